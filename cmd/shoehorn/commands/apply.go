@@ -0,0 +1,234 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/apply"
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyFiles     []string
+	applyRecursive bool
+	applyDryRun    bool
+	applyPrune     bool
+	applyYes       bool
+)
+
+// applyCmd pushes manifest files into the catalog
+var applyCmd = &cobra.Command{
+	Use:   "apply -f <file|dir>",
+	Short: "Create, update and prune catalog entities from manifest files",
+	Long: `Apply Shoehorn manifests to the catalog, so a repository can own its catalog
+entries declaratively.
+
+Every file is validated by the server and compared with the live entity to
+build a plan of creates and updates. Nothing is applied unless every manifest
+is valid. Directories are read for *.yml and *.yaml files; -r descends into
+subdirectories. Files found there that are not Shoehorn manifests (no
+schemaVersion or service), such as CI workflows, are skipped.
+
+With --prune, entities owned by the teams named in the manifests that no
+manifest describes are deleted. Deleting asks for confirmation unless --yes
+is given. The plan is always built from the server, never from the local
+cache, so pruning does not act on stale ownership data.
+
+Examples:
+  shoehorn apply -f .shoehorn/
+  shoehorn apply -f . -r --dry-run
+  shoehorn apply -f .shoehorn/ --prune --yes
+  shoehorn apply -f .shoehorn/ --dry-run -o json`,
+	Args: cobra.NoArgs,
+	RunE: runApply,
+}
+
+func runApply(_ *cobra.Command, _ []string) error {
+	files, err := manifest.Files(applyFiles, applyRecursive, func(_ string, data []byte) bool {
+		shoehorn, _ := manifest.Sniff(data)
+		return shoehorn
+	})
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no manifest files (*.yml, *.yaml) found in %s", strings.Join(applyFiles, ", "))
+	}
+
+	sources := make([]apply.Source, len(files))
+	for i, file := range files {
+		data, err := readManifestFile(file)
+		if err != nil {
+			return err
+		}
+		sources[i] = apply.Source{Name: file, Data: data}
+	}
+
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	result, spinErr := tui.RunSpinner("Planning...", func() (any, error) {
+		return apply.BuildPlan(ctx, client, sources, applyPrune)
+	})
+	if spinErr != nil {
+		return spinErr
+	}
+	plan := result.(*apply.Plan)
+	plan.DryRun = applyDryRun
+
+	mode := ui.DetectMode(false, NoInteractive(), OutputFormat())
+	structured := mode == ui.ModeJSON || mode == ui.ModeYAML
+	if !structured {
+		renderApplyPlan(plan)
+	}
+
+	if plan.Summary.Invalid > 0 {
+		if structured {
+			if err := renderStructured(mode, plan); err != nil {
+				return err
+			}
+		}
+		return ui.WithExitCode(ui.ExitValidation,
+			fmt.Errorf("%d of %d manifests are invalid; nothing was applied", plan.Summary.Invalid, len(files)))
+	}
+
+	pending := plan.Summary.Create + plan.Summary.Update + plan.Summary.Delete
+	if !applyDryRun && pending > 0 {
+		if plan.Summary.Delete > 0 && !applyYes {
+			ok, err := confirm(fmt.Sprintf("Delete %d entities from the catalog?", plan.Summary.Delete))
+			if err != nil {
+				return err
+			}
+			if !ok {
+				return errDeclined
+			}
+		}
+		if _, spinErr := tui.RunSpinner("Applying...", func() (any, error) {
+			return nil, executeApplyPlan(ctx, client, plan)
+		}); spinErr != nil {
+			return spinErr
+		}
+	}
+
+	if structured {
+		if err := renderStructured(mode, plan); err != nil {
+			return err
+		}
+	} else if !applyDryRun && pending > 0 {
+		renderApplyResult(plan)
+	}
+
+	if plan.Summary.Failed > 0 {
+		return fmt.Errorf("%d of %d changes failed", plan.Summary.Failed, pending)
+	}
+	return nil
+}
+
+// executeApplyPlan applies creates and updates, then deletes. A failed
+// change is recorded and the rest carry on, unless the failure (auth,
+// cancellation) would affect every remaining change.
+func executeApplyPlan(ctx context.Context, client *api.Client, plan *apply.Plan) error {
+	for _, c := range plan.Changes {
+		var err error
+		switch c.Action {
+		case "create", "update":
+			_, err = client.ApplyManifest(ctx, c.Content)
+		case "delete":
+			err = client.DeleteEntity(ctx, c.ID)
+		default:
+			continue
+		}
+		if err != nil {
+			if fatalBatchError(err) {
+				return err
+			}
+			c.Error = err.Error()
+			plan.Summary.Failed++
+		}
+	}
+	return nil
+}
+
+// readManifestFile reads a manifest from a file, or from stdin for "-"
+func readManifestFile(name string) ([]byte, error) {
+	if name == "-" {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+		return data, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return data, nil
+}
+
+func renderStructured(mode ui.OutputMode, v any) error {
+	if mode == ui.ModeYAML {
+		return ui.RenderYAML(v)
+	}
+	return ui.RenderJSON(v)
+}
+
+// renderApplyPlan prints the plan, leaving out unchanged entities
+func renderApplyPlan(plan *apply.Plan) {
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case "create":
+			fmt.Printf("  %s %s %s\n", tui.SuccessStyle.Render("+"), c.ID, tui.MutedStyle.Render("("+c.File+")"))
+		case "update":
+			fmt.Printf("  %s %s %s %s\n", tui.WarnStyle.Render("~"), c.ID, tui.MutedStyle.Render("("+c.File+")"), strings.Join(c.Fields, ", "))
+		case "delete":
+			fmt.Printf("  %s %s\n", tui.ErrorStyle.Render("-"), c.ID)
+		case "invalid":
+			fmt.Printf("  %s %s: %s\n", tui.ErrorStyle.Render("✗"), c.File, c.Error)
+		}
+	}
+
+	s := plan.Summary
+	if s.Invalid > 0 {
+		fmt.Printf("\n%d invalid, %d valid manifests.\n", s.Invalid, len(plan.Changes)-s.Invalid-s.Delete)
+		return
+	}
+	if s.Create+s.Update+s.Delete == 0 {
+		fmt.Printf("No changes; the catalog matches the manifests (%d unchanged).\n", s.Unchanged)
+		return
+	}
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to delete, %d unchanged.\n", s.Create, s.Update, s.Delete, s.Unchanged)
+	if plan.DryRun {
+		fmt.Println("Dry run: nothing was changed.")
+	}
+}
+
+// renderApplyResult prints failed changes and the final tally
+func renderApplyResult(plan *apply.Plan) {
+	for _, c := range plan.Changes {
+		if c.Error != "" {
+			fmt.Printf("  %s %s %s: %s\n", tui.ErrorStyle.Render("✗"), c.Action, c.ID, c.Error)
+		}
+	}
+	s := plan.Summary
+	fmt.Printf("Applied %d of %d changes.\n", s.Create+s.Update+s.Delete-s.Failed, s.Create+s.Update+s.Delete)
+}
+
+func init() {
+	applyCmd.Flags().StringSliceVarP(&applyFiles, "filename", "f", nil, "manifest file, directory or glob to apply (repeatable; - for stdin)")
+	applyCmd.Flags().BoolVarP(&applyRecursive, "recursive", "r", false, "include manifests in subdirectories")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false, "show the plan without changing anything")
+	applyCmd.Flags().BoolVar(&applyPrune, "prune", false, "delete entities of the manifests' owners that no manifest describes")
+	applyCmd.Flags().BoolVarP(&applyYes, "yes", "y", false, "delete without asking for confirmation")
+	applyCmd.MarkFlagRequired("filename")
+	rootCmd.AddCommand(applyCmd)
+}
@@ -3,18 +3,27 @@ package commands
 import (
 	"context"
 	"fmt"
+	"os/exec"
+	"runtime"
 	"strings"
 	"time"
 
 	"github.com/shoehorn-dev/cli/pkg/api"
 	"github.com/shoehorn-dev/cli/pkg/config"
 	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	serverURL string
-	patToken  string
+	serverURL     string
+	patToken      string
+	loginWeb      bool
+	loginIssuer   string
+	loginClientID string
+
+	logoutAll               bool
+	logoutKeepServerSession bool
 )
 
 // authCmd represents the auth command group
@@ -28,9 +37,19 @@ var authCmd = &cobra.Command{
 var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to Shoehorn",
-	Long: `Authenticate with the Shoehorn platform using a Personal Access Token.
+	Long: `Authenticate with the Shoehorn platform.
 
-Create a PAT in the Shoehorn UI under Settings > API Keys, then run:
+Without --token, login uses the server's OIDC issuer. The default device flow
+prints a code to enter in a browser on any machine, so it works over SSH and
+in containers. --web opens a browser on this machine instead (PKCE with a
+loopback redirect) and gives up if login is not completed within five minutes.
+
+With --token, login uses a Personal Access Token. Create one in the Shoehorn UI
+under Settings > API Keys.
+
+Examples:
+  shoehorn auth login --server https://shoehorn.example.com
+  shoehorn auth login --server https://shoehorn.example.com --web
   shoehorn auth login --server http://localhost:8080 --token shp_your_token`,
 	RunE: runLogin,
 }
@@ -47,13 +66,30 @@ var statusCmd = &cobra.Command{
 var logoutCmd = &cobra.Command{
 	Use:   "logout",
 	Short: "Logout from Shoehorn",
-	Long:  `Clear local credentials. Note: tokens are not revoked on the server.`,
-	RunE:  runLogout,
+	Long: `Revoke the current profile's credentials on the server and clear them locally.
+
+For a PAT, the token itself is revoked. For a browser (OIDC) login, the refresh
+token is revoked at the identity provider, ending the session. Local
+credentials are cleared even if revocation fails; the command then exits
+non-zero so the token can be revoked by hand.
+
+Examples:
+  shoehorn auth logout
+  shoehorn auth logout --all
+  shoehorn auth logout --keep-server-session`,
+	Args: cobra.NoArgs,
+	RunE: runLogout,
 }
 
 func init() {
-	loginCmd.Flags().StringVar(&serverURL, "server", "http://localhost:8080", "Shoehorn API server URL")
+	loginCmd.Flags().StringVar(&serverURL, "server", "http://localhost:8080", "Shoehorn API server URL (defaults to the profile's server)")
 	loginCmd.Flags().StringVar(&patToken, "token", "", "Personal Access Token (shp_xxx)")
+	loginCmd.Flags().BoolVar(&loginWeb, "web", false, "Log in with a browser on this machine instead of the device flow")
+	loginCmd.Flags().StringVar(&loginIssuer, "issuer", "", "OIDC issuer URL (default: advertised by the server)")
+	loginCmd.Flags().StringVar(&loginClientID, "client-id", "", "OIDC client ID (default: advertised by the server)")
+
+	logoutCmd.Flags().BoolVar(&logoutAll, "all", false, "Log out of every profile")
+	logoutCmd.Flags().BoolVar(&logoutKeepServerSession, "keep-server-session", false, "Clear local credentials without revoking them on the server")
 
 	authCmd.AddCommand(loginCmd)
 	authCmd.AddCommand(statusCmd)
@@ -63,11 +99,21 @@ func init() {
 }
 
 func runLogin(cmd *cobra.Command, args []string) error {
-	if patToken == "" {
-		return fmt.Errorf("a Personal Access Token is required\n\nUsage:\n  shoehorn auth login --server <url> --token <PAT>")
+	server := serverURL
+	if !cmd.Flags().Changed("server") {
+		// Re-login to an existing profile keeps its server
+		if cfg, err := config.Load(); err == nil {
+			if p, err := cfg.GetCurrentProfile(); err == nil && p.Server != "" {
+				server = p.Server
+			}
+		}
+	}
+	server = NormalizeServerURL(server)
+
+	if patToken != "" {
+		return runLoginWithPAT(server, patToken)
 	}
-	serverURL = NormalizeServerURL(serverURL)
-	return runLoginWithPAT(serverURL, patToken)
+	return runLoginWithOIDC(server)
 }
 
 // runLoginWithPAT authenticates using a Personal Access Token
@@ -83,40 +129,157 @@ func runLoginWithPAT(server, token string) error {
 	})
 	if err != nil {
 		fmt.Println(tui.ErrorBox("Authentication Failed", err.Error()))
-		return nil
+		return ui.Reported(err)
+	}
+
+	me := result.(*api.MeResponse)
+
+	auth := &config.Auth{
+		ProviderType: "pat",
+		Issuer:       server,
+		AccessToken:  token,
+	}
+	if err := saveLogin(server, auth, me); err != nil {
+		return err
+	}
+
+	printLoginSuccess("Authenticated with PAT", server, me)
+	return nil
+}
+
+// runLoginWithOIDC authenticates with the device authorization flow, or the
+// loopback PKCE flow when --web is set, against the server's OIDC issuer.
+func runLoginWithOIDC(server string) error {
+	ctx := context.Background()
+
+	issuer, clientID := loginIssuer, loginClientID
+	var scopes []string
+	if issuer == "" || clientID == "" {
+		result, err := tui.RunSpinner("Discovering login settings...", func() (any, error) {
+			return api.NewClient(server).GetCLIAuthConfig(ctx)
+		})
+		if err != nil {
+			return fmt.Errorf("server does not advertise OIDC login settings (%w)\nUse --issuer and --client-id, or log in with --token <PAT>", err)
+		}
+		advertised := result.(*api.CLIAuthConfig)
+		if issuer == "" {
+			issuer = advertised.Issuer
+		}
+		if clientID == "" {
+			clientID = advertised.ClientID
+		}
+		scopes = advertised.Scopes
+	}
+	if issuer == "" || clientID == "" {
+		return fmt.Errorf("no OIDC issuer or client ID available; use --issuer and --client-id, or log in with --token <PAT>")
+	}
+
+	oauth := api.NewOAuthClient(issuer, clientID, scopes)
+
+	var tok *api.TokenResponse
+	if loginWeb {
+		t, err := oauth.LoopbackLogin(ctx, func(authURL string) error {
+			fmt.Println("Opening your browser to complete login. If it does not open, visit:")
+			fmt.Printf("\n  %s\n\n", authURL)
+			_ = openBrowser(authURL)
+			return nil
+		})
+		if err != nil {
+			fmt.Println(tui.ErrorBox("Authentication Failed", err.Error()))
+			return ui.Reported(err)
+		}
+		tok = t
+	} else {
+		result, err := tui.RunSpinner("Requesting device code...", func() (any, error) {
+			return oauth.StartDeviceAuthorization(ctx)
+		})
+		if err != nil {
+			fmt.Println(tui.ErrorBox("Authentication Failed", err.Error()))
+			return ui.Reported(err)
+		}
+		da := result.(*api.DeviceAuthorization)
+
+		body := fmt.Sprintf("%s  %s\n%s  %s",
+			tui.LabelStyle.Render("Visit"), da.VerificationURI,
+			tui.LabelStyle.Render("Code"), tui.TitleStyle.Render(da.UserCode),
+		)
+		if da.VerificationURIComplete != "" {
+			body += fmt.Sprintf("\n\n%s", tui.MutedStyle.Render("Or open: "+da.VerificationURIComplete))
+		}
+		fmt.Println(tui.BoxStyle.Render(body))
+
+		result, err = tui.RunSpinner("Waiting for approval in the browser...", func() (any, error) {
+			return oauth.PollDeviceToken(ctx, da)
+		})
+		if err != nil {
+			fmt.Println(tui.ErrorBox("Authentication Failed", err.Error()))
+			return ui.Reported(err)
+		}
+		tok = result.(*api.TokenResponse)
 	}
 
+	client := api.NewClient(server)
+	client.SetToken(tok.AccessToken)
+	result, err := tui.RunSpinner("Fetching user info...", func() (any, error) {
+		return client.GetMe(ctx)
+	})
+	if err != nil {
+		fmt.Println(tui.ErrorBox("Authentication Failed", err.Error()))
+		return ui.Reported(err)
+	}
 	me := result.(*api.MeResponse)
 
-	// Save config
+	auth := &config.Auth{
+		ProviderType: "oidc",
+		Issuer:       issuer,
+		ClientID:     clientID,
+		AccessToken:  tok.AccessToken,
+		RefreshToken: tok.RefreshToken,
+		TokenType:    tok.TokenType,
+		ExpiresAt:    tok.ExpiresAt(),
+	}
+	if err := saveLogin(server, auth, me); err != nil {
+		return err
+	}
+
+	printLoginSuccess("Authenticated", server, me)
+	return nil
+}
+
+// saveLogin stores the server and credentials on the active profile
+func saveLogin(server string, auth *config.Auth, me *api.MeResponse) error {
 	cfg, err := config.Load()
 	if err != nil {
 		return fmt.Errorf("load config: %w", err)
 	}
 
-	currentProfile := cfg.Profiles[cfg.CurrentProfile]
+	profileName := cfg.ActiveProfileName()
+	currentProfile := cfg.Profiles[profileName]
 	if currentProfile == nil {
-		currentProfile = &config.Profile{Name: cfg.CurrentProfile}
-		cfg.Profiles[cfg.CurrentProfile] = currentProfile
+		currentProfile = &config.Profile{Name: profileName}
+		cfg.SetProfile(profileName, currentProfile)
 	}
 
-	currentProfile.Server = server
-	currentProfile.Auth = &config.Auth{
-		ProviderType: "pat",
-		Issuer:       server,
-		AccessToken:  token,
-		User: &config.User{
-			Email:    me.Email,
-			Name:     me.Name,
-			TenantID: me.TenantID,
-		},
+	auth.User = &config.User{
+		Email:    me.Email,
+		Name:     me.Name,
+		TenantID: me.TenantID,
+	}
+	if currentProfile.Auth != nil {
+		// Reuse the credential slot so the old secret is overwritten, not orphaned
+		auth.CredentialRef = currentProfile.Auth.CredentialRef
 	}
+	currentProfile.Server = server
+	currentProfile.Auth = auth
 
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("save config: %w", err)
 	}
+	return nil
+}
 
-	// Success panel
+// printLoginSuccess renders the post-login identity panel
+func printLoginSuccess(title, server string, me *api.MeResponse) {
 	var lines []string
 	if me.Name != "" {
 		lines = append(lines, fmt.Sprintf("%s  %s", tui.LabelStyle.Render("Name"), me.Name))
@@ -132,8 +295,21 @@ func runLoginWithPAT(server, token string) error {
 	if len(me.Roles) > 0 {
 		lines = append(lines, fmt.Sprintf("%s  %s", tui.LabelStyle.Render("Roles"), strings.Join(me.Roles, ", ")))
 	}
-	fmt.Println(tui.SuccessBox("Authenticated with PAT", strings.Join(lines, "\n")))
-	return nil
+	fmt.Println(tui.SuccessBox(title, strings.Join(lines, "\n")))
+}
+
+// openBrowser opens url in the user's default browser
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
 }
 
 func runStatus(cmd *cobra.Command, args []string) error {
@@ -147,18 +323,20 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	fmt.Printf("Profile: %s\n", cfg.CurrentProfile)
+	fmt.Printf("Profile: %s\n", cfg.ActiveProfileName())
 	fmt.Printf("Server:  %s\n", currentProfile.Server)
 
 	if !cfg.IsAuthenticated() {
 		fmt.Println("Status:  Not authenticated")
 		fmt.Println()
-		fmt.Println("Run 'shoehorn auth login --token <PAT>' to authenticate")
+		fmt.Println("Run 'shoehorn auth login' to authenticate")
 		return nil
 	}
 
 	if cfg.IsPATAuth() {
 		fmt.Println("Status:  Authenticated (PAT)")
+	} else if currentProfile.Auth.ProviderType == "oidc" {
+		fmt.Println("Status:  Authenticated (OIDC)")
 	} else {
 		fmt.Println("Status:  Authenticated")
 	}
@@ -178,10 +356,14 @@ func runStatus(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	if cfg.IsTokenExpired() {
+	if cfg.IsTokenExpired() && currentProfile.Auth.RefreshToken != "" {
+		fmt.Println("Token:   Expired (renewed automatically on the next request)")
+	} else if cfg.IsTokenExpired() {
 		fmt.Println("Token:   Expired (use 'shoehorn auth login' to refresh)")
 	} else if cfg.IsPATAuth() {
 		fmt.Println("Token:   Valid (PAT, no expiry)")
+	} else if currentProfile.Auth.ExpiresAt.IsZero() {
+		fmt.Println("Token:   Valid (no expiry)")
 	} else {
 		timeUntilExpiry := time.Until(currentProfile.Auth.ExpiresAt)
 		fmt.Printf("Token:   Valid (expires in %s)\n", formatDuration(timeUntilExpiry))
@@ -211,19 +393,59 @@ func runLogout(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("failed to load config: %w", err)
 	}
 
-	currentProfile, err := cfg.GetCurrentProfile()
-	if err != nil {
+	names := []string{cfg.ActiveProfileName()}
+	if logoutAll {
+		names = cfg.ProfileNames()
+	} else if _, err := cfg.GetCurrentProfile(); err != nil {
 		return err
 	}
 
-	currentProfile.Auth = nil
+	ctx := context.Background()
+	var loggedOut, unrevoked []string
+	for _, name := range names {
+		profile := cfg.Profiles[name]
+		if profile == nil || !profile.Auth.HasCredentials() {
+			continue
+		}
+
+		if !logoutKeepServerSession {
+			// Revocation needs the secret, which may still be in the credential store
+			if err := cfg.LoadCredentials(name); err != nil {
+				return err
+			}
+			if err := api.RevokeCredentials(ctx, profile.Server, profile.Auth); err != nil {
+				ui.RenderWarning(fmt.Sprintf("profile %q: %v", name, err))
+				unrevoked = append(unrevoked, name)
+			}
+		}
+
+		profile.Auth = nil
+		loggedOut = append(loggedOut, name)
+	}
 
 	if err := cfg.Save(); err != nil {
 		return fmt.Errorf("failed to save config: %w", err)
 	}
 
-	fmt.Printf("Logged out from profile: %s\n", cfg.CurrentProfile)
-	fmt.Println("Note: Tokens are not revoked on the server. They will expire naturally.")
+	if len(loggedOut) == 0 {
+		fmt.Println("No profiles were logged in")
+		return nil
+	}
+	// Cached catalog data belongs to the session that fetched it
+	clearProfileCache(loggedOut...)
+
+	for _, name := range loggedOut {
+		fmt.Printf("Logged out from profile: %s\n", name)
+	}
+	if logoutKeepServerSession {
+		fmt.Println("Note: Tokens were not revoked on the server (--keep-server-session).")
+	}
+
+	// Local credentials are gone either way, but a token that is still valid on
+	// the server must not go unnoticed.
+	if len(unrevoked) > 0 {
+		return fmt.Errorf("could not revoke credentials on the server for profile(s): %s\nRevoke them manually in the Shoehorn UI", strings.Join(unrevoked, ", "))
+	}
 	return nil
 }
 
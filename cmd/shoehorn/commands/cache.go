@@ -0,0 +1,143 @@
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/cache"
+	"github.com/shoehorn-dev/cli/pkg/config"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd is the parent command for the local response cache
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage the local API response cache",
+	Long: `Catalog reads (get, search, forge molds) are cached per profile in the cache
+directory next to the config file (by default ~/.shoehorn/cache).
+
+Cached responses are revalidated with the server on every read unless
+--max-age allows using them as-is. With --offline, or when the server is
+unreachable, cached data is shown with a note saying how old it is.
+
+Examples:
+  shoehorn get entities --offline
+  shoehorn get entities --max-age 10m
+  shoehorn cache stats
+  shoehorn cache clear staging`,
+}
+
+// ─── cache stats ────────────────────────────────────────────────────────────
+
+var cacheStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show cache size and age per profile",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheStats,
+}
+
+// cacheStatsOutput is the JSON/YAML shape of cache stats
+type cacheStatsOutput struct {
+	Dir      string               `json:"dir" yaml:"dir"`
+	Profiles []cache.ProfileStats `json:"profiles" yaml:"profiles"`
+}
+
+func runCacheStats(_ *cobra.Command, _ []string) error {
+	store, err := openCache()
+	if err != nil {
+		return err
+	}
+	stats, err := store.Stats()
+	if err != nil {
+		return err
+	}
+
+	mode := ui.DetectMode(Interactive(), NoInteractive(), OutputFormat())
+	out := cacheStatsOutput{Dir: store.Dir(), Profiles: stats}
+	if mode == ui.ModeJSON {
+		return ui.RenderJSON(out)
+	}
+	if mode == ui.ModeYAML {
+		return ui.RenderYAML(out)
+	}
+
+	fmt.Printf("Cache directory: %s\n\n", store.Dir())
+	if len(stats) == 0 {
+		fmt.Println("The cache is empty.")
+		return nil
+	}
+
+	colNames := []string{"Profile", "Entries", "Size", "Oldest", "Newest"}
+	rows := make([][]string, len(stats))
+	for i, s := range stats {
+		rows[i] = []string{
+			s.Profile,
+			strconv.Itoa(s.Entries),
+			formatBytes(s.Bytes),
+			formatCacheTime(s.Oldest),
+			formatCacheTime(s.Newest),
+		}
+	}
+	ui.RenderTable(colNames, rows)
+	return nil
+}
+
+// formatCacheTime formats an entry time with its age, or "-" when unset
+func formatCacheTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return fmt.Sprintf("%s (%s ago)", t.Local().Format("2006-01-02 15:04"), formatDuration(time.Since(t)))
+}
+
+// ─── cache clear ────────────────────────────────────────────────────────────
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear [profile...]",
+	Short: "Delete cached responses (all profiles unless named)",
+	RunE:  runCacheClear,
+}
+
+func runCacheClear(_ *cobra.Command, args []string) error {
+	store, err := openCache()
+	if err != nil {
+		return err
+	}
+	removed, err := store.Clear(args...)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Removed %d cached response(s).\n", removed)
+	return nil
+}
+
+// openCache returns the cache store next to the active config file
+func openCache() (*cache.Store, error) {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil, fmt.Errorf("locate cache directory: %w", err)
+	}
+	return cache.New(dir), nil
+}
+
+// clearProfileCache removes cached catalog data for profiles, warning on failure
+func clearProfileCache(profiles ...string) {
+	if len(profiles) == 0 {
+		return
+	}
+	store, err := openCache()
+	if err == nil {
+		_, err = store.Clear(profiles...)
+	}
+	if err != nil {
+		ui.RenderWarning(fmt.Sprintf("could not clear cached data: %v", err))
+	}
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatsCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+	rootCmd.AddCommand(cacheCmd)
+}
@@ -0,0 +1,303 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/apply"
+	"github.com/shoehorn-dev/cli/pkg/config"
+	"github.com/shoehorn-dev/cli/pkg/snapshot"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// catalogCmd is the parent command for whole-catalog operations
+var catalogCmd = &cobra.Command{
+	Use:   "catalog",
+	Short: "Export and import catalog snapshots",
+	Long: `Export the whole catalog of a profile to a versioned snapshot, or re-apply
+the entities of a snapshot through the manifests API.
+
+Snapshots hold every entity with its resources, status and scorecard, plus
+teams (with members), users and groups. Formats:
+  json     one JSON document (default)
+  ndjson   one JSON record per line, for streaming and grep
+  tar.gz   snapshot.json plus manifests/<id>.yml for each entity
+
+Exports always read from the server, never from the local cache, and so do
+not work with --offline.
+
+Examples:
+  shoehorn catalog export -f catalog.json
+  shoehorn catalog export -f catalog.tar.gz
+  shoehorn catalog export --format ndjson | gzip > catalog.ndjson.gz
+  shoehorn catalog import catalog.json --dry-run
+  shoehorn --profile local catalog import catalog.tar.gz --yes`,
+}
+
+// ─── catalog export ─────────────────────────────────────────────────────────
+
+var (
+	exportFile        string
+	exportFormat      string
+	exportConcurrency int
+)
+
+var catalogExportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Write a snapshot of the whole catalog",
+	Args:  cobra.NoArgs,
+	RunE:  runCatalogExport,
+}
+
+func runCatalogExport(cmd *cobra.Command, _ []string) error {
+	format := snapshot.FormatFromPath(exportFile)
+	if cmd.Flags().Changed("format") {
+		f, err := snapshot.ParseFormat(exportFormat)
+		if err != nil {
+			return err
+		}
+		format = f
+	}
+	if exportFile == "" && format == snapshot.FormatTarball && term.IsTerminal(int(os.Stdout.Fd())) {
+		return fmt.Errorf("refusing to write a tarball to the terminal; use -f <file> or redirect stdout")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	profile, err := cfg.GetCurrentProfile()
+	if err != nil {
+		return err
+	}
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+
+	result, spinErr := tui.RunSpinner("Exporting catalog...", func() (any, error) {
+		return snapshot.Export(context.Background(), client, snapshot.ExportOptions{Concurrency: exportConcurrency})
+	})
+	if spinErr != nil {
+		return fmt.Errorf("export catalog: %w", spinErr)
+	}
+	snap := result.(*snapshot.Snapshot)
+	snap.Profile = cfg.ActiveProfileName()
+	snap.Server = profile.Server
+
+	var out io.Writer = os.Stdout
+	if exportFile != "" {
+		f, err := os.Create(exportFile)
+		if err != nil {
+			return fmt.Errorf("create snapshot file: %w", err)
+		}
+		defer f.Close()
+		out = f
+	}
+	if err := snapshot.Write(out, snap, format); err != nil {
+		if exportFile != "" {
+			os.Remove(exportFile)
+		}
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+
+	dest := exportFile
+	if dest == "" {
+		dest = "stdout"
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d entities, %d teams, %d users and %d groups to %s (%s)\n",
+		len(snap.Entities), len(snap.Teams), len(snap.Users), len(snap.Groups), dest, format)
+	return nil
+}
+
+// ─── catalog import ─────────────────────────────────────────────────────────
+
+var (
+	importDryRun bool
+	importYes    bool
+)
+
+var catalogImportCmd = &cobra.Command{
+	Use:   "import <snapshot>",
+	Short: "Re-apply the entities of a snapshot",
+	Long: `Re-apply every entity in a snapshot to the active profile through the
+manifests API. Use "-" to read the snapshot from stdin.
+
+With --dry-run each manifest is validated by the server and compared with the
+live catalog to show whether it would be created or updated; nothing is written.
+
+Teams, users and groups are kept in snapshots for audits but are not imported:
+they are managed by your identity provider.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCatalogImport,
+}
+
+// importResult is the outcome for one entity
+type importResult struct {
+	ID     string `json:"id" yaml:"id"`
+	Action string `json:"action" yaml:"action"` // create/update (dry run), created/updated/unchanged, invalid, failed
+	Error  string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+func runCatalogImport(_ *cobra.Command, args []string) error {
+	snap, err := snapshot.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("read snapshot: %w", err)
+	}
+	if len(snap.Entities) == 0 {
+		fmt.Println("The snapshot has no entities to import.")
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+
+	if !importDryRun && !importYes {
+		ok, err := confirm(fmt.Sprintf("Apply %d entities from %s to profile %q?", len(snap.Entities), args[0], cfg.ActiveProfileName()))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errDeclined
+		}
+	}
+
+	msg := "Applying manifests..."
+	if importDryRun {
+		msg = "Validating manifests..."
+	}
+	result, spinErr := tui.RunSpinner(msg, func() (any, error) {
+		return importEntities(context.Background(), client, snap.Entities, importDryRun)
+	})
+	if spinErr != nil {
+		return spinErr
+	}
+	results := result.([]importResult)
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+
+	mode := ui.DetectMode(false, NoInteractive(), OutputFormat())
+	switch mode {
+	case ui.ModeJSON:
+		if err := ui.RenderJSON(results); err != nil {
+			return err
+		}
+	case ui.ModeYAML:
+		if err := ui.RenderYAML(results); err != nil {
+			return err
+		}
+	default:
+		rows := make([][]string, len(results))
+		for i, r := range results {
+			rows[i] = []string{r.ID, r.Action, r.Error}
+		}
+		ui.RenderTable([]string{"Entity", "Action", "Error"}, rows)
+		fmt.Println()
+		if importDryRun {
+			fmt.Printf("Dry run: %d of %d entities would be applied; nothing was changed.\n", len(results)-failed, len(results))
+		} else {
+			fmt.Printf("Applied %d of %d entities.\n", len(results)-failed, len(results))
+		}
+	}
+
+	if failed > 0 {
+		err := fmt.Errorf("%d of %d entities could not be applied", failed, len(results))
+		if importDryRun {
+			return ui.WithExitCode(ui.ExitValidation, err)
+		}
+		return err
+	}
+	return nil
+}
+
+// importEntities validates (dry run) or applies the manifest of each entity.
+// Per-entity failures are recorded in the results; only a failure that makes
+// further requests pointless (authentication, cancellation) aborts.
+func importEntities(ctx context.Context, client *api.Client, entities []*snapshot.Entity, dryRun bool) ([]importResult, error) {
+	results := make([]importResult, 0, len(entities))
+	for _, e := range entities {
+		r := importResult{ID: e.ID}
+		content, err := e.Manifest().Marshal()
+		if err != nil {
+			r.Action, r.Error = "invalid", err.Error()
+			results = append(results, r)
+			continue
+		}
+
+		if dryRun {
+			r.Action, err = planEntity(ctx, client, e.ID, string(content))
+		} else {
+			var resp *api.ApplyManifestResponse
+			if resp, err = client.ApplyManifest(ctx, string(content)); err == nil {
+				r.Action = resp.Action
+			}
+		}
+		if err != nil {
+			if fatalBatchError(err) {
+				return nil, err
+			}
+			if r.Action == "" {
+				r.Action = "failed"
+			}
+			r.Error = err.Error()
+		}
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// planEntity validates a manifest and reports whether applying it would
+// create or update the entity
+func planEntity(ctx context.Context, client *api.Client, id, content string) (string, error) {
+	v, err := client.ValidateManifest(ctx, content)
+	if err != nil {
+		return "", err
+	}
+	if !v.Valid {
+		return "invalid", fmt.Errorf("%s", apply.ValidationMessage(v.Errors))
+	}
+
+	if _, err := client.GetEntity(ctx, id); err != nil {
+		if api.IsNotFound(err) {
+			return "create", nil
+		}
+		return "", err
+	}
+	return "update", nil
+}
+
+// fatalBatchError reports whether err would fail every remaining request of
+// a batch, so there is no point carrying on
+func fatalBatchError(err error) bool {
+	return ui.ExitCode(err) == ui.ExitAuthRequired || ui.ExitCode(err) == ui.ExitCancelled
+}
+
+func init() {
+	catalogExportCmd.Flags().StringVarP(&exportFile, "file", "f", "", "write the snapshot to this file (default: stdout)")
+	catalogExportCmd.Flags().StringVar(&exportFormat, "format", "json", "snapshot format: json, ndjson, or tar.gz (default: from the file extension)")
+	catalogExportCmd.Flags().IntVar(&exportConcurrency, "concurrency", snapshot.DefaultConcurrency, "entities fetched in parallel")
+
+	catalogImportCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "validate and show the plan without changing anything")
+	catalogImportCmd.Flags().BoolVarP(&importYes, "yes", "y", false, "apply without asking for confirmation")
+
+	catalogCmd.AddCommand(catalogExportCmd)
+	catalogCmd.AddCommand(catalogImportCmd)
+	rootCmd.AddCommand(catalogCmd)
+}
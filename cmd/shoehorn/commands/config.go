@@ -0,0 +1,352 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
+	"github.com/shoehorn-dev/cli/pkg/credentials"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// configCmd is the parent command for profile management
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Manage CLI configuration and profiles",
+	Long: `View and edit the CLI configuration file without editing YAML by hand.
+
+Examples:
+  shoehorn config get-profiles
+  shoehorn config set-profile staging --server https://staging.shoehorn.dev
+  shoehorn config use-profile staging
+  shoehorn config rename-profile staging stage
+  shoehorn config delete-profile stage
+  shoehorn config set-credential-store keyring
+  shoehorn config view`,
+}
+
+// ─── config get-profiles ────────────────────────────────────────────────────
+
+var configGetProfilesCmd = &cobra.Command{
+	Use:     "get-profiles",
+	Aliases: []string{"profiles"},
+	Short:   "List all profiles",
+	Args:    cobra.NoArgs,
+	RunE:    runConfigGetProfiles,
+}
+
+// profileSummary is the machine-readable form of a profile listing
+type profileSummary struct {
+	Name          string `json:"name" yaml:"name"`
+	DisplayName   string `json:"display_name" yaml:"display_name"`
+	Server        string `json:"server" yaml:"server"`
+	Current       bool   `json:"current" yaml:"current"`
+	Authenticated bool   `json:"authenticated" yaml:"authenticated"`
+	AuthType      string `json:"auth_type,omitempty" yaml:"auth_type,omitempty"`
+	User          string `json:"user,omitempty" yaml:"user,omitempty"`
+}
+
+func runConfigGetProfiles(_ *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	active := cfg.ActiveProfileName()
+	summaries := make([]profileSummary, 0, len(cfg.Profiles))
+	for _, name := range cfg.ProfileNames() {
+		p := cfg.Profiles[name]
+		if p == nil {
+			continue
+		}
+		s := profileSummary{
+			Name:        name,
+			DisplayName: p.Name,
+			Server:      p.Server,
+			Current:     name == active,
+		}
+		if p.Auth != nil {
+			s.Authenticated = p.Auth.HasCredentials()
+			s.AuthType = p.Auth.ProviderType
+			if p.Auth.User != nil {
+				s.User = p.Auth.User.Email
+				if s.User == "" {
+					s.User = p.Auth.User.Name
+				}
+			}
+		}
+		summaries = append(summaries, s)
+	}
+
+	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
+	if mode == ui.ModeJSON {
+		return ui.RenderJSON(summaries)
+	}
+	if mode == ui.ModeYAML {
+		return ui.RenderYAML(summaries)
+	}
+
+	rows := make([][]string, len(summaries))
+	for i, s := range summaries {
+		current := ""
+		if s.Current {
+			current = "*"
+		}
+		auth := "-"
+		if s.Authenticated {
+			auth = s.AuthType
+		}
+		user := s.User
+		if user == "" {
+			user = "-"
+		}
+		rows[i] = []string{current, s.Name, s.Server, auth, user}
+	}
+	ui.RenderTable([]string{"Current", "Name", "Server", "Auth", "User"}, rows)
+	return nil
+}
+
+// ─── config use-profile ─────────────────────────────────────────────────────
+
+var configUseProfileCmd = &cobra.Command{
+	Use:   "use-profile <name>",
+	Short: "Switch the current profile",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUseProfile,
+}
+
+func runConfigUseProfile(_ *cobra.Command, args []string) error {
+	name := args[0]
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.UseProfile(name); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	ui.RenderSuccess(fmt.Sprintf("Switched to profile %q", name))
+	return nil
+}
+
+// ─── config set-profile ─────────────────────────────────────────────────────
+
+var (
+	setProfileServer      string
+	setProfileDisplayName string
+	setProfileUse         bool
+	setProfileMaxRetries  int
+	setProfileMaxDelay    time.Duration
+)
+
+var configSetProfileCmd = &cobra.Command{
+	Use:   "set-profile <name>",
+	Short: "Create or update a profile",
+	Long: `Create a profile, or update the server and display name of an existing one.
+Existing credentials are kept unless the server changes.
+
+--max-retries and --max-delay set the retry budget for API requests made with
+this profile; the global --retries and --retry-max-delay flags override them.
+
+Examples:
+  shoehorn config set-profile prod --server https://api.shoehorn.dev --name Production
+  shoehorn config set-profile staging --server staging.shoehorn.dev --use
+  shoehorn config set-profile prod --max-retries 6 --max-delay 1m`,
+	Args: cobra.ExactArgs(1),
+	RunE: runConfigSetProfile,
+}
+
+func runConfigSetProfile(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	p, exists := cfg.Profiles[name]
+	if !exists || p == nil {
+		if setProfileServer == "" {
+			return fmt.Errorf("--server is required when creating a new profile")
+		}
+		p = &config.Profile{Name: name}
+		cfg.SetProfile(name, p)
+	}
+
+	if cmd.Flags().Changed("server") {
+		server := NormalizeServerURL(setProfileServer)
+		if p.Server != "" && p.Server != server && p.Auth != nil {
+			// Credentials are bound to the server they were issued by
+			p.Auth = nil
+			ui.RenderWarning(fmt.Sprintf("server changed; credentials for profile %q were cleared", name))
+		}
+		p.Server = server
+	}
+	if cmd.Flags().Changed("name") {
+		p.Name = setProfileDisplayName
+	}
+	if cmd.Flags().Changed("max-retries") || cmd.Flags().Changed("max-delay") {
+		if p.Retry == nil {
+			p.Retry = &config.RetrySettings{}
+		}
+		if cmd.Flags().Changed("max-retries") {
+			if setProfileMaxRetries < 0 {
+				return fmt.Errorf("--max-retries must be 0 or more")
+			}
+			p.Retry.MaxRetries = &setProfileMaxRetries
+		}
+		if cmd.Flags().Changed("max-delay") {
+			if setProfileMaxDelay <= 0 {
+				return fmt.Errorf("--max-delay must be positive")
+			}
+			p.Retry.MaxDelay = setProfileMaxDelay
+		}
+	}
+	if setProfileUse {
+		cfg.CurrentProfile = name
+	}
+
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	verb := "Updated"
+	if !exists {
+		verb = "Created"
+	}
+	ui.RenderSuccess(fmt.Sprintf("%s profile %q (%s)", verb, name, p.Server))
+	return nil
+}
+
+// ─── config rename-profile ──────────────────────────────────────────────────
+
+var configRenameProfileCmd = &cobra.Command{
+	Use:   "rename-profile <old-name> <new-name>",
+	Short: "Rename a profile",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigRenameProfile,
+}
+
+func runConfigRenameProfile(_ *cobra.Command, args []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.RenameProfile(args[0], args[1]); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	ui.RenderSuccess(fmt.Sprintf("Renamed profile %q to %q", args[0], args[1]))
+	return nil
+}
+
+// ─── config delete-profile ──────────────────────────────────────────────────
+
+var configDeleteProfileCmd = &cobra.Command{
+	Use:   "delete-profile <name>",
+	Short: "Delete a profile and its local credentials",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigDeleteProfile,
+}
+
+func runConfigDeleteProfile(_ *cobra.Command, args []string) error {
+	name := args[0]
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.DeleteProfile(name); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	ui.RenderSuccess(fmt.Sprintf("Deleted profile %q", name))
+	return nil
+}
+
+// ─── config view ────────────────────────────────────────────────────────────
+
+var configViewCmd = &cobra.Command{
+	Use:   "view",
+	Short: "Show the configuration with tokens redacted",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigView,
+}
+
+func runConfigView(_ *cobra.Command, _ []string) error {
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+
+	redacted := cfg.Redacted()
+	if ui.DetectMode(interactive, noInteractive, outputFormat) == ui.ModeJSON {
+		return ui.RenderJSON(redacted)
+	}
+	return ui.RenderYAML(redacted)
+}
+
+// ─── config set-credential-store ────────────────────────────────────────────
+
+var configSetCredentialStoreCmd = &cobra.Command{
+	Use:   "set-credential-store <auto|keyring|file|plaintext>",
+	Short: "Choose where tokens are stored and migrate existing ones",
+	Long: `Choose where access and refresh tokens are stored. Existing credentials for
+all profiles are moved to the new backend.
+
+Backends:
+  auto       OS keyring when available, otherwise the encrypted file when it
+             exists or a passphrase is set, otherwise inline (default)
+  keyring    OS keyring (Secret Service, macOS Keychain, Windows Credential Manager)
+  file       age/passphrase-encrypted ~/.shoehorn/credentials.age
+             (passphrase from $SHOEHORN_CREDENTIALS_PASSPHRASE or a prompt)
+  plaintext  tokens inline in config.yaml (not recommended)`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"auto", "keyring", "file", "plaintext"},
+	RunE:      runConfigSetCredentialStore,
+}
+
+func runConfigSetCredentialStore(_ *cobra.Command, args []string) error {
+	kind, err := credentials.ParseKind(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.SetCredentialStore(kind); err != nil {
+		return err
+	}
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+	if credentials.Kind(cfg.CredentialStore) == credentials.KindPlaintext {
+		ui.RenderWarning("tokens are now stored in plaintext in the config file")
+	}
+	ui.RenderSuccess(fmt.Sprintf("Credential store set to %q", cfg.CredentialStore))
+	return nil
+}
+
+func init() {
+	configSetProfileCmd.Flags().StringVar(&setProfileServer, "server", "", "Shoehorn API server URL")
+	configSetProfileCmd.Flags().StringVar(&setProfileDisplayName, "name", "", "Display name for the profile")
+	configSetProfileCmd.Flags().BoolVar(&setProfileUse, "use", false, "Also switch to this profile")
+	configSetProfileCmd.Flags().IntVar(&setProfileMaxRetries, "max-retries", 0, "Retries for idempotent API requests (0 disables)")
+	configSetProfileCmd.Flags().DurationVar(&setProfileMaxDelay, "max-delay", 0, "Longest wait between retries, e.g. 30s")
+
+	configCmd.AddCommand(configGetProfilesCmd)
+	configCmd.AddCommand(configUseProfileCmd)
+	configCmd.AddCommand(configSetProfileCmd)
+	configCmd.AddCommand(configRenameProfileCmd)
+	configCmd.AddCommand(configDeleteProfileCmd)
+	configCmd.AddCommand(configViewCmd)
+	configCmd.AddCommand(configSetCredentialStoreCmd)
+
+	rootCmd.AddCommand(configCmd)
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"golang.org/x/term"
+)
+
+// errDeclined is returned when the user answers no to a confirmation prompt
+var errDeclined = ui.WithExitCode(ui.ExitCancelled, errors.New("cancelled"))
+
+// confirm asks a yes/no question on stderr and reads the answer from stdin.
+// Without a terminal there is nobody to ask, so it fails and points at --yes.
+func confirm(question string) (bool, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return false, fmt.Errorf("%s\nstdin is not a terminal; pass --yes to confirm", question)
+	}
+	fmt.Fprintf(os.Stderr, "%s [y/N]: ", question)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(line)) {
+	case "y", "yes":
+		return true, nil
+	}
+	return false, nil
+}
@@ -3,14 +3,20 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 
 	"github.com/shoehorn-dev/cli/pkg/api"
-	"github.com/shoehorn-dev/cli/pkg/config"
+	"github.com/shoehorn-dev/cli/pkg/convert"
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+	"github.com/shoehorn-dev/cli/pkg/schema"
+	"github.com/shoehorn-dev/cli/pkg/textdiff"
 	"github.com/spf13/cobra"
 )
 
@@ -19,6 +25,11 @@ var (
 	convertOutputType string
 	convertValidate   bool
 	convertRecursive  bool
+	convertRemote     bool
+	convertInPlace    bool
+	convertBackup     bool
+	convertGitMv      bool
+	convertDiff       bool
 )
 
 // convertCmd represents the convert command
@@ -27,6 +38,24 @@ var convertCmd = &cobra.Command{
 	Short: "Convert between Backstage and Shoehorn manifest formats",
 	Long: `Convert manifest files between Backstage and Shoehorn formats.
 
+Backstage Component, API, System, Resource and Group entities are converted to
+Shoehorn manifests locally, with no login or network access. Backstage fields
+with no Shoehorn equivalent are reported as warnings on stderr. --remote
+converts through the server of the current profile instead; conversions
+--to backstage and --to mold always do. Local conversions keep the comments
+of the fields they convert.
+
+Each document of a multi-document YAML file is converted on its own and the
+results are joined with ---; locally, documents of unsupported kinds are
+skipped with a warning. Converting a directory locally, files that are not
+Backstage manifests (such as CI workflows) are skipped with a warning too.
+
+--in-place replaces each file with its conversion, and --git-mv moves it to
+.shoehorn/<id>.yml next to it with git mv, so its history follows (files
+holding several manifests are refused); --backup keeps the original as
+<file>.bak. --diff prints a unified diff of what would
+change instead of writing anything.
+
 Examples:
   # Convert a Backstage manifest to Shoehorn format
   shoehorn convert catalog-info.yaml
@@ -44,7 +73,17 @@ Examples:
   shoehorn convert ./manifests -r
 
   # Validate during conversion
-  shoehorn convert catalog-info.yaml --validate`,
+  shoehorn convert catalog-info.yaml --validate
+
+  # Convert through the server
+  shoehorn convert catalog-info.yaml --remote
+
+  # Preview, then migrate a repository to .shoehorn/<id>.yml
+  shoehorn convert . -r --git-mv --diff
+  shoehorn convert . -r --git-mv
+
+  # Convert in place, keeping catalog-info.yaml.bak
+  shoehorn convert catalog-info.yaml --in-place --backup`,
 	Args: cobra.ExactArgs(1),
 	RunE: runConvert,
 }
@@ -54,32 +93,40 @@ func init() {
 	convertCmd.Flags().StringVar(&convertOutputType, "to", "shoehorn", "output format: shoehorn, backstage, or mold")
 	convertCmd.Flags().BoolVar(&convertValidate, "validate", false, "validate manifest after conversion")
 	convertCmd.Flags().BoolVarP(&convertRecursive, "recursive", "r", false, "recursively process directories")
+	convertCmd.Flags().BoolVar(&convertRemote, "remote", false, "convert through the server instead of locally")
+	convertCmd.Flags().BoolVar(&convertInPlace, "in-place", false, "replace each input file with its conversion")
+	convertCmd.Flags().BoolVar(&convertGitMv, "git-mv", false, "move each input file to .shoehorn/<id>.yml with git mv and write the conversion there")
+	convertCmd.Flags().BoolVar(&convertBackup, "backup", false, "with --in-place or --git-mv, keep the original as <file>.bak")
+	convertCmd.Flags().BoolVar(&convertDiff, "diff", false, "print a unified diff of the changes instead of writing them")
 	rootCmd.AddCommand(convertCmd)
 }
 
 func runConvert(cmd *cobra.Command, args []string) error {
 	inputPath := args[0]
 
-	// Load config and create API client
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
-	}
-
-	// Get current profile
-	currentProfile, err := cfg.GetCurrentProfile()
-	if err != nil {
-		return err
+	rewrite := convertInPlace || convertGitMv
+	switch {
+	case convertInPlace && convertGitMv:
+		return fmt.Errorf("--in-place and --git-mv cannot be combined")
+	case rewrite && convertOutput != "":
+		return fmt.Errorf("--output cannot be combined with --in-place or --git-mv")
+	case rewrite && convertOutputType == "mold":
+		return fmt.Errorf("a mold is JSON and cannot replace a manifest; use --output")
+	case convertGitMv && convertOutputType != "shoehorn":
+		return fmt.Errorf("--git-mv moves files to .shoehorn/ and requires --to shoehorn")
+	case convertBackup && !rewrite:
+		return fmt.Errorf("--backup requires --in-place or --git-mv")
 	}
 
-	// Create API client
-	client := api.NewClient(currentProfile.Server)
-
-	// Set auth token if available
-	if !cfg.IsAuthenticated() {
-		return fmt.Errorf("not authenticated - run 'shoehorn auth login' first")
+	// Only server conversions need a client
+	var client *api.Client
+	if convertRemote || convertOutputType != "shoehorn" {
+		var err error
+		client, err = api.NewClientFromConfig()
+		if err != nil {
+			return err
+		}
 	}
-	client.SetToken(currentProfile.Auth.AccessToken)
 
 	// Check if input is a directory
 	fileInfo, err := os.Stat(inputPath)
@@ -100,6 +147,7 @@ func runConvert(cmd *cobra.Command, args []string) error {
 
 func convertDirectory(client *api.Client, dirPath string) error {
 	var filesProcessed int
+	var filesSkipped int
 	var filesFailed int
 
 	err := filepath.WalkDir(dirPath, func(path string, d fs.DirEntry, err error) error {
@@ -107,8 +155,11 @@ func convertDirectory(client *api.Client, dirPath string) error {
 			return err
 		}
 
-		// Skip directories
+		// Skip directories, and manifests that are already converted
 		if d.IsDir() {
+			if path != dirPath && (d.Name() == ".git" || (d.Name() == ".shoehorn" && convertOutputType == "shoehorn")) {
+				return filepath.SkipDir
+			}
 			return nil
 		}
 
@@ -128,8 +179,14 @@ func convertDirectory(client *api.Client, dirPath string) error {
 			outputPath = filepath.Join(convertOutput, relPath)
 		}
 
-		fmt.Printf("Converting %s...\n", path)
+		fmt.Fprintf(convertProgress(), "Converting %s...\n", path)
 		if err := convertFile(client, path, outputPath); err != nil {
+			// Other YAML in the tree, such as CI workflows, is not an error
+			if errors.Is(err, convert.ErrNotBackstage) {
+				fmt.Fprintf(os.Stderr, "  ⚠ Skipped: %v\n", err)
+				filesSkipped++
+				return nil
+			}
 			fmt.Fprintf(os.Stderr, "  ✗ Failed: %v\n", err)
 			filesFailed++
 			return nil // Continue processing other files
@@ -143,7 +200,7 @@ func convertDirectory(client *api.Client, dirPath string) error {
 		return fmt.Errorf("failed to walk directory: %w", err)
 	}
 
-	fmt.Printf("\nProcessed %d files, %d failed\n", filesProcessed, filesFailed)
+	fmt.Fprintf(convertProgress(), "\nProcessed %d files, %d skipped, %d failed\n", filesProcessed, filesSkipped, filesFailed)
 	if filesFailed > 0 {
 		return fmt.Errorf("%d files failed to convert", filesFailed)
 	}
@@ -151,6 +208,7 @@ func convertDirectory(client *api.Client, dirPath string) error {
 	return nil
 }
 
+// convertFile converts one file, locally when client is nil
 func convertFile(client *api.Client, inputPath string, outputPath string) error {
 	// Read input file
 	data, err := os.ReadFile(inputPath)
@@ -158,61 +216,288 @@ func convertFile(client *api.Client, inputPath string, outputPath string) error
 		return fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Call API
-	ctx := context.Background()
-	result, err := client.ConvertManifest(ctx, string(data), convertOutputType, convertValidate)
+	var (
+		outputData []byte
+		validation *api.ManifestValidationResult
+	)
+	if client == nil {
+		outputData, validation, err = convertLocal(inputPath, data)
+	} else {
+		outputData, validation, err = convertRemoteFile(client, data)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to convert manifest: %w", err)
+		return err
 	}
 
-	if !result.Success {
-		return fmt.Errorf("conversion failed - manifest is invalid")
+	if convertDiff {
+		dest := inputPath
+		switch {
+		case outputPath != "":
+			dest = outputPath
+		case convertGitMv:
+			if dest, err = gitMoveTarget(inputPath, outputData); err != nil {
+				return err
+			}
+		}
+		fmt.Print(textdiff.Unified(inputPath, dest, string(data), string(outputData), textdiff.DefaultContext))
+	} else if err := writeConversion(inputPath, outputPath, data, outputData); err != nil {
+		return err
 	}
 
-	// Prepare output
-	var outputData []byte
-	if convertOutputType == "mold" {
-		// Mold format is JSON
-		outputData, err = json.MarshalIndent(result.Mold, "", "  ")
+	// Show validation results if requested
+	if convertValidate && validation != nil {
+		if !validation.Valid {
+			fmt.Fprintln(convertProgress(), "\nValidation errors:")
+			for _, err := range validation.Errors {
+				if err.Field != "" {
+					fmt.Fprintf(convertProgress(), "  - %s: %s\n", err.Field, err.Message)
+				} else {
+					fmt.Fprintf(convertProgress(), "  - %s\n", err.Message)
+				}
+			}
+		} else {
+			fmt.Fprintln(convertProgress(), "  ✓ Validation passed")
+		}
+	}
+
+	return nil
+}
+
+// writeConversion writes a conversion to outputPath, over the input with
+// --in-place, to .shoehorn/<id>.yml with --git-mv, or to stdout
+func writeConversion(inputPath, outputPath string, original, converted []byte) error {
+	if convertInPlace || convertGitMv {
+		info, err := os.Stat(inputPath)
 		if err != nil {
-			return fmt.Errorf("failed to marshal mold: %w", err)
+			return err
 		}
-	} else {
-		// Shoehorn and Backstage formats are YAML
-		outputData = []byte(result.Content)
+		dest := inputPath
+		if convertGitMv {
+			if dest, err = gitMoveTarget(inputPath, converted); err != nil {
+				return err
+			}
+		}
+		if convertBackup {
+			if err := os.WriteFile(inputPath+".bak", original, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("failed to write backup: %w", err)
+			}
+		}
+		if convertGitMv {
+			if err := gitMove(inputPath, dest); err != nil {
+				return err
+			}
+		}
+		if err := os.WriteFile(dest, converted, info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", dest, err)
+		}
+		if dest != inputPath {
+			fmt.Fprintf(convertProgress(), "  ✓ Moved %s to %s\n", inputPath, dest)
+		} else {
+			fmt.Fprintf(convertProgress(), "  ✓ Converted %s in place\n", inputPath)
+		}
+		return nil
 	}
 
-	// Write output
 	if outputPath != "" {
 		// Create parent directories if needed
 		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
 			return fmt.Errorf("failed to create output directory: %w", err)
 		}
 
-		if err := os.WriteFile(outputPath, outputData, 0644); err != nil {
+		if err := os.WriteFile(outputPath, converted, 0644); err != nil {
 			return fmt.Errorf("failed to write output file: %w", err)
 		}
 		fmt.Printf("  ✓ Wrote to %s\n", outputPath)
-	} else {
-		// Write to stdout
-		fmt.Println(string(outputData))
+		return nil
 	}
 
-	// Show validation results if requested
-	if convertValidate && result.Validation != nil {
-		if !result.Validation.Valid {
-			fmt.Println("\nValidation errors:")
-			for _, err := range result.Validation.Errors {
-				if err.Field != "" {
-					fmt.Printf("  - %s: %s\n", err.Field, err.Message)
-				} else {
-					fmt.Printf("  - %s\n", err.Message)
-				}
+	// Write to stdout
+	fmt.Println(strings.TrimSuffix(string(converted), "\n"))
+	return nil
+}
+
+// gitMoveTarget returns .shoehorn/<id>.yml next to a converted file. A file
+// converted to several manifests has no single target and is refused.
+func gitMoveTarget(inputPath string, converted []byte) (string, error) {
+	if docs := manifest.SplitDocuments(string(converted)); len(docs) > 1 {
+		return "", fmt.Errorf("%s converts to %d manifests and cannot be moved to one; convert it with --output instead", inputPath, len(docs))
+	}
+	m, err := manifest.Parse(converted)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", inputPath, err)
+	}
+	return filepath.Join(filepath.Dir(inputPath), ".shoehorn", m.Service.ID+".yml"), nil
+}
+
+// gitMove renames a file with git mv, so that its history follows it
+func gitMove(from, to string) error {
+	if _, err := os.Stat(to); err == nil {
+		return fmt.Errorf("cannot move %s: %s already exists", from, to)
+	}
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+	}
+	absFrom, err := filepath.Abs(from)
+	if err != nil {
+		return err
+	}
+	absTo, err := filepath.Abs(to)
+	if err != nil {
+		return err
+	}
+	out, err := exec.Command("git", "-C", filepath.Dir(absFrom), "mv", "--", absFrom, absTo).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("git mv %s %s: %s", from, to, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// convertProgress is where progress lines go: stdout, unless --diff is
+// printing a patch there
+func convertProgress() io.Writer {
+	if convertDiff {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// convertLocal converts the Backstage documents of a file to Shoehorn with
+// pkg/convert, printing unmapped fields as warnings and validating against
+// the embedded schema when --validate is set. In a file with several
+// documents, those that cannot be converted are skipped with a warning. A
+// file with no Backstage document at all is an error wrapping
+// convert.ErrNotBackstage.
+func convertLocal(inputPath string, data []byte) ([]byte, *api.ManifestValidationResult, error) {
+	docs := manifest.SplitDocuments(string(data))
+	var outputs []string
+
+	// Warnings wait until a Backstage document turns up, so that a file with
+	// none is reported once rather than document by document
+	var pending []string
+	backstage := false
+	flush := func() {
+		for _, w := range pending {
+			fmt.Fprintln(os.Stderr, w)
+		}
+		pending = nil
+	}
+	warn := func(format string, args ...any) {
+		pending = append(pending, fmt.Sprintf(format, args...))
+		if backstage {
+			flush()
+		}
+	}
+
+	for _, doc := range docs {
+		prefix := ""
+		if len(docs) > 1 {
+			prefix = doc.Describe() + ": "
+		}
+
+		result, err := convert.FromBackstage([]byte(doc.Content))
+		backstage = backstage || !errors.Is(err, convert.ErrNotBackstage)
+		switch {
+		case errors.Is(err, convert.ErrNotBackstage) && len(docs) == 1:
+			return nil, nil, fmt.Errorf("%s is %w", inputPath, err)
+		case err != nil && len(docs) > 1 && (errors.Is(err, convert.ErrNotBackstage) || errors.Is(err, convert.ErrUnsupportedKind)):
+			warn("⚠ %s: %sskipped: %v", inputPath, prefix, err)
+			continue
+		case err != nil:
+			return nil, nil, fmt.Errorf("failed to convert %smanifest: %w (use --remote to convert through the server)", prefix, err)
+		}
+		for _, w := range result.Warnings {
+			warn("⚠ %s: %s%s", inputPath, prefix, w)
+		}
+
+		out, err := result.Marshal()
+		if err != nil {
+			return nil, nil, err
+		}
+		outputs = append(outputs, string(out))
+	}
+	if !backstage {
+		return nil, nil, fmt.Errorf("%s is %w", inputPath, convert.ErrNotBackstage)
+	}
+	flush()
+	if len(outputs) == 0 {
+		return nil, nil, fmt.Errorf("%s has no Backstage documents that can be converted", inputPath)
+	}
+
+	out := []byte(manifest.JoinDocuments(outputs))
+	if !convertValidate {
+		return out, nil, nil
+	}
+	validation := &api.ManifestValidationResult{Valid: true, Errors: []api.ManifestValidationError{}}
+	for _, doc := range manifest.SplitDocuments(string(out)) {
+		res, err := schema.ValidateManifest(doc.Content, "")
+		if err != nil {
+			return nil, nil, err
+		}
+		mergeValidation(validation, res.Valid, res.Errors, doc, len(outputs))
+	}
+	return out, validation, nil
+}
+
+// mergeValidation adds a document's validation result to a file's, naming
+// the document in its errors when the file has several
+func mergeValidation(into *api.ManifestValidationResult, valid bool, errs []api.ManifestValidationError, doc manifest.Document, docs int) {
+	into.Valid = into.Valid && valid
+	for _, e := range errs {
+		if docs > 1 {
+			e.Message = fmt.Sprintf("%s (%s)", e.Message, doc.Describe())
+		}
+		into.Errors = append(into.Errors, e)
+	}
+}
+
+// convertRemoteFile converts each document of a file through the server.
+// Several molds are written as a JSON array.
+func convertRemoteFile(client *api.Client, data []byte) ([]byte, *api.ManifestValidationResult, error) {
+	ctx := context.Background()
+	docs := manifest.SplitDocuments(string(data))
+
+	var (
+		outputs    []string
+		molds      []map[string]any
+		validation *api.ManifestValidationResult
+	)
+	for _, doc := range docs {
+		prefix := ""
+		if len(docs) > 1 {
+			prefix = doc.Describe() + ": "
+		}
+		result, err := client.ConvertManifest(ctx, doc.Content, convertOutputType, convertValidate)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to convert %smanifest: %w", prefix, err)
+		}
+		if !result.Success {
+			return nil, nil, fmt.Errorf("conversion failed - %smanifest is invalid", prefix)
+		}
+		if result.Validation != nil {
+			if validation == nil {
+				validation = &api.ManifestValidationResult{Valid: true, Errors: []api.ManifestValidationError{}}
 			}
-		} else {
-			fmt.Println("  ✓ Validation passed")
+			mergeValidation(validation, result.Validation.Valid, result.Validation.Errors, doc, len(docs))
 		}
+		outputs = append(outputs, result.Content)
+		molds = append(molds, result.Mold)
 	}
 
-	return nil
+	if convertOutputType == "mold" {
+		// Mold format is JSON
+		var v any = molds
+		if len(molds) == 1 {
+			v = molds[0]
+		}
+		out, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal mold: %w", err)
+		}
+		return out, validation, nil
+	}
+	// Shoehorn and Backstage formats are YAML
+	if len(outputs) == 1 {
+		return []byte(outputs[0]), validation, nil
+	}
+	return []byte(manifest.JoinDocuments(outputs)), validation, nil
 }
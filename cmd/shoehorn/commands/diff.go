@@ -0,0 +1,209 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/config"
+	"github.com/shoehorn-dev/cli/pkg/snapshot"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+var diffAgainst string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff [<snapshot-a> <snapshot-b>]",
+	Short: "Compare the entities of two snapshots or two profiles",
+	Long: `Compare catalog entities between two snapshots (from "catalog export"), or
+between the live catalogs of two profiles. Reports added, removed and changed
+entities: ownership changes, tag drift, scorecard grade movements and changes
+to name, type, lifecycle, tier and description.
+
+With --against, both catalogs are read from their servers, never from the
+local cache, so drift is not reported from stale data; this does not work
+with --offline.
+
+Examples:
+  shoehorn diff before.json after.json
+  shoehorn diff --profile staging --against prod
+  shoehorn diff --profile staging --against prod -o json`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if diffAgainst != "" {
+			if len(args) != 0 {
+				return fmt.Errorf("--against compares live profiles and takes no snapshot arguments")
+			}
+			return nil
+		}
+		if len(args) != 2 {
+			return fmt.Errorf("expected two snapshots, or --against <profile>")
+		}
+		return nil
+	},
+	RunE: runDiff,
+}
+
+func runDiff(_ *cobra.Command, args []string) error {
+	var from, to *snapshot.Snapshot
+	var fromLabel, toLabel string
+
+	if diffAgainst != "" {
+		cfg, err := config.Load()
+		if err != nil {
+			return fmt.Errorf("load config: %w", err)
+		}
+		fromLabel, toLabel = cfg.ActiveProfileName(), diffAgainst
+		if fromLabel == toLabel {
+			return fmt.Errorf("--against %s is the active profile; choose another with --profile", toLabel)
+		}
+
+		_, spinErr := tui.RunSpinner(fmt.Sprintf("Fetching %s and %s catalogs...", fromLabel, toLabel), func() (any, error) {
+			var err error
+			if from, err = exportProfile(fromLabel); err != nil {
+				return nil, err
+			}
+			to, err = exportProfile(toLabel)
+			return nil, err
+		})
+		if spinErr != nil {
+			return spinErr
+		}
+	} else {
+		fromLabel, toLabel = args[0], args[1]
+		var err error
+		if from, err = snapshot.ReadFile(fromLabel); err != nil {
+			return fmt.Errorf("read %s: %w", fromLabel, err)
+		}
+		if to, err = snapshot.ReadFile(toLabel); err != nil {
+			return fmt.Errorf("read %s: %w", toLabel, err)
+		}
+	}
+
+	d := snapshot.Compare(from, to)
+	d.From, d.To = fromLabel, toLabel
+
+	switch ui.DetectMode(false, NoInteractive(), OutputFormat()) {
+	case ui.ModeJSON:
+		return ui.RenderJSON(d)
+	case ui.ModeYAML:
+		return ui.RenderYAML(d)
+	}
+	renderDiff(d)
+	return nil
+}
+
+// exportProfile fetches the live entities of a profile, bypassing its cache
+func exportProfile(name string) (*snapshot.Snapshot, error) {
+	client, err := api.NewClientForProfile(name)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	snap, err := snapshot.Export(context.Background(), client, snapshot.ExportOptions{EntitiesOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", name, err)
+	}
+	return snap, nil
+}
+
+// renderDiff prints a colored summary of d
+func renderDiff(d *snapshot.Diff) {
+	if d.Empty() {
+		fmt.Printf("No differences between %s and %s.\n", d.From, d.To)
+		return
+	}
+
+	fmt.Println(tui.TitleStyle.Render(fmt.Sprintf("%s → %s", d.From, d.To)))
+
+	if len(d.Added) > 0 {
+		fmt.Println()
+		fmt.Println(tui.HeaderStyle.Render(fmt.Sprintf("Added (%d)", len(d.Added))))
+		for _, e := range d.Added {
+			fmt.Printf("  %s %s%s\n", tui.SuccessStyle.Render("+"), e.ID, entityHint(e))
+		}
+	}
+
+	if len(d.Removed) > 0 {
+		fmt.Println()
+		fmt.Println(tui.HeaderStyle.Render(fmt.Sprintf("Removed (%d)", len(d.Removed))))
+		for _, e := range d.Removed {
+			fmt.Printf("  %s %s%s\n", tui.ErrorStyle.Render("-"), e.ID, entityHint(e))
+		}
+	}
+
+	if len(d.Changed) > 0 {
+		fmt.Println()
+		fmt.Println(tui.HeaderStyle.Render(fmt.Sprintf("Changed (%d)", len(d.Changed))))
+		for _, c := range d.Changed {
+			fmt.Printf("  %s %s\n", tui.WarnStyle.Render("~"), c.ID)
+			if c.Owner != nil {
+				printChange("owner", orNone(c.Owner.From)+" → "+orNone(c.Owner.To))
+			}
+			if len(c.TagsAdded) > 0 || len(c.TagsRemoved) > 0 {
+				var tags []string
+				for _, t := range c.TagsAdded {
+					tags = append(tags, tui.SuccessStyle.Render("+"+t))
+				}
+				for _, t := range c.TagsRemoved {
+					tags = append(tags, tui.ErrorStyle.Render("-"+t))
+				}
+				printChange("tags", strings.Join(tags, " "))
+			}
+			if g := c.Grade; g != nil {
+				style := tui.ErrorStyle
+				if g.Improved() {
+					style = tui.SuccessStyle
+				}
+				printChange("grade", style.Render(formatGrade(g.From, g.FromScore)+" → "+formatGrade(g.To, g.ToScore)))
+			}
+			for _, f := range c.Fields {
+				printChange(f.Field, fmt.Sprintf("%q → %q", f.From, f.To))
+			}
+		}
+	}
+
+	s := d.Summary
+	fmt.Println()
+	fmt.Printf("%d added, %d removed, %d changed (%d owner, %d tag, %d grades up, %d grades down)\n",
+		s.Added, s.Removed, s.Changed, s.OwnerChanges, s.TagChanges, s.GradesImproved, s.GradesDropped)
+}
+
+func printChange(label, value string) {
+	fmt.Printf("      %s %s\n", tui.MutedStyle.Render(label+":"), value)
+}
+
+// entityHint returns " (type, owner)" for an added or removed entity
+func entityHint(e *snapshot.Entity) string {
+	var parts []string
+	if e.Type != "" {
+		parts = append(parts, e.Type)
+	}
+	if e.Owner != "" {
+		parts = append(parts, "owner "+e.Owner)
+	}
+	if len(parts) == 0 {
+		return ""
+	}
+	return tui.MutedStyle.Render(" (" + strings.Join(parts, ", ") + ")")
+}
+
+func formatGrade(grade string, score int) string {
+	if grade == "" {
+		return "none"
+	}
+	return fmt.Sprintf("%s (%d)", grade, score)
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
+
+func init() {
+	diffCmd.Flags().StringVar(&diffAgainst, "against", "", "compare the live catalog of the active profile with this profile")
+	rootCmd.AddCommand(diffCmd)
+}
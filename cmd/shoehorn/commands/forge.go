@@ -3,12 +3,17 @@ package commands
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"os"
 	"strconv"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/table"
 	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/inputs"
+	"github.com/shoehorn-dev/cli/pkg/schema"
 	"github.com/shoehorn-dev/cli/pkg/tui"
 	"github.com/shoehorn-dev/cli/pkg/ui"
 	"github.com/spf13/cobra"
@@ -25,7 +30,7 @@ var forgeCmd = &cobra.Command{
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Manage workflow runs",
-	Long:  `List, create, and inspect workflow runs.`,
+	Long:  `List, create, inspect, watch, cancel, retry, and roll back workflow runs.`,
 }
 
 // runListCmd represents the forge run list command
@@ -49,9 +54,12 @@ var runCreateCmd = &cobra.Command{
 	Short: "Create a new workflow run",
 	Long: `Start a new Forge workflow run from a mold slug.
 
-Optionally pass input values as JSON or key=value pairs:
+Optionally pass input values as JSON, key=value pairs or files:
   shoehorn forge run create my-mold --action create --inputs '{"env":"staging"}'
-  shoehorn forge run create my-mold --action create --input env=staging --input name=my-repo`,
+  shoehorn forge run create my-mold --action create --input env=staging --input name=my-repo
+  shoehorn forge run create my-mold --action create --inputs-file inputs.yaml
+
+` + inputSourcesHelp,
 	Args: cobra.ExactArgs(1),
 	RunE: runCreateRun,
 }
@@ -65,7 +73,23 @@ var executeCmd = &cobra.Command{
 Examples:
   shoehorn forge execute my-mold --input name=my-repo --input owner=acme
   shoehorn forge execute my-mold --action scaffold --inputs '{"name":"my-repo"}'
-  shoehorn forge execute my-mold --dry-run --input name=test`,
+  shoehorn forge execute my-mold --dry-run --input name=test
+  shoehorn forge execute my-mold --dry-run --offline --input name=test
+  shoehorn forge execute my-mold --input name=my-repo --wait --timeout 15m
+  shoehorn forge execute my-mold --inputs-file inputs.yaml --input description=@README.md
+
+` + inputSourcesHelp + `
+
+Inputs are checked against the mold's JSON Schema before a run is created, and
+every problem is reported at once. With --offline, --dry-run checks inputs
+against the cached mold without contacting the server.
+
+On a terminal, missing required inputs are asked for in a form built from the
+mold's input schema, followed by a summary to confirm. --no-interactive turns
+the form off.
+
+With --wait the command follows the run until it finishes and exits non-zero
+if it does not complete (see "shoehorn forge run watch").`,
 	Args: cobra.ExactArgs(1),
 	RunE: runExecute,
 }
@@ -79,7 +103,7 @@ var moldsCmd = &cobra.Command{
 // moldsListCmd lists all molds
 var moldsListCmd = &cobra.Command{
 	Use:   "list",
-	Short: "List all molds",
+	Short: "List molds",
 	RunE:  runMoldsList,
 }
 
@@ -91,26 +115,44 @@ var moldsGetCmd = &cobra.Command{
 	RunE:  runMoldsGet,
 }
 
+// inputSourcesHelp explains where run inputs come from, for command help
+const inputSourcesHelp = `Input values are merged from, lowest precedence first:
+  1. the mold's defaults
+  2. --inputs-file files (YAML or JSON, - for stdin), in the order given
+  3. --inputs JSON
+  4. --input key=value flags, in the order given
+${VAR} and ${VAR:-default} in strings from files and --inputs are expanded
+from the environment ($${ for a literal ${). --input key=@path reads the
+value from a file as-is (@- for stdin); use key=@@text for a literal @.`
+
 var (
 	runInputsJSON   string
+	runInputsFiles  []string
 	runInputKVPairs []string
 	runActionFlag   string
 	runDryRunFlag   bool
+	runPaging       PageFlags
+	moldPaging      PageFlags
 )
 
 func init() {
 	// run create flags
+	runCreateCmd.Flags().StringArrayVar(&runInputsFiles, "inputs-file", nil, "Read input values from a YAML or JSON file, - for stdin (repeatable)")
 	runCreateCmd.Flags().StringVar(&runInputsJSON, "inputs", "", "Input values as JSON object")
-	runCreateCmd.Flags().StringArrayVar(&runInputKVPairs, "input", nil, "Input as key=value (repeatable)")
+	runCreateCmd.Flags().StringArrayVar(&runInputKVPairs, "input", nil, "Input as key=value or key=@file (repeatable)")
 	runCreateCmd.Flags().StringVar(&runActionFlag, "action", "", "Action name (auto-selects primary if omitted)")
 	runCreateCmd.Flags().BoolVar(&runDryRunFlag, "dry-run", false, "Validate without executing")
 
 	// execute flags (same as run create)
+	executeCmd.Flags().StringArrayVar(&runInputsFiles, "inputs-file", nil, "Read input values from a YAML or JSON file, - for stdin (repeatable)")
 	executeCmd.Flags().StringVar(&runInputsJSON, "inputs", "", "Input values as JSON object")
-	executeCmd.Flags().StringArrayVar(&runInputKVPairs, "input", nil, "Input as key=value (repeatable)")
+	executeCmd.Flags().StringArrayVar(&runInputKVPairs, "input", nil, "Input as key=value or key=@file (repeatable)")
 	executeCmd.Flags().StringVar(&runActionFlag, "action", "", "Action name (auto-selects primary if omitted)")
 	executeCmd.Flags().BoolVar(&runDryRunFlag, "dry-run", false, "Validate without executing")
 
+	AddPageFlags(runListCmd, &runPaging)
+	AddPageFlags(moldsListCmd, &moldPaging)
+
 	runCmd.AddCommand(runListCmd)
 	runCmd.AddCommand(runGetCmd)
 	runCmd.AddCommand(runCreateCmd)
@@ -126,29 +168,83 @@ func init() {
 
 // ─── Input helpers ──────────────────────────────────────────────────────────
 
-// buildInputs merges --inputs JSON and --input key=value flags into a single map.
-func buildInputs(inputsJSON string, kvPairs []string) (map[string]any, error) {
-	inputs := map[string]any{}
+// buildInputs merges input values into a single map. Later sources override
+// earlier ones: --inputs-file files in the order given, then --inputs JSON,
+// then --input flags in the order given. ${VAR} references in strings from
+// files and --inputs are expanded from the environment. --input key=@path
+// reads the value from a file (or stdin for "@-") as-is; "@@" stands for a
+// literal leading "@".
+func buildInputs(files []string, inputsJSON string, kvPairs []string) (map[string]any, error) {
+	result := map[string]any{}
+
+	stdinRead := false
+	read := func(name string) ([]byte, error) {
+		if name == "-" {
+			if stdinRead {
+				return nil, fmt.Errorf("stdin (-) can only be read once")
+			}
+			stdinRead = true
+		}
+		return readManifestFile(name)
+	}
+
+	for _, name := range files {
+		data, err := read(name)
+		if err != nil {
+			return nil, fmt.Errorf("--inputs-file: %w", err)
+		}
+		if name == "-" {
+			name = "stdin"
+		}
+		values, err := inputs.Decode(data)
+		if err != nil {
+			return nil, fmt.Errorf("--inputs-file %s: %w", name, err)
+		}
+		expanded, err := inputs.Interpolate(values, os.LookupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("--inputs-file %s: %w", name, err)
+		}
+		maps.Copy(result, expanded.(map[string]any))
+	}
 
 	if inputsJSON != "" {
-		if err := json.Unmarshal([]byte(inputsJSON), &inputs); err != nil {
+		var values map[string]any
+		if err := json.Unmarshal([]byte(inputsJSON), &values); err != nil {
 			return nil, fmt.Errorf("parse --inputs JSON: %w", err)
 		}
+		expanded, err := inputs.Interpolate(values, os.LookupEnv)
+		if err != nil {
+			return nil, fmt.Errorf("--inputs: %w", err)
+		}
+		maps.Copy(result, expanded.(map[string]any))
 	}
 
 	for _, kv := range kvPairs {
-		parts := strings.SplitN(kv, "=", 2)
-		if len(parts) != 2 {
-			return nil, fmt.Errorf("invalid --input format %q, expected key=value", kv)
+		key, value, ok := strings.Cut(kv, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --input format %q, expected key=value or key=@file", kv)
+		}
+		switch {
+		case strings.HasPrefix(value, "@@"):
+			value = value[1:]
+		case strings.HasPrefix(value, "@"):
+			data, err := read(value[1:])
+			if err != nil {
+				return nil, fmt.Errorf("--input %s: %w", key, err)
+			}
+			// Editors end files with a newline that is not part of the value
+			value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
 		}
-		inputs[parts[0]] = parts[1]
+		result[key] = value
 	}
 
-	return inputs, nil
+	return result, nil
 }
 
-// coerceInputTypes converts string values from --input flags to their schema types.
-// JSON values from --inputs are already correctly typed.
+// coerceInputTypes converts string values from --input flags to their schema types;
+// arrays and objects are given as JSON. Values that do not parse are left as
+// strings for validation to report. JSON values from --inputs are already
+// correctly typed.
 func coerceInputTypes(inputs map[string]any, schema []api.MoldInput) {
 	typeMap := map[string]string{}
 	for _, inp := range schema {
@@ -173,8 +269,59 @@ func coerceInputTypes(inputs map[string]any, schema []api.MoldInput) {
 			if i, err := strconv.ParseInt(s, 10, 64); err == nil {
 				inputs[key] = i
 			}
+		case "array", "object":
+			var v any
+			if err := json.Unmarshal([]byte(s), &v); err == nil {
+				inputs[key] = v
+			}
+		}
+	}
+}
+
+// validateInputs checks inputs against the mold's JSON Schema and reports
+// every violation at once. Molds without a schema the CLI can compile are
+// only checked for required inputs, leaving the rest to the server.
+func validateInputs(mold *api.MoldDetail, inputs map[string]any) error {
+	var errs []api.ManifestValidationError
+	checked := false
+	if mold.Schema != nil {
+		if compiled, err := schema.Compile(mold.Schema); err != nil {
+			ui.RenderWarning(fmt.Sprintf("inputs not fully checked locally: mold schema: %v", err))
+		} else {
+			errs = compiled.Validate(inputs)
+			checked = true
+		}
+	}
+	if !checked {
+		for _, inp := range mold.Inputs {
+			if _, exists := inputs[inp.Name]; inp.Required && !exists {
+				errs = append(errs, api.ManifestValidationError{Field: inp.Name, Message: "is required"})
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	if len(errs) == 1 {
+		b.WriteString("invalid input:")
+	} else {
+		fmt.Fprintf(&b, "%d invalid inputs:", len(errs))
+	}
+	missing := false
+	for _, e := range errs {
+		field := e.Field
+		if field == "" {
+			field = "inputs"
 		}
+		fmt.Fprintf(&b, "\n  %s: %s", field, e.Message)
+		missing = missing || e.Message == "is required"
+	}
+	if missing {
+		b.WriteString("\nUse --input key=value to provide missing inputs")
 	}
+	return ui.WithExitCode(ui.ExitValidation, errors.New(b.String()))
 }
 
 // resolveAction determines which action to use: explicit flag, primary action, or first action.
@@ -208,6 +355,9 @@ func runExecute(cmd *cobra.Command, args []string) error {
 	moldResult, spinErr := tui.RunSpinner(fmt.Sprintf("Loading mold %q...", moldSlug), func() (any, error) {
 		return client.GetMold(ctx, moldSlug)
 	})
+	if errors.Is(spinErr, api.ErrOffline) {
+		return fmt.Errorf("get mold: %w\nRun \"shoehorn forge molds get %s\" online once to cache it", spinErr, moldSlug)
+	}
 	if spinErr != nil {
 		return fmt.Errorf("get mold: %w", spinErr)
 	}
@@ -219,11 +369,18 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("no action specified and mold %q has no actions defined; use --action flag", moldSlug)
 	}
 
-	// 3. Build inputs
-	inputs, err := buildInputs(runInputsJSON, runInputKVPairs)
+	// 3. Build inputs, asking for the rest on a terminal if required ones are missing
+	inputs, err := buildInputs(runInputsFiles, runInputsJSON, runInputKVPairs)
 	if err != nil {
 		return err
 	}
+	prompted := false
+	if needsPrompt(mold.Inputs, inputs) && ui.CanPrompt(noInteractive, outputFormat) {
+		if err := promptInputs(fmt.Sprintf("%s · %s", moldSlug, action), mold.Inputs, inputs); err != nil {
+			return err
+		}
+		prompted = true
+	}
 
 	// 4. Fill defaults for missing non-required inputs
 	for _, inp := range mold.Inputs {
@@ -232,25 +389,17 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// 5. Coerce string values to schema types (boolean, number, integer)
+	// 5. Coerce string values to schema types (boolean, number, integer, array, object)
 	coerceInputTypes(inputs, mold.Inputs)
 
-	// 6. Validate required inputs
-	var missing []string
-	for _, inp := range mold.Inputs {
-		if inp.Required {
-			if _, exists := inputs[inp.Name]; !exists {
-				missing = append(missing, inp.Name)
-			}
-		}
-	}
-	if len(missing) > 0 {
-		return fmt.Errorf("missing required inputs: %s\nUse --input key=value to provide them", strings.Join(missing, ", "))
+	// 6. Validate inputs against the mold schema
+	if err := validateInputs(mold, inputs); err != nil {
+		return err
 	}
 
-	// 6. Handle JSON/YAML output
+	// 7. Handle JSON/YAML output; with --wait the run's final state is rendered instead
 	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
-	if mode == ui.ModeJSON {
+	if mode == ui.ModeJSON && !runWaitFlag {
 		return ui.RenderJSON(map[string]any{
 			"mold_slug": moldSlug,
 			"action":    action,
@@ -259,16 +408,41 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		})
 	}
 
-	// 7. Create run
+	// 8. Create run, after confirming what the wizard collected
+	if prompted {
+		if err := confirmExecute(moldSlug, action, mold.Inputs, inputs); err != nil {
+			return err
+		}
+	}
+	if runDryRunFlag && offline {
+		body := fmt.Sprintf(
+			"%s  %s\n%s  %s\n%s  %d\n\n%s",
+			tui.LabelStyle.Render("Mold"), moldSlug,
+			tui.LabelStyle.Render("Action"), action,
+			tui.LabelStyle.Render("Inputs"), len(inputs),
+			tui.MutedStyle.Render("Inputs checked against the cached mold schema; the server was not contacted."),
+		)
+		fmt.Println(tui.SuccessBox("Dry Run Complete", body))
+		return nil
+	}
 	result, spinErr := tui.RunSpinner(fmt.Sprintf("Executing %q action %q...", moldSlug, action), func() (any, error) {
 		return client.CreateRun(ctx, moldSlug, action, inputs, runDryRunFlag)
 	})
 	if spinErr != nil {
 		fmt.Println(tui.ErrorBox("Execution Failed", spinErr.Error()))
-		return nil
+		return ui.Reported(spinErr)
 	}
 
 	run := result.(*api.ForgeRun)
+	if runWaitFlag && mode == ui.ModeJSON {
+		if run.Done() {
+			if err := ui.RenderJSON(run); err != nil {
+				return err
+			}
+			return runOutcome(run)
+		}
+		return waitForRun(client, run.ID)
+	}
 
 	prefix := "Run Created"
 	if runDryRunFlag {
@@ -283,8 +457,15 @@ func runExecute(cmd *cobra.Command, args []string) error {
 		tui.LabelStyle.Render("Status"), tui.StatusColor(run.Status).Render(run.Status),
 	)
 	fmt.Println(tui.SuccessBox(prefix, body))
+	if runWaitFlag {
+		if run.Done() {
+			return runOutcome(run)
+		}
+		fmt.Println()
+		return waitForRun(client, run.ID)
+	}
 	if !runDryRunFlag {
-		fmt.Printf("\nCheck progress: shoehorn forge run get %s\n", run.ID)
+		fmt.Printf("\nCheck progress: shoehorn forge run watch %s\n", run.ID)
 	}
 	return nil
 }
@@ -297,16 +478,25 @@ func runListRuns(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := runPaging.Options()
+	if err != nil {
+		return err
+	}
+
 	result, spinErr := tui.RunSpinner("Loading runs...", func() (any, error) {
-		return client.ListRuns(context.Background())
+		return client.ListRuns(context.Background(), page)
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list runs: %w", spinErr)
 	}
 
 	response := result.(*api.ForgeRunsResponse)
+	more := response.Pagination.HasMore
 
 	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
+	if more && mode != ui.ModeInteractive {
+		WarnTruncated("runs", len(response.Runs))
+	}
 	switch mode {
 	case ui.ModeJSON:
 		return ui.RenderJSON(response)
@@ -345,7 +535,7 @@ func runListRuns(cmd *cobra.Command, args []string) error {
 				tuiRows[i] = table.Row(r)
 			}
 			_, tErr := tui.RunTable(tui.TableConfig{
-				Title:   fmt.Sprintf("Runs  (%d)", len(response.Runs)),
+				Title:   fmt.Sprintf("Runs  (%s)", CountLabel(len(response.Runs), more)),
 				Columns: tuiCols,
 				Rows:    tuiRows,
 			})
@@ -419,7 +609,7 @@ func runGetRun(cmd *cobra.Command, args []string) error {
 func runCreateRun(cmd *cobra.Command, args []string) error {
 	moldSlug := args[0]
 
-	inputs, err := buildInputs(runInputsJSON, runInputKVPairs)
+	inputs, err := buildInputs(runInputsFiles, runInputsJSON, runInputKVPairs)
 	if err != nil {
 		return err
 	}
@@ -448,7 +638,7 @@ func runCreateRun(cmd *cobra.Command, args []string) error {
 	})
 	if spinErr != nil {
 		fmt.Println(tui.ErrorBox("Run Failed", spinErr.Error()))
-		return nil
+		return ui.Reported(spinErr)
 	}
 
 	run := result.(*api.ForgeRun)
@@ -477,8 +667,16 @@ func runMoldsList(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := moldPaging.Options()
+	if err != nil {
+		return err
+	}
+
+	var more bool
 	result, spinErr := tui.RunSpinner("Loading molds...", func() (any, error) {
-		return client.ListMolds(context.Background())
+		molds, m, err := client.ListMolds(context.Background(), page)
+		more = m
+		return molds, err
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list molds: %w", spinErr)
@@ -487,6 +685,9 @@ func runMoldsList(cmd *cobra.Command, args []string) error {
 	molds := result.([]*api.Mold)
 
 	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
+	if more && mode != ui.ModeInteractive {
+		WarnTruncated("molds", len(molds))
+	}
 	if mode == ui.ModeJSON {
 		return ui.RenderJSON(molds)
 	}
@@ -516,7 +717,7 @@ func runMoldsList(cmd *cobra.Command, args []string) error {
 			tuiRows[i] = table.Row(r)
 		}
 		_, err = tui.RunTable(tui.TableConfig{
-			Title:   fmt.Sprintf("Molds  (%d)", len(molds)),
+			Title:   fmt.Sprintf("Molds  (%s)", CountLabel(len(molds), more)),
 			Columns: tuiCols,
 			Rows:    tuiRows,
 		})
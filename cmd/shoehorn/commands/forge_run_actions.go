@@ -0,0 +1,198 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// runCancelCmd stops a pending or executing run
+var runCancelCmd = &cobra.Command{
+	Use:   "cancel <run-id>",
+	Short: "Cancel a pending or executing workflow run",
+	Long: `Cancel a workflow run that has not finished yet. Steps already carried out
+are left as they are; use "forge run rollback" to undo them.
+
+Examples:
+  shoehorn forge run cancel 3f2c9a
+  shoehorn forge run cancel 3f2c9a --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runCancelRun,
+}
+
+// runRetryCmd starts a new run with the mold, action and inputs of an earlier one
+var runRetryCmd = &cobra.Command{
+	Use:   "retry <run-id>",
+	Short: "Start a failed workflow run again",
+	Long: `Start a new run with the same mold, action and inputs as a run that failed,
+was cancelled or was rolled back.
+
+Examples:
+  shoehorn forge run retry 3f2c9a
+  shoehorn forge run retry 3f2c9a --yes --wait`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRetryRun,
+}
+
+// runRollbackCmd undoes the steps of a finished run
+var runRollbackCmd = &cobra.Command{
+	Use:   "rollback <run-id>",
+	Short: "Undo the steps a finished workflow run carried out",
+	Long: `Roll back a run that completed, failed or was cancelled, undoing the steps it
+carried out. A run that is still going must be cancelled first.
+
+Examples:
+  shoehorn forge run rollback 3f2c9a
+  shoehorn forge run rollback 3f2c9a --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRollbackRun,
+}
+
+var runActionYes bool
+
+func init() {
+	for _, cmd := range []*cobra.Command{runCancelCmd, runRetryCmd, runRollbackCmd} {
+		cmd.Flags().BoolVarP(&runActionYes, "yes", "y", false, "skip the confirmation prompt")
+		runCmd.AddCommand(cmd)
+	}
+	runRetryCmd.Flags().BoolVar(&runWaitFlag, "wait", false, "Wait for the new run to finish, showing its progress")
+	addWatchFlags(runRetryCmd)
+}
+
+func runCancelRun(cmd *cobra.Command, args []string) error {
+	client, run, err := confirmRunAction(args[0], "Cancel", func(run *api.ForgeRun) error {
+		if run.Done() {
+			return fmt.Errorf("run %s has already finished (%s); nothing to cancel", run.ID, run.Status)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result, spinErr := tui.RunSpinner(fmt.Sprintf("Cancelling run %q...", run.ID), func() (any, error) {
+		return client.CancelRun(context.Background(), run.ID)
+	})
+	if spinErr != nil {
+		return fmt.Errorf("cancel run: %w", spinErr)
+	}
+	return renderRunChange("Run Cancelled", result.(*api.ForgeRun))
+}
+
+func runRetryRun(cmd *cobra.Command, args []string) error {
+	client, run, err := confirmRunAction(args[0], "Retry", (*api.ForgeRun).Retryable)
+	if err != nil {
+		return err
+	}
+
+	result, spinErr := tui.RunSpinner(fmt.Sprintf("Retrying run %q...", run.ID), func() (any, error) {
+		return client.CreateRun(context.Background(), run.MoldSlug, run.Action, run.Inputs, run.DryRun)
+	})
+	if spinErr != nil {
+		return fmt.Errorf("retry run: %w", spinErr)
+	}
+	retry := result.(*api.ForgeRun)
+
+	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
+	if runWaitFlag && !retry.Done() {
+		if mode != ui.ModeJSON && mode != ui.ModeYAML {
+			fmt.Printf("Retrying run %s as %s\n\n", run.ID, retry.ID)
+		}
+		return waitForRun(client, retry.ID)
+	}
+	if err := renderRunChange("Run Retried", retry, tui.Field{Label: "Retry Of", Value: run.ID}); err != nil {
+		return err
+	}
+	if runWaitFlag {
+		return runOutcome(retry)
+	}
+	if mode != ui.ModeJSON && mode != ui.ModeYAML {
+		fmt.Printf("\nCheck progress: shoehorn forge run watch %s\n", retry.ID)
+	}
+	return nil
+}
+
+func runRollbackRun(cmd *cobra.Command, args []string) error {
+	client, run, err := confirmRunAction(args[0], "Roll back", func(run *api.ForgeRun) error {
+		switch {
+		case !run.Done():
+			return fmt.Errorf("run %s is still %s; cancel it before rolling it back", run.ID, run.Status)
+		case run.Status == api.RunRolledBack:
+			return fmt.Errorf("run %s has already been rolled back", run.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	result, spinErr := tui.RunSpinner(fmt.Sprintf("Rolling back run %q...", run.ID), func() (any, error) {
+		return client.RollbackRun(context.Background(), run.ID)
+	})
+	if spinErr != nil {
+		return fmt.Errorf("roll back run: %w", spinErr)
+	}
+	return renderRunChange("Rollback Started", result.(*api.ForgeRun))
+}
+
+// confirmRunAction loads a run, checks that the action applies to it and,
+// unless --yes is set, asks before going ahead
+func confirmRunAction(runID, verb string, check func(*api.ForgeRun) error) (*api.Client, *api.ForgeRun, error) {
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, spinErr := tui.RunSpinner(fmt.Sprintf("Loading run %q...", runID), func() (any, error) {
+		return client.GetRun(context.Background(), runID)
+	})
+	if spinErr != nil {
+		return nil, nil, fmt.Errorf("get run: %w", spinErr)
+	}
+	run := result.(*api.ForgeRun)
+
+	if err := check(run); err != nil {
+		return nil, nil, err
+	}
+	if !runActionYes {
+		ok, err := confirm(fmt.Sprintf("%s run %s (mold %s, action %s, %s)?", verb, run.ID, run.MoldSlug, run.Action, run.Status))
+		if err != nil {
+			return nil, nil, err
+		}
+		if !ok {
+			return nil, nil, errDeclined
+		}
+	}
+	return client, run, nil
+}
+
+// renderRunChange shows a run after cancel, retry or rollback
+func renderRunChange(title string, run *api.ForgeRun, extra ...tui.Field) error {
+	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
+	switch mode {
+	case ui.ModeJSON:
+		return ui.RenderJSON(run)
+	case ui.ModeYAML:
+		return ui.RenderYAML(run)
+	}
+
+	fields := append([]tui.Field{
+		{Label: "Run ID", Value: run.ID},
+		{Label: "Mold", Value: run.MoldSlug},
+		{Label: "Action", Value: run.Action},
+		{Label: "Status", Value: tui.StatusColor(run.Status).Render(run.Status)},
+	}, extra...)
+	var body string
+	for i, f := range fields {
+		if i > 0 {
+			body += "\n"
+		}
+		body += fmt.Sprintf("%s  %s", tui.LabelStyle.Render(f.Label), f.Value)
+	}
+	fmt.Println(tui.SuccessBox(title, body))
+	return nil
+}
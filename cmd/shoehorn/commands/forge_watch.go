@@ -0,0 +1,185 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// runWatchCmd follows a forge run until it finishes
+var runWatchCmd = &cobra.Command{
+	Use:   "watch <run-id>",
+	Short: "Follow a workflow run until it finishes",
+	Long: `Follow a workflow run, showing the status of each step and streaming step
+logs, until the run finishes. Events are streamed when the server supports
+it; otherwise the run is polled every --interval.
+
+The exit code reflects how the run ended: 0 completed, 7 failed,
+8 rolled back, 6 cancelled, 5 --timeout expired.
+
+Examples:
+  shoehorn forge run watch 3f2c9a
+  shoehorn forge run watch 3f2c9a --timeout 15m --no-interactive`,
+	Args: cobra.ExactArgs(1),
+	RunE: runWatchRun,
+}
+
+var (
+	runWaitFlag      bool
+	runWatchInterval time.Duration
+	runWatchTimeout  time.Duration
+)
+
+func init() {
+	addWatchFlags(runWatchCmd)
+	executeCmd.Flags().BoolVar(&runWaitFlag, "wait", false, "Wait for the run to finish, showing its progress")
+	addWatchFlags(executeCmd)
+
+	runCmd.AddCommand(runWatchCmd)
+}
+
+func addWatchFlags(cmd *cobra.Command) {
+	cmd.Flags().DurationVar(&runWatchInterval, "interval", 2*time.Second, "How often to poll when the server does not stream events")
+	cmd.Flags().DurationVar(&runWatchTimeout, "timeout", 0, "Stop waiting after this long, e.g. 15m (0 waits until the run finishes)")
+}
+
+func runWatchRun(cmd *cobra.Command, args []string) error {
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+	return waitForRun(client, args[0])
+}
+
+// waitForRun follows a run until it finishes, then renders it and returns an
+// error carrying the exit code for how it ended
+func waitForRun(client *api.Client, runID string) error {
+	if runWatchInterval <= 0 {
+		return fmt.Errorf("--interval must be positive")
+	}
+	ctx := context.Background()
+	if runWatchTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, runWatchTimeout)
+		defer cancel()
+	}
+
+	mode := ui.DetectMode(interactive, noInteractive, outputFormat)
+	var run *api.ForgeRun
+	status := "pending"
+	var err error
+	if mode == ui.ModeJSON || mode == ui.ModeYAML {
+		run, err = client.WatchRun(ctx, runID, runWatchInterval, func(u api.RunUpdate) {
+			if u.Run != nil {
+				status = u.Run.Status
+			}
+		})
+	} else {
+		err = tui.RunProgress(ctx, "Run "+runID, func(ctx context.Context, update func(tui.Progress)) error {
+			var werr error
+			run, werr = client.WatchRun(ctx, runID, runWatchInterval, func(u api.RunUpdate) {
+				if u.Run != nil {
+					status = u.Run.Status
+				}
+				update(runProgress(u))
+			})
+			return werr
+		})
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil:
+		return fmt.Errorf("run %s still %s after %s: %w", runID, status, runWatchTimeout, err)
+	case errors.Is(err, context.Canceled):
+		return fmt.Errorf("stopped watching run %s; it continues on the server: %w", runID, err)
+	case err != nil:
+		return fmt.Errorf("watch run: %w", err)
+	}
+
+	outcome := runOutcome(run)
+	switch mode {
+	case ui.ModeJSON:
+		if err := ui.RenderJSON(run); err != nil {
+			return err
+		}
+		return outcome
+	case ui.ModeYAML:
+		if err := ui.RenderYAML(run); err != nil {
+			return err
+		}
+		return outcome
+	}
+
+	body := fmt.Sprintf(
+		"%s  %s\n%s  %s\n%s  %s",
+		tui.LabelStyle.Render("Run ID"), run.ID,
+		tui.LabelStyle.Render("Mold"), run.MoldSlug,
+		tui.LabelStyle.Render("Status"), tui.StatusColor(run.Status).Render(run.Status),
+	)
+	if run.Error != "" {
+		body += fmt.Sprintf("\n%s  %s", tui.LabelStyle.Render("Error"), tui.ErrorStyle.Render(run.Error))
+	}
+	if outcome == nil {
+		fmt.Println(tui.SuccessBox("Run Completed", body))
+		return nil
+	}
+	title := "Run Ended"
+	switch run.Status {
+	case api.RunFailed:
+		title = "Run Failed"
+	case api.RunRolledBack:
+		title = "Run Rolled Back"
+	case api.RunCancelled:
+		title = "Run Cancelled"
+	}
+	fmt.Println(tui.ErrorBox(title, body))
+	return ui.Reported(outcome)
+}
+
+// runOutcome returns nil for a completed run, and otherwise an error with the
+// exit code for the status the run ended in
+func runOutcome(run *api.ForgeRun) error {
+	detail := ""
+	if run.Error != "" {
+		detail = ": " + run.Error
+	}
+	switch run.Status {
+	case api.RunCompleted:
+		return nil
+	case api.RunFailed:
+		return ui.WithExitCode(ui.ExitRunFailed, fmt.Errorf("run %s failed%s", run.ID, detail))
+	case api.RunRolledBack:
+		return ui.WithExitCode(ui.ExitRolledBack, fmt.Errorf("run %s failed and was rolled back%s", run.ID, detail))
+	case api.RunCancelled:
+		return ui.WithExitCode(ui.ExitCancelled, fmt.Errorf("run %s was cancelled", run.ID))
+	}
+	return fmt.Errorf("run %s ended with unknown status %q", run.ID, run.Status)
+}
+
+// runProgress converts a run update for the progress view
+func runProgress(u api.RunUpdate) tui.Progress {
+	var p tui.Progress
+	if u.Run != nil {
+		p.Status = u.Run.Status
+		p.Steps = make([]tui.Step, len(u.Run.Steps))
+		for i, s := range u.Run.Steps {
+			p.Steps[i] = tui.Step{Name: s.Name, Status: s.Status, Detail: s.Error}
+		}
+	}
+	for _, l := range u.Logs {
+		msg := l.Message
+		if l.Level == "error" {
+			msg = tui.ErrorStyle.Render(msg)
+		}
+		if l.Step != "" {
+			msg = tui.MutedStyle.Render("["+l.Step+"]") + " " + msg
+		}
+		p.Logs = append(p.Logs, msg)
+	}
+	return p
+}
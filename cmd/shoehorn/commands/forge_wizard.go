@@ -0,0 +1,135 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+)
+
+// needsPrompt reports whether a required input has neither a value nor a default
+func needsPrompt(schema []api.MoldInput, inputs map[string]any) bool {
+	for _, inp := range schema {
+		if _, given := inputs[inp.Name]; inp.Required && !given && inp.Default == "" {
+			return true
+		}
+	}
+	return false
+}
+
+// promptInputs asks for every input not already given, in the mold's input
+// order with defaults pre-filled, and adds the answers to inputs. JSON answers
+// for array and object inputs are decoded; the rest are left as strings for
+// coerceInputTypes.
+func promptInputs(title string, schema []api.MoldInput, inputs map[string]any) error {
+	var fields []tui.FormField
+	for _, inp := range schema {
+		if _, given := inputs[inp.Name]; given {
+			continue
+		}
+		fields = append(fields, tui.FormField{
+			Name:        inp.Name,
+			Description: inp.Description,
+			Kind:        fieldKind(inp),
+			Required:    inp.Required,
+			Default:     inp.Default,
+			Options:     inp.Enum,
+		})
+	}
+
+	answers, err := tui.RunForm(title, fields)
+	if errors.Is(err, tui.ErrFormCancelled) {
+		return errDeclined
+	}
+	if err != nil {
+		return err
+	}
+	for _, f := range fields {
+		answer, ok := answers[f.Name]
+		if !ok {
+			continue
+		}
+		if f.Kind == tui.FieldJSON {
+			var v any
+			if err := json.Unmarshal([]byte(answer), &v); err != nil {
+				return fmt.Errorf("input %s: %w", f.Name, err)
+			}
+			inputs[f.Name] = v
+			continue
+		}
+		inputs[f.Name] = answer
+	}
+	return nil
+}
+
+// fieldKind picks the form widget for a mold input
+func fieldKind(inp api.MoldInput) tui.FieldKind {
+	if len(inp.Enum) > 0 {
+		return tui.FieldSelect
+	}
+	switch inp.Type {
+	case "boolean":
+		return tui.FieldBool
+	case "number":
+		return tui.FieldNumber
+	case "integer":
+		return tui.FieldInteger
+	case "array", "object":
+		return tui.FieldJSON
+	}
+	return tui.FieldText
+}
+
+// confirmExecute shows the run about to be created and asks to go ahead
+func confirmExecute(moldSlug, action string, schema []api.MoldInput, inputs map[string]any) error {
+	fields := make([]tui.Field, 0, len(inputs))
+	listed := map[string]bool{}
+	for _, inp := range schema {
+		if v, ok := inputs[inp.Name]; ok {
+			fields = append(fields, tui.Field{Label: inp.Name, Value: formatInputValue(v)})
+			listed[inp.Name] = true
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(inputs)) {
+		if !listed[name] {
+			fields = append(fields, tui.Field{Label: name, Value: formatInputValue(inputs[name])})
+		}
+	}
+
+	sections := []tui.DetailSection{
+		{Fields: []tui.Field{
+			{Label: "Mold", Value: moldSlug},
+			{Label: "Action", Value: action},
+		}},
+		{Title: "Inputs", Fields: fields},
+	}
+	if runDryRunFlag {
+		sections[0].Fields = append(sections[0].Fields, tui.Field{Label: "Dry Run", Value: "true"})
+	}
+	fmt.Println(tui.RenderDetail("Run Summary", sections))
+
+	ok, err := confirm("Start this run?")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errDeclined
+	}
+	return nil
+}
+
+// formatInputValue renders an input value for the summary
+func formatInputValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case map[string]any, []any:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
@@ -18,12 +18,19 @@ var (
 	entityType    string
 	entityOwner   string
 	showScorecard bool
+	entityPaging  commands.PageFlags
 )
 
 var entitiesCmd = &cobra.Command{
 	Use:   "entities",
-	Short: "List all catalog entities",
-	RunE:  runGetEntities,
+	Short: "List catalog entities",
+	Long: `List catalog entities, fetching pages from the API until --limit is reached.
+
+Examples:
+  shoehorn get entities --type service
+  shoehorn get entities --all -o json > entities.json
+  shoehorn get entities --limit 500 --page-size 250`,
+	RunE: runGetEntities,
 }
 
 var entityCmd = &cobra.Command{
@@ -36,6 +43,7 @@ var entityCmd = &cobra.Command{
 func init() {
 	entitiesCmd.Flags().StringVar(&entityType, "type", "", "Filter by entity type (service, library, etc.)")
 	entitiesCmd.Flags().StringVar(&entityOwner, "owner", "", "Filter by owning team slug")
+	commands.AddPageFlags(entitiesCmd, &entityPaging)
 
 	entityCmd.Flags().BoolVar(&showScorecard, "scorecard", false, "Include scorecard in output")
 
@@ -49,13 +57,21 @@ func runGetEntities(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := entityPaging.Options()
+	if err != nil {
+		return err
+	}
 	opts := api.ListEntitiesOpts{
-		Type:  entityType,
-		Owner: entityOwner,
+		Type:        entityType,
+		Owner:       entityOwner,
+		PageOptions: page,
 	}
 
+	var more bool
 	result, spinErr := tui.RunSpinner("Loading entities...", func() (any, error) {
-		return client.ListEntities(context.Background(), opts)
+		entities, m, err := client.ListEntities(context.Background(), opts)
+		more = m
+		return entities, err
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list entities: %w", spinErr)
@@ -64,6 +80,9 @@ func runGetEntities(cmd *cobra.Command, args []string) error {
 	entities := result.([]*api.Entity)
 
 	mode := ui.DetectMode(commands.Interactive(), commands.NoInteractive(), commands.OutputFormat())
+	if more && mode != ui.ModeInteractive {
+		commands.WarnTruncated("entities", len(entities))
+	}
 	if mode == ui.ModeJSON {
 		return ui.RenderJSON(entities)
 	}
@@ -93,7 +112,7 @@ func runGetEntities(cmd *cobra.Command, args []string) error {
 		for i, r := range rows {
 			tuiRows[i] = table.Row(r)
 		}
-		title := fmt.Sprintf("Entities  (%d)", len(entities))
+		title := fmt.Sprintf("Entities  (%s)", commands.CountLabel(len(entities), more))
 		if entityType != "" {
 			title += fmt.Sprintf("  type=%s", entityType)
 		}
@@ -130,8 +149,8 @@ func runGetEntity(cmd *cobra.Command, args []string) error {
 		return e, nil
 	})
 	if spinErr != nil {
-		if strings.Contains(spinErr.Error(), "404") {
-			return fmt.Errorf("entity %q not found.\nHint: Use the ID column from `shoehorn get entities` to look up an entity by its service ID", id)
+		if api.IsNotFound(spinErr) {
+			return fmt.Errorf("entity %q not found: %w\nHint: Use the ID column from `shoehorn get entities` to look up an entity by its service ID", id, spinErr)
 		}
 		return fmt.Errorf("get entity: %w", spinErr)
 	}
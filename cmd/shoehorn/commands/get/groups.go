@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var groupPaging commands.PageFlags
+
 var groupsCmd = &cobra.Command{
 	Use:   "groups",
 	Short: "List all directory groups",
@@ -26,6 +28,8 @@ var groupCmd = &cobra.Command{
 }
 
 func init() {
+	commands.AddPageFlags(groupsCmd, &groupPaging)
+
 	GetCmd.AddCommand(groupsCmd)
 	GetCmd.AddCommand(groupCmd)
 }
@@ -36,8 +40,16 @@ func runGetGroups(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := groupPaging.Options()
+	if err != nil {
+		return err
+	}
+
+	var more bool
 	result, spinErr := tui.RunSpinner("Loading groups...", func() (any, error) {
-		return client.ListGroups(context.Background())
+		groups, m, err := client.ListGroups(context.Background(), page)
+		more = m
+		return groups, err
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list groups: %w", spinErr)
@@ -46,6 +58,9 @@ func runGetGroups(cmd *cobra.Command, args []string) error {
 	groups := result.([]*api.Group)
 
 	mode := ui.DetectMode(commands.Interactive(), commands.NoInteractive(), commands.OutputFormat())
+	if more && mode != ui.ModeInteractive {
+		commands.WarnTruncated("groups", len(groups))
+	}
 	if mode == ui.ModeJSON {
 		return ui.RenderJSON(groups)
 	}
@@ -69,7 +84,7 @@ func runGetGroups(cmd *cobra.Command, args []string) error {
 			tuiRows[i] = table.Row(r)
 		}
 		_, err = tui.RunTable(tui.TableConfig{
-			Title:   fmt.Sprintf("Groups  (%d)", len(groups)),
+			Title:   fmt.Sprintf("Groups  (%s)", commands.CountLabel(len(groups), more)),
 			Columns: tuiCols,
 			Rows:    tuiRows,
 		})
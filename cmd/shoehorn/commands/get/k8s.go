@@ -12,6 +12,8 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var k8sPaging commands.PageFlags
+
 var k8sCmd = &cobra.Command{
 	Use:   "k8s",
 	Short: "List Kubernetes agents",
@@ -20,6 +22,8 @@ var k8sCmd = &cobra.Command{
 }
 
 func init() {
+	commands.AddPageFlags(k8sCmd, &k8sPaging)
+
 	GetCmd.AddCommand(k8sCmd)
 }
 
@@ -29,8 +33,16 @@ func runGetK8s(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := k8sPaging.Options()
+	if err != nil {
+		return err
+	}
+
+	var more bool
 	result, spinErr := tui.RunSpinner("Loading K8s agents...", func() (any, error) {
-		return client.ListK8sAgents(context.Background())
+		agents, m, err := client.ListK8sAgents(context.Background(), page)
+		more = m
+		return agents, err
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list k8s agents: %w", spinErr)
@@ -39,6 +51,9 @@ func runGetK8s(cmd *cobra.Command, args []string) error {
 	agents := result.([]*api.K8sAgent)
 
 	mode := ui.DetectMode(commands.Interactive(), commands.NoInteractive(), commands.OutputFormat())
+	if more && mode != ui.ModeInteractive {
+		commands.WarnTruncated("agents", len(agents))
+	}
 	if mode == ui.ModeJSON {
 		return ui.RenderJSON(agents)
 	}
@@ -65,7 +80,7 @@ func runGetK8s(cmd *cobra.Command, args []string) error {
 			tuiRows[j] = table.Row{a.ClusterName, status, a.Version, a.LastSeen}
 		}
 		_, err = tui.RunTable(tui.TableConfig{
-			Title:   fmt.Sprintf("Kubernetes Agents  (%d)", len(agents)),
+			Title:   fmt.Sprintf("Kubernetes Agents  (%s)", commands.CountLabel(len(agents), more)),
 			Columns: tuiCols,
 			Rows:    tuiRows,
 		})
@@ -51,7 +51,7 @@ func runGetOwned(cmd *cobra.Command, args []string) error {
 	seen := map[string]bool{}
 	var allEntities []*api.Entity
 	for _, team := range me.Teams {
-		entities, err := client.ListEntities(ctx, api.ListEntitiesOpts{Owner: team})
+		entities, _, err := client.ListEntities(ctx, api.ListEntitiesOpts{Owner: team})
 		if err != nil {
 			continue
 		}
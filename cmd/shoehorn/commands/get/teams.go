@@ -12,9 +12,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var teamPaging commands.PageFlags
+
 var teamsCmd = &cobra.Command{
 	Use:   "teams",
-	Short: "List all teams",
+	Short: "List teams",
 	RunE:  runGetTeams,
 }
 
@@ -26,6 +28,8 @@ var teamCmd = &cobra.Command{
 }
 
 func init() {
+	commands.AddPageFlags(teamsCmd, &teamPaging)
+
 	GetCmd.AddCommand(teamsCmd)
 	GetCmd.AddCommand(teamCmd)
 }
@@ -36,8 +40,16 @@ func runGetTeams(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := teamPaging.Options()
+	if err != nil {
+		return err
+	}
+
+	var more bool
 	result, spinErr := tui.RunSpinner("Loading teams...", func() (any, error) {
-		return client.ListTeams(context.Background())
+		teams, m, err := client.ListTeams(context.Background(), page)
+		more = m
+		return teams, err
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list teams: %w", spinErr)
@@ -46,6 +58,9 @@ func runGetTeams(cmd *cobra.Command, args []string) error {
 	teams := result.([]*api.Team)
 
 	mode := ui.DetectMode(commands.Interactive(), commands.NoInteractive(), commands.OutputFormat())
+	if more && mode != ui.ModeInteractive {
+		commands.WarnTruncated("teams", len(teams))
+	}
 	if mode == ui.ModeJSON {
 		return ui.RenderJSON(teams)
 	}
@@ -75,7 +90,7 @@ func runGetTeams(cmd *cobra.Command, args []string) error {
 			tuiRows[i] = table.Row(r)
 		}
 		_, err = tui.RunTable(tui.TableConfig{
-			Title:   fmt.Sprintf("Teams  (%d)", len(teams)),
+			Title:   fmt.Sprintf("Teams  (%s)", commands.CountLabel(len(teams), more)),
 			Columns: tuiCols,
 			Rows:    tuiRows,
 		})
@@ -13,9 +13,11 @@ import (
 	"github.com/spf13/cobra"
 )
 
+var userPaging commands.PageFlags
+
 var usersCmd = &cobra.Command{
 	Use:   "users",
-	Short: "List all users in the directory",
+	Short: "List users in the directory",
 	RunE:  runGetUsers,
 }
 
@@ -27,6 +29,8 @@ var userCmd = &cobra.Command{
 }
 
 func init() {
+	commands.AddPageFlags(usersCmd, &userPaging)
+
 	GetCmd.AddCommand(usersCmd)
 	GetCmd.AddCommand(userCmd)
 }
@@ -37,8 +41,16 @@ func runGetUsers(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
+	page, err := userPaging.Options()
+	if err != nil {
+		return err
+	}
+
+	var more bool
 	result, spinErr := tui.RunSpinner("Loading users...", func() (any, error) {
-		return client.ListUsers(context.Background())
+		users, m, err := client.ListUsers(context.Background(), page)
+		more = m
+		return users, err
 	})
 	if spinErr != nil {
 		return fmt.Errorf("list users: %w", spinErr)
@@ -47,6 +59,9 @@ func runGetUsers(cmd *cobra.Command, args []string) error {
 	users := result.([]*api.User)
 
 	mode := ui.DetectMode(commands.Interactive(), commands.NoInteractive(), commands.OutputFormat())
+	if more && mode != ui.ModeInteractive {
+		commands.WarnTruncated("users", len(users))
+	}
 	if mode == ui.ModeJSON {
 		return ui.RenderJSON(users)
 	}
@@ -71,7 +86,7 @@ func runGetUsers(cmd *cobra.Command, args []string) error {
 			tuiRows[i] = table.Row(r)
 		}
 		_, err = tui.RunTable(tui.TableConfig{
-			Title:   fmt.Sprintf("Users  (%d)", len(users)),
+			Title:   fmt.Sprintf("Users  (%s)", commands.CountLabel(len(users), more)),
 			Columns: tuiCols,
 			Rows:    tuiRows,
 		})
@@ -0,0 +1,58 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// DefaultListLimit caps list commands unless --limit or --all says otherwise
+const DefaultListLimit = 100
+
+// PageFlags holds the --limit, --page-size and --all flags of a list command
+type PageFlags struct {
+	limit    int
+	pageSize int
+	all      bool
+}
+
+// AddPageFlags registers the paging flags on a list command
+func AddPageFlags(cmd *cobra.Command, f *PageFlags) {
+	cmd.Flags().IntVar(&f.limit, "limit", DefaultListLimit, "maximum number of results to fetch")
+	cmd.Flags().IntVar(&f.pageSize, "page-size", api.DefaultPageSize, "results requested per API call")
+	cmd.Flags().BoolVar(&f.all, "all", false, "fetch every page, however many results there are")
+	cmd.MarkFlagsMutuallyExclusive("limit", "all")
+}
+
+// Options validates the flags and converts them to api.PageOptions
+func (f *PageFlags) Options() (api.PageOptions, error) {
+	if f.limit <= 0 {
+		return api.PageOptions{}, fmt.Errorf("--limit must be positive (use --all for no limit)")
+	}
+	if f.pageSize <= 0 {
+		return api.PageOptions{}, fmt.Errorf("--page-size must be positive")
+	}
+	opts := api.PageOptions{Limit: f.limit, PageSize: f.pageSize}
+	if f.all {
+		opts.Limit = 0
+	}
+	return opts, nil
+}
+
+// CountLabel formats a result count for table titles, with a "+" when more
+// results were left unfetched.
+func CountLabel(n int, more bool) string {
+	if more {
+		return strconv.Itoa(n) + "+"
+	}
+	return strconv.Itoa(n)
+}
+
+// WarnTruncated tells the user on stderr that a list stopped at --limit, so
+// that output piped elsewhere is not mistaken for the full result.
+func WarnTruncated(noun string, shown int) {
+	fmt.Fprintf(os.Stderr, "Showing the first %d %s; more are available. Use --all or a higher --limit to fetch them.\n", shown, noun)
+}
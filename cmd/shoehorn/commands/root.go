@@ -2,7 +2,11 @@ package commands
 
 import (
 	"fmt"
+	"os"
+	"time"
 
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/config"
 	"github.com/shoehorn-dev/cli/pkg/tui"
 	"github.com/spf13/cobra"
 )
@@ -16,6 +20,10 @@ var (
 	noInteractive bool
 	interactive   bool
 	outputFormat  string
+	retries       int
+	retryMaxDelay time.Duration
+	offline       bool
+	maxAge        time.Duration
 )
 
 // NoInteractive returns the --no-interactive flag value (for use by sub-packages)
@@ -27,6 +35,9 @@ func Interactive() bool { return interactive }
 // OutputFormat returns the --output flag value (for use by sub-packages)
 func OutputFormat() string { return outputFormat }
 
+// Offline returns true when --offline was given (for use by sub-packages)
+func Offline() bool { return offline }
+
 // rootCmd represents the base command
 var rootCmd = &cobra.Command{
 	Use:   "shoehorn",
@@ -34,10 +45,45 @@ var rootCmd = &cobra.Command{
 	Long: `Shoehorn CLI provides command-line access to the Shoehorn platform.
 
 Use it to authenticate, manage workflows, and interact with the Forge service.`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+	// main prints errors and picks the exit code
+	SilenceErrors: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// Flags and arguments are valid by now; later errors are not usage errors
+		cmd.SilenceUsage = true
+
+		config.SetPathOverride(cfgFile)
+		config.SetProfileOverride(profile)
 		if noInteractive {
 			tui.SetPlainMode(true)
 		}
+
+		// Retry flags override the profile only when given explicitly
+		maxRetries, maxDelay := -1, time.Duration(0)
+		if cmd.Flags().Changed("retries") {
+			if retries < 0 {
+				return fmt.Errorf("--retries must be 0 or more")
+			}
+			maxRetries = retries
+		}
+		if cmd.Flags().Changed("retry-max-delay") {
+			if retryMaxDelay <= 0 {
+				return fmt.Errorf("--retry-max-delay must be positive")
+			}
+			maxDelay = retryMaxDelay
+		}
+		api.SetRetryOverride(maxRetries, maxDelay)
+
+		if maxAge < 0 {
+			return fmt.Errorf("--max-age must not be negative")
+		}
+		api.SetCacheOptions(offline, maxAge)
+		return nil
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		if since, ok := api.CachedSince(); ok {
+			fmt.Fprintf(os.Stderr, "Note: showing cached data from %s (%s ago); it may be out of date.\n",
+				since.Local().Format("2006-01-02 15:04"), formatDuration(time.Since(since)))
+		}
 	},
 }
 
@@ -53,11 +99,15 @@ func RootCmd() *cobra.Command {
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.shoehorn/config.yaml)")
-	rootCmd.PersistentFlags().StringVar(&profile, "profile", "default", "authentication profile to use")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $SHOEHORN_CONFIG or $HOME/.shoehorn/config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&profile, "profile", "", "authentication profile to use (default is $SHOEHORN_PROFILE or current_profile)")
 	rootCmd.PersistentFlags().BoolVarP(&noInteractive, "no-interactive", "I", false, "disable interactive mode (force plain output)")
 	rootCmd.PersistentFlags().BoolVarP(&interactive, "interactive", "i", false, "enable interactive table mode")
 	rootCmd.PersistentFlags().StringVarP(&outputFormat, "output", "o", "", "output format (table|json|yaml)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", api.DefaultRetryPolicy.MaxRetries, "retries for GET/PUT/DELETE on connection errors, 429 and 5xx (0 disables)")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxDelay, "retry-max-delay", api.DefaultRetryPolicy.MaxDelay, "longest wait between retries, including Retry-After")
+	rootCmd.PersistentFlags().BoolVar(&offline, "offline", false, "never contact the server; serve catalog reads from the local cache")
+	rootCmd.PersistentFlags().DurationVar(&maxAge, "max-age", 0, "use cached catalog data younger than this without asking the server (e.g. 10m)")
 
 	// Set version for cobra's built-in --version flag
 	rootCmd.Version = Version
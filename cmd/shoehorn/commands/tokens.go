@@ -0,0 +1,267 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/tui"
+	"github.com/shoehorn-dev/cli/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// tokensCmd is the parent command for Personal Access Token management
+var tokensCmd = &cobra.Command{
+	Use:     "tokens",
+	Aliases: []string{"token"},
+	Short:   "Manage Personal Access Tokens",
+	Long: `List, create, and revoke your Personal Access Tokens (PATs).
+
+Examples:
+  shoehorn auth tokens list
+  shoehorn auth tokens create ci-deploy --scope catalog:read --scope forge:execute --ttl 30d
+  shoehorn auth tokens create ci-deploy --ttl 90d -o json | jq -r .token
+  shoehorn auth tokens revoke 3fa85f64`,
+}
+
+// ─── auth tokens list ───────────────────────────────────────────────────────
+
+var tokensListCmd = &cobra.Command{
+	Use:     "list",
+	Aliases: []string{"ls"},
+	Short:   "List your Personal Access Tokens",
+	Args:    cobra.NoArgs,
+	RunE:    runTokensList,
+}
+
+func runTokensList(_ *cobra.Command, _ []string) error {
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+
+	result, spinErr := tui.RunSpinner("Loading tokens...", func() (any, error) {
+		return client.ListTokens(context.Background())
+	})
+	if spinErr != nil {
+		return spinErr
+	}
+
+	tokens := result.([]*api.PersonalAccessToken)
+
+	mode := ui.DetectMode(Interactive(), NoInteractive(), OutputFormat())
+	if mode == ui.ModeJSON {
+		return ui.RenderJSON(tokens)
+	}
+	if mode == ui.ModeYAML {
+		return ui.RenderYAML(tokens)
+	}
+
+	if len(tokens) == 0 {
+		fmt.Println("No tokens found.")
+		fmt.Println("  Create one with: shoehorn auth tokens create <name>")
+		return nil
+	}
+
+	colNames := []string{"ID", "Name", "Scopes", "Created", "Last Used", "Expires"}
+	rows := make([][]string, len(tokens))
+	for i, t := range tokens {
+		scopes := strings.Join(t.Scopes, ",")
+		if scopes == "" {
+			scopes = "-"
+		}
+		expires := formatTokenDate(t.ExpiresAt, "never")
+		if t.Expired() {
+			expires = "expired " + expires
+		}
+		rows[i] = []string{
+			t.ID,
+			t.Name,
+			scopes,
+			formatTokenDate(t.CreatedAt, "-"),
+			formatTokenDate(t.LastUsedAt, "never"),
+			expires,
+		}
+	}
+
+	if mode == ui.ModeInteractive {
+		tuiCols := []table.Column{
+			{Title: "ID", Width: 14},
+			{Title: "Name", Width: 20},
+			{Title: "Scopes", Width: 28},
+			{Title: "Created", Width: 12},
+			{Title: "Last Used", Width: 12},
+			{Title: "Expires", Width: 20},
+		}
+		tuiRows := make([]table.Row, len(rows))
+		for i, r := range rows {
+			tuiRows[i] = table.Row(r)
+		}
+		_, err = tui.RunTable(tui.TableConfig{
+			Title:   fmt.Sprintf("Personal Access Tokens (%d)", len(tokens)),
+			Columns: tuiCols,
+			Rows:    tuiRows,
+		})
+		return err
+	}
+
+	ui.RenderTable(colNames, rows)
+	return nil
+}
+
+// ─── auth tokens create ─────────────────────────────────────────────────────
+
+var (
+	tokenScopes []string
+	tokenTTL    string
+)
+
+var tokensCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create a Personal Access Token",
+	Long: `Create a Personal Access Token. The secret is printed once and cannot be
+retrieved again; use -o json to capture it in scripts.
+
+--ttl accepts Go durations (720h) or days (90d). Without --ttl the server's
+default lifetime applies.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTokensCreate,
+}
+
+func runTokensCreate(_ *cobra.Command, args []string) error {
+	req := api.CreateTokenRequest{
+		Name:   args[0],
+		Scopes: tokenScopes,
+	}
+	if tokenTTL != "" {
+		ttl, err := parseTTL(tokenTTL)
+		if err != nil {
+			return err
+		}
+		req.ExpiresAt = time.Now().Add(ttl).UTC()
+	}
+
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+
+	result, spinErr := tui.RunSpinner(fmt.Sprintf("Creating token %q...", req.Name), func() (any, error) {
+		return client.CreateToken(context.Background(), req)
+	})
+	if spinErr != nil {
+		return spinErr
+	}
+
+	token := result.(*api.CreatedToken)
+
+	mode := ui.DetectMode(Interactive(), NoInteractive(), OutputFormat())
+	if mode == ui.ModeJSON {
+		return ui.RenderJSON(token)
+	}
+	if mode == ui.ModeYAML {
+		return ui.RenderYAML(token)
+	}
+
+	scopes := strings.Join(token.Scopes, ", ")
+	if scopes == "" {
+		scopes = "(server default)"
+	}
+	lines := []string{
+		fmt.Sprintf("%s  %s", tui.LabelStyle.Render("ID"), token.ID),
+		fmt.Sprintf("%s  %s", tui.LabelStyle.Render("Name"), token.Name),
+		fmt.Sprintf("%s  %s", tui.LabelStyle.Render("Scopes"), scopes),
+		fmt.Sprintf("%s  %s", tui.LabelStyle.Render("Expires"), formatTokenDate(token.ExpiresAt, "never")),
+		"",
+		token.Token,
+		"",
+		tui.MutedStyle.Render("Copy this token now. It will not be shown again."),
+	}
+	fmt.Println(tui.SuccessBox("Token created", strings.Join(lines, "\n")))
+	return nil
+}
+
+// parseTTL parses a token lifetime: a Go duration ("720h") or whole days ("90d")
+func parseTTL(s string) (time.Duration, error) {
+	var ttl time.Duration
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --ttl %q: expected e.g. 90d or 720h", s)
+		}
+		ttl = time.Duration(n) * 24 * time.Hour
+	} else {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return 0, fmt.Errorf("invalid --ttl %q: expected e.g. 90d or 720h", s)
+		}
+		ttl = d
+	}
+	if ttl <= 0 {
+		return 0, fmt.Errorf("invalid --ttl %q: must be positive", s)
+	}
+	return ttl, nil
+}
+
+// ─── auth tokens revoke ─────────────────────────────────────────────────────
+
+var tokensRevokeCmd = &cobra.Command{
+	Use:   "revoke <id>...",
+	Short: "Revoke Personal Access Tokens by ID",
+	Long: `Revoke Personal Access Tokens by ID. Every token is tried even when an
+earlier one fails; the command fails if any could not be revoked.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTokensRevoke,
+}
+
+func runTokensRevoke(_ *cobra.Command, args []string) error {
+	client, err := api.NewClientFromConfig()
+	if err != nil {
+		return err
+	}
+
+	// Every ID is tried, so that one bad ID does not leave the others live
+	ctx := context.Background()
+	failed := 0
+	for _, id := range args {
+		_, spinErr := tui.RunSpinner(fmt.Sprintf("Revoking %s...", id), func() (any, error) {
+			return nil, client.RevokeTokenByID(ctx, id)
+		})
+		if spinErr != nil {
+			if fatalBatchError(spinErr) {
+				return spinErr
+			}
+			fmt.Printf("%s Token %s not revoked: %v\n", tui.ErrorStyle.Render("✗"), id, spinErr)
+			failed++
+			continue
+		}
+		fmt.Printf("Token %s revoked.\n", id)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tokens could not be revoked", failed, len(args))
+	}
+	return nil
+}
+
+// formatTokenDate formats a token timestamp as a date, or fallback when unset
+func formatTokenDate(t time.Time, fallback string) string {
+	if t.IsZero() {
+		return fallback
+	}
+	return t.Local().Format("2006-01-02")
+}
+
+func init() {
+	tokensCreateCmd.Flags().StringArrayVar(&tokenScopes, "scope", nil, "Scope to grant (repeatable; default: server default scopes)")
+	tokensCreateCmd.Flags().StringVar(&tokenTTL, "ttl", "", "Token lifetime, e.g. 30d or 720h (default: server default)")
+
+	tokensCmd.AddCommand(tokensListCmd)
+	tokensCmd.AddCommand(tokensCreateCmd)
+	tokensCmd.AddCommand(tokensRevokeCmd)
+
+	authCmd.AddCommand(tokensCmd)
+}
@@ -1,30 +1,63 @@
 package commands
 
 import (
+	"cmp"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
+	"slices"
+	"strings"
+	"sync"
 
 	"github.com/shoehorn-dev/cli/pkg/api"
-	"github.com/shoehorn-dev/cli/pkg/config"
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+	"github.com/shoehorn-dev/cli/pkg/report"
+	"github.com/shoehorn-dev/cli/pkg/schema"
+	"github.com/shoehorn-dev/cli/pkg/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	validateInput  string
-	validateFormat string
+	validateInput         string
+	validateFormat        string
+	validateSchemaVersion string
+	validateRecursive     bool
+	validateConcurrency   int
 )
 
 // validateCmd represents the validate command
 var validateCmd = &cobra.Command{
-	Use:   "validate [file]",
+	Use:   "validate [file|dir|glob]...",
 	Short: "Validate Shoehorn or Backstage manifest files",
 	Long: `Validate manifest files and output structured validation errors.
 
 Supports both Shoehorn and Backstage manifest formats with automatic detection.
 
+Any number of files, directories and globs can be given; a directory
+contributes its *.yml and *.yaml files (with -r, those of subdirectories too)
+and ** in a glob matches any number of directories. Files found that way which
+are neither Shoehorn nor Backstage manifests, such as CI workflows, are
+skipped. With no arguments, or -,
+the manifest is read from stdin. Files are validated in parallel and the
+command exits with status 4 if any of them is invalid.
+
+Manifests are validated by the server of the current profile. With --offline
+they are validated against the schemas embedded in the CLI instead, with no
+login or network access; --schema-version picks the Shoehorn schema version
+(default: the manifest's schemaVersion, else the latest). Backstage manifests
+are always checked against the latest Backstage schema.
+
+Errors are printed as file:line:col: message, so editors and grep-style
+tooling can jump to them; JSON output carries line and column fields.
+
+Each document of a multi-document YAML file is validated on its own, and
+reported by index, kind and name.
+
+--format sarif writes a SARIF 2.1.0 log for code-scanning dashboards, and
+--format junit JUnit XML for test dashboards.
+
 Examples:
   # Validate a manifest file (text output)
   shoehorn validate catalog-info.yaml
@@ -33,95 +66,279 @@ Examples:
   shoehorn validate .shoehorn/service.yml --format json
 
   # Validate from stdin
-  cat catalog-info.yaml | shoehorn validate -`,
-	Args: cobra.MaximumNArgs(1),
+  cat catalog-info.yaml | shoehorn validate -
+
+  # Validate every manifest in a repository
+  shoehorn validate -r .
+  shoehorn validate 'services/**/catalog-info.yaml'
+
+  # Validate without a server, e.g. in a pre-commit hook
+  shoehorn validate .shoehorn/service.yml --offline
+  shoehorn validate .shoehorn/service.yml --offline --schema-version 1
+
+  # Report to GitHub code scanning
+  shoehorn validate -r . --offline --format sarif > shoehorn.sarif`,
 	RunE: runValidate,
 }
 
 func init() {
-	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format: text or json")
+	validateCmd.Flags().StringVar(&validateFormat, "format", "text", "output format: text, json, sarif, or junit")
+	validateCmd.Flags().StringVar(&validateSchemaVersion, "schema-version", "", "embedded Shoehorn schema version for --offline validation (e.g. 1)")
+	validateCmd.Flags().BoolVarP(&validateRecursive, "recursive", "r", false, "validate manifests in subdirectories of directory arguments")
+	validateCmd.Flags().IntVar(&validateConcurrency, "concurrency", 8, "files validated in parallel")
 	rootCmd.AddCommand(validateCmd)
 }
 
 func runValidate(cmd *cobra.Command, args []string) error {
-	// Determine input source
-	var content string
-	var filename string
+	switch validateFormat {
+	case "text", "json", "sarif", "junit":
+	default:
+		return fmt.Errorf("unknown format %q (expected text, json, sarif, or junit)", validateFormat)
+	}
 
-	if len(args) == 0 || args[0] == "-" {
-		// Read from stdin
-		data, err := io.ReadAll(os.Stdin)
-		if err != nil {
-			return fmt.Errorf("failed to read from stdin: %w", err)
+	if len(args) == 0 {
+		args = []string{"-"}
+	}
+	files, err := manifest.Files(args, validateRecursive, func(name string, data []byte) bool {
+		shoehorn, backstage := manifest.Sniff(data)
+		return shoehorn || backstage || manifest.IsCatalogInfo(name)
+	})
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no manifest files (*.yml, *.yaml) found in %s", strings.Join(args, ", "))
+	}
+
+	validate, err := manifestValidator()
+	if err != nil {
+		return err
+	}
+	results, err := validateFiles(files, validate)
+	if err != nil {
+		return err
+	}
+
+	switch validateFormat {
+	case "json":
+		var v any = map[string]any{
+			"files":   results,
+			"summary": report.Summarize(results),
+		}
+		if len(results) == 1 {
+			v = results[0]
 		}
-		content = string(data)
-		filename = "stdin"
-	} else {
-		// Read from file
-		filename = args[0]
-		data, err := os.ReadFile(filename)
+		jsonData, err := json.MarshalIndent(v, "", "  ")
 		if err != nil {
-			return fmt.Errorf("failed to read file: %w", err)
+			return fmt.Errorf("failed to marshal JSON: %w", err)
 		}
-		content = string(data)
+		fmt.Println(string(jsonData))
+	case "sarif":
+		if err := report.WriteSARIF(os.Stdout, results, Version); err != nil {
+			return err
+		}
+	case "junit":
+		if err := report.WriteJUnit(os.Stdout, results); err != nil {
+			return err
+		}
+	default:
+		renderValidateResults(results)
 	}
 
-	// Load config and create API client
-	cfg, err := config.Load()
-	if err != nil {
-		return fmt.Errorf("failed to load config: %w", err)
+	return validateOutcome(results)
+}
+
+// manifestValidator returns the function validating one manifest: against
+// the embedded schemas with --offline, or through the server of the current
+// profile otherwise. The client is created once and shared between files, and
+// an unknown --schema-version is reported before any file is read.
+func manifestValidator() (func(ctx context.Context, content string) (*api.ValidateManifestResponse, error), error) {
+	if Offline() {
+		if err := schema.CheckVersion(validateSchemaVersion); err != nil {
+			return nil, err
+		}
+		return func(_ context.Context, content string) (*api.ValidateManifestResponse, error) {
+			return schema.ValidateManifest(content, validateSchemaVersion)
+		}, nil
+	}
+	if validateSchemaVersion != "" {
+		return nil, fmt.Errorf("--schema-version selects an embedded schema and requires --offline")
 	}
 
-	// Get current profile
-	currentProfile, err := cfg.GetCurrentProfile()
+	client, err := api.NewClientFromConfig()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return func(ctx context.Context, content string) (*api.ValidateManifestResponse, error) {
+		result, err := client.ValidateManifest(ctx, content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to validate manifest: %w", err)
+		}
+		return result, nil
+	}, nil
+}
 
-	// Create API client
-	client := api.NewClient(currentProfile.Server)
+// validateFiles validates files in parallel, returning results in the order
+// of files. Errors that would fail every file (authentication, cancellation)
+// stop the run and are returned; others, such as an unreadable file, are
+// recorded on the file's result. Setup errors, an unknown --schema-version
+// among them, are caught by manifestValidator before any file is read.
+func validateFiles(files []string, validate func(context.Context, string) (*api.ValidateManifestResponse, error)) ([]report.FileResult, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// Set auth token if available
-	if !cfg.IsAuthenticated() {
-		return fmt.Errorf("not authenticated - run 'shoehorn auth login' first")
+	results := make([]report.FileResult, len(files))
+	var (
+		mu    sync.Mutex
+		fatal error
+	)
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(max(validateConcurrency, 1), len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				r, err := validateFile(ctx, files[i], validate)
+				results[i] = r
+				if err != nil && fatalBatchError(err) {
+					mu.Lock()
+					if fatal == nil {
+						fatal = err
+					}
+					mu.Unlock()
+					cancel()
+				}
+			}
+		}()
 	}
-	client.SetToken(currentProfile.Auth.AccessToken)
+feed:
+	for i := range files {
+		select {
+		case jobs <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
 
-	// Call API
-	ctx := context.Background()
-	result, err := client.ValidateManifest(ctx, content)
+	if fatal != nil {
+		return nil, fatal
+	}
+	return results, nil
+}
+
+// validateFile validates each YAML document of a file on its own, with error
+// positions relative to the file
+func validateFile(ctx context.Context, name string, validate func(context.Context, string) (*api.ValidateManifestResponse, error)) (report.FileResult, error) {
+	r := report.FileResult{File: name, Valid: true, Errors: []api.ManifestValidationError{}}
+	if name == "-" {
+		r.File = "stdin"
+	}
+	data, err := readManifestFile(name)
 	if err != nil {
-		return fmt.Errorf("failed to validate manifest: %w", err)
+		r.Valid = false
+		r.Error = err.Error()
+		return r, err
 	}
 
-	// Output based on format
-	if validateFormat == "json" {
-		output := map[string]any{
-			"file":   filename,
-			"valid":  result.Valid,
-			"errors": result.Errors,
-		}
-		jsonData, err := json.MarshalIndent(output, "", "  ")
+	docs := manifest.SplitDocuments(string(data))
+	for _, doc := range docs {
+		result, err := validate(ctx, doc.Content)
 		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %w", err)
+			if len(docs) > 1 {
+				err = fmt.Errorf("%s: %w", doc.Describe(), err)
+			}
+			r.Valid = false
+			r.Error = err.Error()
+			return r, err
 		}
-		fmt.Println(string(jsonData))
-	} else {
-		// Text output
-		if result.Valid {
-			fmt.Printf("✓ %s is valid\n", filename)
-		} else {
-			fmt.Printf("✗ %s has validation errors:\n\n", filename)
-			for _, err := range result.Errors {
-				if err.Field != "" {
-					fmt.Printf("  - %s: %s\n", err.Field, err.Message)
-				} else {
-					fmt.Printf("  - %s\n", err.Message)
+
+		errs := result.Errors
+		if errs == nil {
+			errs = []api.ManifestValidationError{}
+		}
+		schema.Locate(doc.Content, errs)
+		for i := range errs {
+			if errs[i].Line > 0 {
+				errs[i].Line += doc.Line - 1
+			}
+		}
+		slices.SortStableFunc(errs, func(a, b api.ManifestValidationError) int {
+			return cmp.Or(cmp.Compare(a.Line, b.Line), cmp.Compare(a.Column, b.Column))
+		})
+
+		r.Valid = r.Valid && result.Valid
+		r.Errors = append(r.Errors, errs...)
+		if len(docs) > 1 {
+			kind, docName := doc.Identity()
+			r.Documents = append(r.Documents, report.DocumentResult{
+				Index: doc.Index, Kind: kind, Name: docName, Line: doc.Line, Valid: result.Valid, Errors: errs,
+			})
+		}
+	}
+	return r, nil
+}
+
+func renderValidateResults(results []report.FileResult) {
+	for i, r := range results {
+		switch {
+		case r.Error != "":
+			fmt.Printf("! %s could not be validated: %s\n", r.File, r.Error)
+		case r.Valid && len(r.Documents) > 0:
+			fmt.Printf("✓ %s is valid (%d documents)\n", r.File, len(r.Documents))
+		case r.Valid:
+			fmt.Printf("✓ %s is valid\n", r.File)
+		default:
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("✗ %s has validation errors:\n\n", r.File)
+			if len(r.Documents) == 0 {
+				for _, e := range r.Errors {
+					fmt.Println(report.FormatLocated(r.File, e))
 				}
 			}
-			return fmt.Errorf("validation failed")
+			for _, d := range r.Documents {
+				label := strings.TrimSpace(d.Kind + " " + d.Name)
+				if d.Valid {
+					fmt.Printf("  ✓ document %d: %s\n", d.Index, label)
+					continue
+				}
+				fmt.Printf("  ✗ document %d: %s\n", d.Index, label)
+				for _, e := range d.Errors {
+					fmt.Println("    " + report.FormatLocated(r.File, e))
+				}
+			}
+			if i < len(results)-1 {
+				fmt.Println()
+			}
 		}
 	}
+	if len(results) > 1 {
+		sum := report.Summarize(results)
+		fmt.Printf("\n%d files: %d valid, %d invalid", sum.Files, sum.Valid, sum.Invalid)
+		if sum.Failed > 0 {
+			fmt.Printf(", %d could not be validated", sum.Failed)
+		}
+		fmt.Println()
+	}
+}
 
+// validateOutcome turns results into the command's error: files that could
+// not be validated are a plain error, invalid ones a validation error (exit 4)
+func validateOutcome(results []report.FileResult) error {
+	sum := report.Summarize(results)
+	switch {
+	case sum.Failed > 0 && sum.Files == 1:
+		return errors.New(results[0].Error)
+	case sum.Failed > 0:
+		return fmt.Errorf("%d of %d files could not be validated", sum.Failed, sum.Files)
+	case sum.Invalid > 0 && sum.Files == 1:
+		return ui.WithExitCode(ui.ExitValidation, fmt.Errorf("validation failed"))
+	case sum.Invalid > 0:
+		return ui.WithExitCode(ui.ExitValidation, fmt.Errorf("%d of %d files failed validation", sum.Invalid, sum.Files))
+	}
 	return nil
 }
@@ -1,11 +1,9 @@
 package main
 
 import (
-	"fmt"
-	"os"
-
 	"github.com/shoehorn-dev/cli/cmd/shoehorn/commands"
 	_ "github.com/shoehorn-dev/cli/cmd/shoehorn/commands/get" // register get subcommands
+	"github.com/shoehorn-dev/cli/pkg/ui"
 )
 
 // version is injected at build time via ldflags:
@@ -19,7 +17,6 @@ func init() {
 
 func main() {
 	if err := commands.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		ui.ExitWithError(err)
 	}
 }
@@ -194,13 +194,17 @@ func (c *Client) UploadAddonBundle(ctx context.Context, slug string, bundles map
 		return nil, fmt.Errorf("close multipart writer: %w", err)
 	}
 
+	if err := c.refreshIfExpired(ctx); err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+fmt.Sprintf("/api/v1/marketplace/%s/bundle", slug), &body)
 	if err != nil {
 		return nil, fmt.Errorf("create request: %w", err)
 	}
 	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	if token := c.GetToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
 	resp, err := c.httpClient.Do(req)
@@ -215,7 +219,7 @@ func (c *Client) UploadAddonBundle(ctx context.Context, slug string, bundles map
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("upload bundle failed (%d): %s", resp.StatusCode, string(respBody))
+		return nil, fmt.Errorf("upload bundle: %w", newAPIError(resp.StatusCode, resp.Header, respBody))
 	}
 
 	var result BundleUploadResult
@@ -2,7 +2,11 @@ package api
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
 )
 
 // AuthStatusResponse contains current authentication status
@@ -25,3 +29,44 @@ func (c *Client) GetAuthStatus(ctx context.Context) (*AuthStatusResponse, error)
 	err := c.Get(ctx, "/api/v1/auth/cli/status", &resp)
 	return &resp, err
 }
+
+// RevokeToken revokes the Personal Access Token the client authenticates with.
+// A token the server already rejects counts as revoked.
+func (c *Client) RevokeToken(ctx context.Context) error {
+	status, err := c.doIgnoreStatus(ctx, http.MethodPost, "/api/v1/auth/cli/revoke", nil, nil)
+	if err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	switch {
+	case status >= 200 && status < 300, status == http.StatusUnauthorized:
+		return nil
+	case status == http.StatusNotFound:
+		return fmt.Errorf("revoke token: server does not support token revocation")
+	default:
+		return fmt.Errorf("revoke token: %w", &APIError{StatusCode: status, Message: http.StatusText(status)})
+	}
+}
+
+// RevokeCredentials invalidates a profile's credentials on the server: the
+// PAT itself, or for OIDC logins the refresh token (which ends the session)
+// at the issuer. It does not modify the profile.
+func RevokeCredentials(ctx context.Context, server string, auth *config.Auth) error {
+	if auth == nil {
+		return nil
+	}
+	switch {
+	case auth.ProviderType == "pat" && auth.AccessToken != "":
+		c := NewClient(server)
+		c.SetToken(auth.AccessToken)
+		return c.RevokeToken(ctx)
+	case auth.ProviderType == "oidc" && auth.Issuer != "":
+		oc := NewOAuthClient(auth.Issuer, auth.ClientID, nil)
+		if auth.RefreshToken != "" {
+			return oc.Revoke(ctx, auth.RefreshToken, "refresh_token")
+		}
+		if auth.AccessToken != "" {
+			return oc.Revoke(ctx, auth.AccessToken, "access_token")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
+)
+
+func TestRevokeToken(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{"revoked", http.StatusNoContent, ""},
+		{"already invalid", http.StatusUnauthorized, ""},
+		{"unsupported", http.StatusNotFound, "does not support"},
+		{"server error", http.StatusInternalServerError, "500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/api/v1/auth/cli/revoke" {
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+				}
+				if r.Header.Get("Authorization") != "Bearer shp_abc" {
+					t.Errorf("Authorization = %q", r.Header.Get("Authorization"))
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			c := NewClient(server.URL)
+			c.SetToken("shp_abc")
+			err := c.RevokeToken(context.Background())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("RevokeToken() = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("RevokeToken() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRevokeCredentials_OIDCRevokesRefreshToken(t *testing.T) {
+	m := newMockOIDC(t)
+	auth := &config.Auth{
+		ProviderType: "oidc",
+		Issuer:       m.URL,
+		ClientID:     "shoehorn-cli",
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+	}
+
+	if err := RevokeCredentials(context.Background(), "http://unused", auth); err != nil {
+		t.Fatalf("RevokeCredentials() = %v", err)
+	}
+	if len(m.revoked) != 1 {
+		t.Fatalf("revocation requests = %d, want 1", len(m.revoked))
+	}
+	got := m.revoked[0]
+	if got.Get("token") != "refresh-1" || got.Get("token_type_hint") != "refresh_token" {
+		t.Errorf("revoked %v, want the refresh token", got)
+	}
+}
+
+func TestRevokeCredentials_IssuerWithoutRevocation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"token_endpoint":"http://example/token"}`))
+	}))
+	defer server.Close()
+
+	auth := &config.Auth{ProviderType: "oidc", Issuer: server.URL, ClientID: "shoehorn-cli", RefreshToken: "refresh-1"}
+	if err := RevokeCredentials(context.Background(), "http://unused", auth); err == nil {
+		t.Error("expected error when issuer has no revocation endpoint")
+	}
+}
@@ -0,0 +1,177 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/cache"
+	"github.com/shoehorn-dev/cli/pkg/config"
+)
+
+// ErrOffline is returned in offline mode for requests the cache cannot answer
+var ErrOffline = errors.New("not available offline (--offline)")
+
+// cacheOverride holds the global --offline and --max-age flags
+var cacheOverride struct {
+	offline bool
+	maxAge  time.Duration
+}
+
+// SetCacheOptions applies the global cache flags to clients created by
+// NewClientFromConfig. In offline mode no request reaches the server and only
+// cached catalog reads succeed. Cached responses younger than maxAge are used
+// without asking the server; 0 revalidates every read.
+func SetCacheOptions(offline bool, maxAge time.Duration) {
+	cacheOverride.offline = offline
+	cacheOverride.maxAge = maxAge
+}
+
+// responseCache is a client's view of the on-disk cache for one profile
+type responseCache struct {
+	store   *cache.Store
+	profile string
+	offline bool
+	maxAge  time.Duration
+	bypass  bool // never serve cached entries; see BypassCache
+}
+
+// newProfileCache returns the response cache for a profile, or nil when the
+// cache directory cannot be determined.
+func newProfileCache(profile string) *responseCache {
+	dir, err := config.CacheDir()
+	if err != nil {
+		return nil
+	}
+	return &responseCache{
+		store:   cache.New(dir),
+		profile: profile,
+		offline: cacheOverride.offline,
+		maxAge:  cacheOverride.maxAge,
+	}
+}
+
+// BypassCache makes the client's catalog reads always ask the server, for
+// commands that act on what they read (apply --prune). Cached entries are
+// never served, not even during an outage, but fresh responses are still
+// stored. Offline mode refuses such reads.
+func (c *Client) BypassCache() {
+	if c.cache != nil {
+		c.cache.bypass = true
+	}
+}
+
+// cacheUsage tracks cached responses served without asking the server, so
+// the command can tell the user its output may be out of date.
+var cacheUsage struct {
+	mu     sync.Mutex
+	oldest time.Time
+}
+
+// CachedSince returns when the oldest cached response served by this process
+// without revalidation was stored. ok is false if all data came from the server.
+func CachedSince() (oldest time.Time, ok bool) {
+	cacheUsage.mu.Lock()
+	defer cacheUsage.mu.Unlock()
+	return cacheUsage.oldest, !cacheUsage.oldest.IsZero()
+}
+
+// serveCached decodes a cached body into result and records its age
+func serveCached(e *cache.Entry, result any) error {
+	cacheUsage.mu.Lock()
+	if cacheUsage.oldest.IsZero() || e.StoredAt.Before(cacheUsage.oldest) {
+		cacheUsage.oldest = e.StoredAt
+	}
+	cacheUsage.mu.Unlock()
+
+	if result != nil {
+		if err := json.Unmarshal(e.Body, result); err != nil {
+			return fmt.Errorf("decode cached response: %w", err)
+		}
+	}
+	return nil
+}
+
+// cacheable reports whether a successful response may be stored
+func cacheable(status int, header http.Header) bool {
+	return status == http.StatusOK && !strings.Contains(header.Get("Cache-Control"), "no-store")
+}
+
+// getCached performs a GET for a catalog read through the response cache.
+// A cached copy is revalidated with If-None-Match/If-Modified-Since, served
+// as-is in offline mode or while younger than the max age, and used as a
+// fallback when the server is unreachable or failing.
+func (c *Client) getCached(ctx context.Context, path string, result any) error {
+	rc := c.cache
+	if rc == nil {
+		return c.Get(ctx, path, result)
+	}
+
+	// Keyed by server as well, so that re-pointing a profile starts afresh.
+	// An unreadable entry is treated as a miss.
+	key := c.baseURL + path
+	var entry *cache.Entry
+	if !rc.bypass {
+		entry, _ = rc.store.Get(rc.profile, key)
+	}
+
+	if entry != nil && (rc.offline || (rc.maxAge > 0 && entry.Age() < rc.maxAge)) {
+		return serveCached(entry, result)
+	}
+	if rc.offline && rc.bypass {
+		return fmt.Errorf("GET %s: %w", path, ErrOffline)
+	}
+	if rc.offline {
+		return fmt.Errorf("GET %s has not been cached yet: %w", path, ErrOffline)
+	}
+
+	var header http.Header
+	if entry != nil {
+		header = http.Header{}
+		if entry.ETag != "" {
+			header.Set("If-None-Match", entry.ETag)
+		}
+		if entry.LastModified != "" {
+			header.Set("If-Modified-Since", entry.LastModified)
+		}
+	}
+
+	status, respHeader, body, err := c.send(ctx, http.MethodGet, path, nil, header)
+	if entry != nil && ((err != nil && transientError(err)) || (err == nil && status >= 500)) {
+		// Stale data, marked as such, beats no data during an outage
+		return serveCached(entry, result)
+	}
+	if err != nil {
+		return err
+	}
+
+	// Writes are best effort: a read-only home directory must not fail the command
+	if status == http.StatusNotModified && entry != nil {
+		entry.StoredAt = time.Now()
+		_ = rc.store.Put(rc.profile, entry)
+		body = entry.Body
+	} else if status < 200 || status >= 300 {
+		return newAPIError(status, respHeader, body)
+	}
+
+	if result != nil {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+	if status != http.StatusNotModified && cacheable(status, respHeader) {
+		_ = rc.store.Put(rc.profile, &cache.Entry{
+			URL:          key,
+			ETag:         respHeader.Get("ETag"),
+			LastModified: respHeader.Get("Last-Modified"),
+			StoredAt:     time.Now(),
+			Body:         body,
+		})
+	}
+	return nil
+}
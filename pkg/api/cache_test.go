@@ -0,0 +1,190 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/cache"
+)
+
+// newCachingClient returns a client for baseURL with an empty cache
+func newCachingClient(t *testing.T, baseURL string) *Client {
+	t.Helper()
+	cacheUsage.oldest = time.Time{}
+	t.Cleanup(func() { cacheUsage.oldest = time.Time{} })
+
+	c := NewClient(baseURL)
+	c.SetRetryPolicy(RetryPolicy{})
+	c.cache = &responseCache{store: cache.New(t.TempDir()), profile: "dev"}
+	return c
+}
+
+// newETagServer serves {"n":1} with an ETag and answers If-None-Match with 304.
+// status, when non-zero, replaces every response.
+func newETagServer(t *testing.T, requests, notModified *atomic.Int32, status *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if s := status.Load(); s != 0 {
+			w.WriteHeader(int(s))
+			return
+		}
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte(`{"n":1}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGetCached_Revalidates(t *testing.T) {
+	var requests, notModified, status atomic.Int32
+	server := newETagServer(t, &requests, &notModified, &status)
+	c := newCachingClient(t, server.URL)
+
+	for range 2 {
+		var got struct{ N int }
+		if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil {
+			t.Fatalf("getCached() = %v", err)
+		}
+		if got.N != 1 {
+			t.Errorf("got %+v, want n=1", got)
+		}
+	}
+	if requests.Load() != 2 || notModified.Load() != 1 {
+		t.Errorf("requests = %d, 304s = %d; want 2 and 1", requests.Load(), notModified.Load())
+	}
+	if _, ok := CachedSince(); ok {
+		t.Error("CachedSince() reported cached data after a successful revalidation")
+	}
+}
+
+func TestGetCached_MaxAgeSkipsServer(t *testing.T) {
+	var requests, notModified, status atomic.Int32
+	server := newETagServer(t, &requests, &notModified, &status)
+	c := newCachingClient(t, server.URL)
+	c.cache.maxAge = time.Hour
+
+	var got struct{ N int }
+	for range 3 {
+		if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil {
+			t.Fatalf("getCached() = %v", err)
+		}
+	}
+	if requests.Load() != 1 {
+		t.Errorf("requests = %d, want 1", requests.Load())
+	}
+	if _, ok := CachedSince(); !ok {
+		t.Error("CachedSince() = false, want cached data reported")
+	}
+}
+
+func TestGetCached_Offline(t *testing.T) {
+	var requests, notModified, status atomic.Int32
+	server := newETagServer(t, &requests, &notModified, &status)
+	c := newCachingClient(t, server.URL)
+
+	var got struct{ N int }
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil {
+		t.Fatalf("getCached() = %v", err)
+	}
+
+	c.cache.offline = true
+	got.N = 0
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil || got.N != 1 {
+		t.Fatalf("offline getCached() = %+v, %v; want cached n=1", got, err)
+	}
+	if err := c.getCached(context.Background(), "/api/v1/users", &got); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline miss = %v, want ErrOffline", err)
+	}
+	if err := c.Get(context.Background(), "/api/v1/me", nil); !errors.Is(err, ErrOffline) {
+		t.Errorf("offline uncached GET = %v, want ErrOffline", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("requests = %d, want 1 (none while offline)", requests.Load())
+	}
+}
+
+func TestGetCached_FallsBackWhenServerFails(t *testing.T) {
+	var requests, notModified, status atomic.Int32
+	server := newETagServer(t, &requests, &notModified, &status)
+	c := newCachingClient(t, server.URL)
+
+	var got struct{ N int }
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil {
+		t.Fatalf("getCached() = %v", err)
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	got.N = 0
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil || got.N != 1 {
+		t.Fatalf("getCached() during outage = %+v, %v; want cached n=1", got, err)
+	}
+	if _, ok := CachedSince(); !ok {
+		t.Error("CachedSince() = false, want stale data reported")
+	}
+
+	// Client errors are not outages and are passed through
+	status.Store(http.StatusForbidden)
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err == nil {
+		t.Error("getCached() on 403 = nil, want error")
+	}
+}
+
+func TestGetCached_Bypass(t *testing.T) {
+	var requests, notModified, status atomic.Int32
+	server := newETagServer(t, &requests, &notModified, &status)
+	c := newCachingClient(t, server.URL)
+	c.cache.maxAge = time.Hour
+
+	if err := c.getCached(context.Background(), "/api/v1/teams", nil); err != nil {
+		t.Fatalf("getCached() = %v", err)
+	}
+
+	c.BypassCache()
+	var got struct{ N int }
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err != nil || got.N != 1 {
+		t.Fatalf("bypassed getCached() = %+v, %v; want n=1 from the server", got, err)
+	}
+	if requests.Load() != 2 || notModified.Load() != 0 {
+		t.Errorf("requests = %d, 304s = %d; want 2 and 0", requests.Load(), notModified.Load())
+	}
+
+	status.Store(http.StatusServiceUnavailable)
+	if err := c.getCached(context.Background(), "/api/v1/teams", &got); err == nil {
+		t.Error("bypassed getCached() during outage = nil, want error")
+	}
+	if _, ok := CachedSince(); ok {
+		t.Error("CachedSince() reported cached data with the cache bypassed")
+	}
+}
+
+func TestGetCached_NoStore(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Cache-Control", "private, no-store")
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := newCachingClient(t, server.URL)
+	c.cache.maxAge = time.Hour
+	for range 2 {
+		if err := c.getCached(context.Background(), "/api/v1/teams", nil); err != nil {
+			t.Fatalf("getCached() = %v", err)
+		}
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2 (no-store responses are not cached)", requests.Load())
+	}
+}
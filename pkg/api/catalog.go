@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -128,11 +129,12 @@ type ChangelogEntry struct {
 	Timestamp   string `json:"timestamp"`
 }
 
-// ListEntitiesOpts holds optional filters for listing entities
+// ListEntitiesOpts holds optional filters and paging for listing entities
 type ListEntitiesOpts struct {
 	Type   string
 	Search string
 	Owner  string
+	PageOptions
 }
 
 // entityOwnerRef matches the API owner array element: [{"id":"team-slug","type":"team"}]
@@ -146,6 +148,7 @@ type entityServiceInfo struct {
 	ID   string `json:"id"`
 	Name string `json:"name"`
 	Type string `json:"type"`
+	Tier string `json:"tier"`
 }
 
 // entityAPIItem matches a single entity from the API response
@@ -185,8 +188,10 @@ func parseOwner(raw json.RawMessage) string {
 	return ""
 }
 
-// ListEntities returns all entities matching the given filters (handles pagination)
-func (c *Client) ListEntities(ctx context.Context, opts ListEntitiesOpts) ([]*Entity, error) {
+// ListEntities returns entities matching the given filters, following the
+// cursor across pages until opts.Limit is reached. more reports whether
+// further entities were left unfetched.
+func (c *Client) ListEntities(ctx context.Context, opts ListEntitiesOpts) (entities []*Entity, more bool, err error) {
 	q := url.Values{}
 	if opts.Type != "" {
 		q.Set("type", opts.Type)
@@ -197,28 +202,32 @@ func (c *Client) ListEntities(ctx context.Context, opts ListEntitiesOpts) ([]*En
 	if opts.Owner != "" {
 		q.Set("owner", opts.Owner)
 	}
-	q.Set("limit", "100")
 
-	path := "/api/v1/entities?" + q.Encode()
+	return paginate(ctx, opts.PageOptions, func(ctx context.Context, size int, cursor string) ([]*Entity, string, error) {
+		q.Set("limit", strconv.Itoa(size))
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
 
-	var resp entitiesAPIResponse
-	if err := c.Get(ctx, path, &resp); err != nil {
-		return nil, err
-	}
+		var resp entitiesAPIResponse
+		if err := c.getCached(ctx, "/api/v1/entities?"+q.Encode(), &resp); err != nil {
+			return nil, "", err
+		}
 
-	entities := make([]*Entity, len(resp.Entities))
-	for i, raw := range resp.Entities {
-		entities[i] = &Entity{
-			ID:          raw.Service.ID,
-			Name:        raw.Service.Name,
-			Slug:        raw.Service.ID,
-			Type:        raw.Service.Type,
-			Owner:       parseOwner(raw.Owner),
-			Description: raw.Description,
-			Tags:        raw.Tags,
+		page := make([]*Entity, len(resp.Entities))
+		for i, raw := range resp.Entities {
+			page[i] = &Entity{
+				ID:          raw.Service.ID,
+				Name:        raw.Service.Name,
+				Slug:        raw.Service.ID,
+				Type:        raw.Service.Type,
+				Owner:       parseOwner(raw.Owner),
+				Description: raw.Description,
+				Tags:        raw.Tags,
+			}
 		}
-	}
-	return entities, nil
+		return page, resp.Page.NextCursor, nil
+	})
 }
 
 // entityDetailAPIResponse matches the single entity API response
@@ -242,7 +251,7 @@ func (c *Client) GetEntity(ctx context.Context, id string) (*EntityDetail, error
 	var wrapper struct {
 		Entity entityDetailAPIResponse `json:"entity"`
 	}
-	if err := c.Get(ctx, "/api/v1/entities/"+id, &wrapper); err != nil {
+	if err := c.getCached(ctx, "/api/v1/entities/"+id, &wrapper); err != nil {
 		return nil, err
 	}
 	raw := wrapper.Entity
@@ -264,15 +273,21 @@ func (c *Client) GetEntity(ctx context.Context, id string) (*EntityDetail, error
 		},
 		Links:     links,
 		Lifecycle: raw.Lifecycle,
+		Tier:      raw.Service.Tier,
 	}, nil
 }
 
+// DeleteEntity removes an entity from the catalog
+func (c *Client) DeleteEntity(ctx context.Context, id string) error {
+	return c.Delete(ctx, "/api/v1/entities/"+id)
+}
+
 // GetEntityResources fetches an entity's associated resources
 func (c *Client) GetEntityResources(ctx context.Context, id string) ([]*Resource, error) {
 	var resp struct {
 		Resources []Resource `json:"resources"`
 	}
-	if err := c.Get(ctx, fmt.Sprintf("/api/v1/entities/%s/resources", id), &resp); err != nil {
+	if err := c.getCached(ctx, fmt.Sprintf("/api/v1/entities/%s/resources", id), &resp); err != nil {
 		return nil, err
 	}
 	resources := make([]*Resource, len(resp.Resources))
@@ -286,7 +301,7 @@ func (c *Client) GetEntityResources(ctx context.Context, id string) ([]*Resource
 // GetEntityStatus fetches an entity's live health/status
 func (c *Client) GetEntityStatus(ctx context.Context, id string) (*EntityStatus, error) {
 	var resp EntityStatus
-	if err := c.Get(ctx, fmt.Sprintf("/api/v1/entities/%s/status", id), &resp); err != nil {
+	if err := c.getCached(ctx, fmt.Sprintf("/api/v1/entities/%s/status", id), &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -297,7 +312,7 @@ func (c *Client) GetEntityChangelog(ctx context.Context, id string) ([]*Changelo
 	var resp struct {
 		Entries []ChangelogEntry `json:"entries"`
 	}
-	if err := c.Get(ctx, fmt.Sprintf("/api/v1/entities/%s/changelog", id), &resp); err != nil {
+	if err := c.getCached(ctx, fmt.Sprintf("/api/v1/entities/%s/changelog", id), &resp); err != nil {
 		return nil, err
 	}
 	entries := make([]*ChangelogEntry, len(resp.Entries))
@@ -330,7 +345,7 @@ type ScorecardCheck struct {
 // GetEntityScorecard fetches an entity's scorecard
 func (c *Client) GetEntityScorecard(ctx context.Context, id string) (*Scorecard, error) {
 	var resp Scorecard
-	if err := c.Get(ctx, fmt.Sprintf("/api/v1/entities/%s/scorecard", id), &resp); err != nil {
+	if err := c.getCached(ctx, fmt.Sprintf("/api/v1/entities/%s/scorecard", id), &resp); err != nil {
 		return nil, err
 	}
 	return &resp, nil
@@ -364,20 +379,25 @@ type TeamMember struct {
 // TeamsResponse is the response from /teams
 type TeamsResponse struct {
 	Teams []Team `json:"teams"`
+	Total int    `json:"total"`
 }
 
-// ListTeams returns all teams
-func (c *Client) ListTeams(ctx context.Context) ([]*Team, error) {
-	var resp TeamsResponse
-	if err := c.Get(ctx, "/api/v1/teams", &resp); err != nil {
-		return nil, err
-	}
-	teams := make([]*Team, len(resp.Teams))
-	for i := range resp.Teams {
-		t := resp.Teams[i]
-		teams[i] = &t
-	}
-	return teams, nil
+// ListTeams returns teams, paging with limit/offset until page.Limit is
+// reached. more reports whether further teams were left unfetched.
+func (c *Client) ListTeams(ctx context.Context, page PageOptions) (teams []*Team, more bool, err error) {
+	return paginate(ctx, page, offsetPages(func(t *Team) string { return t.ID },
+		func(ctx context.Context, size, offset int) ([]*Team, int, error) {
+			var resp TeamsResponse
+			if err := c.getCached(ctx, "/api/v1/teams?"+offsetQuery(size, offset), &resp); err != nil {
+				return nil, 0, err
+			}
+			teams := make([]*Team, len(resp.Teams))
+			for i := range resp.Teams {
+				t := resp.Teams[i]
+				teams[i] = &t
+			}
+			return teams, resp.Total, nil
+		}))
 }
 
 // GetTeam fetches a team by ID or slug (including members)
@@ -386,7 +406,7 @@ func (c *Client) GetTeam(ctx context.Context, idOrSlug string) (*TeamDetail, err
 		Team    Team         `json:"team"`
 		Members []TeamMember `json:"members"`
 	}
-	if err := c.Get(ctx, "/api/v1/teams/"+idOrSlug, &wrapper); err != nil {
+	if err := c.getCached(ctx, "/api/v1/teams/"+idOrSlug, &wrapper); err != nil {
 		return nil, err
 	}
 	return &TeamDetail{
@@ -427,33 +447,38 @@ type userAPIItem struct {
 // usersAPIResponse matches the actual API response for /users
 type usersAPIResponse struct {
 	Items []userAPIItem `json:"items"`
+	Total int           `json:"total"`
 }
 
-// ListUsers returns all users in the directory
-func (c *Client) ListUsers(ctx context.Context) ([]*User, error) {
-	var resp usersAPIResponse
-	if err := c.Get(ctx, "/api/v1/users", &resp); err != nil {
-		return nil, err
-	}
-	users := make([]*User, len(resp.Items))
-	for i, u := range resp.Items {
-		name := strings.TrimSpace(u.FirstName + " " + u.LastName)
-		if name == "" {
-			name = u.Username
-		}
-		users[i] = &User{
-			ID:    u.ID,
-			Email: u.Email,
-			Name:  name,
-		}
-	}
-	return users, nil
+// ListUsers returns users in the directory, paging with limit/offset until
+// page.Limit is reached. more reports whether further users were left unfetched.
+func (c *Client) ListUsers(ctx context.Context, page PageOptions) (users []*User, more bool, err error) {
+	return paginate(ctx, page, offsetPages(func(u *User) string { return u.ID },
+		func(ctx context.Context, size, offset int) ([]*User, int, error) {
+			var resp usersAPIResponse
+			if err := c.getCached(ctx, "/api/v1/users?"+offsetQuery(size, offset), &resp); err != nil {
+				return nil, 0, err
+			}
+			users := make([]*User, len(resp.Items))
+			for i, u := range resp.Items {
+				name := strings.TrimSpace(u.FirstName + " " + u.LastName)
+				if name == "" {
+					name = u.Username
+				}
+				users[i] = &User{
+					ID:    u.ID,
+					Email: u.Email,
+					Name:  name,
+				}
+			}
+			return users, resp.Total, nil
+		}))
 }
 
 // GetUser fetches a single user by ID
 func (c *Client) GetUser(ctx context.Context, id string) (*UserDetail, error) {
 	var raw userAPIItem
-	if err := c.Get(ctx, "/api/v1/users/"+id, &raw); err != nil {
+	if err := c.getCached(ctx, "/api/v1/users/"+id, &raw); err != nil {
 		return nil, err
 	}
 	name := strings.TrimSpace(raw.FirstName + " " + raw.LastName)
@@ -497,6 +522,7 @@ type groupAPIItem struct {
 // groupsAPIResponse matches the actual API response for /groups
 type groupsAPIResponse struct {
 	Items []groupAPIItem `json:"items"`
+	Total int            `json:"total"`
 }
 
 // RolesResponse is the response from /groups/{name}/roles
@@ -504,26 +530,30 @@ type RolesResponse struct {
 	Roles []Role `json:"roles"`
 }
 
-// ListGroups returns all groups
-func (c *Client) ListGroups(ctx context.Context) ([]*Group, error) {
-	var resp groupsAPIResponse
-	if err := c.Get(ctx, "/api/v1/groups", &resp); err != nil {
-		return nil, err
-	}
-	groups := make([]*Group, len(resp.Items))
-	for i, g := range resp.Items {
-		groups[i] = &Group{
-			Name:      g.Name,
-			RoleCount: len(g.Roles),
-		}
-	}
-	return groups, nil
+// ListGroups returns directory groups, paging with limit/offset until
+// page.Limit is reached. more reports whether further groups were left unfetched.
+func (c *Client) ListGroups(ctx context.Context, page PageOptions) (groups []*Group, more bool, err error) {
+	return paginate(ctx, page, offsetPages(func(g *Group) string { return g.Name },
+		func(ctx context.Context, size, offset int) ([]*Group, int, error) {
+			var resp groupsAPIResponse
+			if err := c.getCached(ctx, "/api/v1/groups?"+offsetQuery(size, offset), &resp); err != nil {
+				return nil, 0, err
+			}
+			groups := make([]*Group, len(resp.Items))
+			for i, g := range resp.Items {
+				groups[i] = &Group{
+					Name:      g.Name,
+					RoleCount: len(g.Roles),
+				}
+			}
+			return groups, resp.Total, nil
+		}))
 }
 
 // GetGroupRoles fetches the roles mapped to a group
 func (c *Client) GetGroupRoles(ctx context.Context, groupName string) ([]*Role, error) {
 	var resp RolesResponse
-	if err := c.Get(ctx, fmt.Sprintf("/api/v1/groups/%s/roles", groupName), &resp); err != nil {
+	if err := c.getCached(ctx, fmt.Sprintf("/api/v1/groups/%s/roles", groupName), &resp); err != nil {
 		return nil, err
 	}
 	roles := make([]*Role, len(resp.Roles))
@@ -574,7 +604,7 @@ func (c *Client) Search(ctx context.Context, query string) (*SearchResult, error
 	q := url.Values{}
 	q.Set("q", query)
 	var resp searchAPIResponse
-	if err := c.Get(ctx, "/api/v1/search?"+q.Encode(), &resp); err != nil {
+	if err := c.getCached(ctx, "/api/v1/search?"+q.Encode(), &resp); err != nil {
 		return nil, err
 	}
 
@@ -619,6 +649,7 @@ type k8sAgentAPIItem struct {
 // k8sAgentsAPIResponse matches the actual API response for /k8s/agents
 type k8sAgentsAPIResponse struct {
 	Agents []k8sAgentAPIItem `json:"agents"`
+	Total  int               `json:"total"`
 }
 
 // formatLastSeen formats a time pointer as a human-readable string
@@ -639,23 +670,27 @@ func formatLastSeen(t *time.Time) string {
 	}
 }
 
-// ListK8sAgents returns all registered K8s agents
-func (c *Client) ListK8sAgents(ctx context.Context) ([]*K8sAgent, error) {
-	var resp k8sAgentsAPIResponse
-	if err := c.Get(ctx, "/api/v1/k8s/agents", &resp); err != nil {
-		return nil, err
-	}
-	agents := make([]*K8sAgent, len(resp.Agents))
-	for i, raw := range resp.Agents {
-		agents[i] = &K8sAgent{
-			ID:          strconv.Itoa(raw.ID),
-			ClusterName: raw.ClusterID,
-			Status:      raw.OnlineStatus,
-			Version:     raw.Name,
-			LastSeen:    formatLastSeen(raw.LastHeartbeat),
-		}
-	}
-	return agents, nil
+// ListK8sAgents returns registered K8s agents, paging with limit/offset until
+// page.Limit is reached. more reports whether further agents were left unfetched.
+func (c *Client) ListK8sAgents(ctx context.Context, page PageOptions) (agents []*K8sAgent, more bool, err error) {
+	return paginate(ctx, page, offsetPages(func(a *K8sAgent) string { return a.ID },
+		func(ctx context.Context, size, offset int) ([]*K8sAgent, int, error) {
+			var resp k8sAgentsAPIResponse
+			if err := c.getCached(ctx, "/api/v1/k8s/agents?"+offsetQuery(size, offset), &resp); err != nil {
+				return nil, 0, err
+			}
+			agents := make([]*K8sAgent, len(resp.Agents))
+			for i, raw := range resp.Agents {
+				agents[i] = &K8sAgent{
+					ID:          strconv.Itoa(raw.ID),
+					ClusterName: raw.ClusterID,
+					Status:      raw.OnlineStatus,
+					Version:     raw.Name,
+					LastSeen:    formatLastSeen(raw.LastHeartbeat),
+				}
+			}
+			return agents, resp.Total, nil
+		}))
 }
 
 // ─── Forge ───────────────────────────────────────────────────────────────────
@@ -679,11 +714,12 @@ type MoldAction struct {
 
 // MoldInput describes a single input parameter for a mold
 type MoldInput struct {
-	Name        string `json:"name"`
-	Type        string `json:"type"`
-	Required    bool   `json:"required"`
-	Description string `json:"description"`
-	Default     string `json:"default"`
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Required    bool     `json:"required"`
+	Description string   `json:"description"`
+	Default     string   `json:"default"`
+	Enum        []string `json:"enum,omitempty"`
 }
 
 // MoldStep describes a single step in a mold
@@ -698,6 +734,8 @@ type MoldDetail struct {
 	Actions []MoldAction `json:"actions"`
 	Inputs  []MoldInput  `json:"inputs"`
 	Steps   []MoldStep   `json:"steps"`
+	// Schema is the JSON Schema the mold's inputs must satisfy
+	Schema map[string]any `json:"schema,omitempty"`
 }
 
 // moldAPIResponse matches the backend mold JSON (camelCase keys)
@@ -730,15 +768,16 @@ func parseMoldInputs(schema map[string]any, inputOrder []string, defaults map[st
 		}
 	}
 
-	// Determine field order: use inputOrder if available, otherwise sorted keys
-	var orderedKeys []string
-	if len(inputOrder) > 0 {
-		orderedKeys = inputOrder
-	} else {
-		for k := range props {
-			orderedKeys = append(orderedKeys, k)
+	// Determine field order: inputOrder first, then any remaining keys sorted
+	orderedKeys := slices.Clone(inputOrder)
+	var rest []string
+	for k := range props {
+		if !slices.Contains(inputOrder, k) {
+			rest = append(rest, k)
 		}
 	}
+	slices.Sort(rest)
+	orderedKeys = append(orderedKeys, rest...)
 
 	var inputs []MoldInput
 	for _, key := range orderedKeys {
@@ -762,11 +801,16 @@ func parseMoldInputs(schema map[string]any, inputOrder []string, defaults map[st
 		if d, ok := prop["description"].(string); ok {
 			inp.Description = d
 		}
+		if enum, ok := prop["enum"].([]any); ok {
+			for _, v := range enum {
+				inp.Enum = append(inp.Enum, fmt.Sprintf("%v", v))
+			}
+		}
 		if def, ok := prop["default"]; ok {
-			inp.Default = fmt.Sprintf("%v", def)
+			inp.Default = formatDefault(def)
 		} else if defaults != nil {
 			if def, ok := defaults[key]; ok {
-				inp.Default = fmt.Sprintf("%v", def)
+				inp.Default = formatDefault(def)
 			}
 		}
 
@@ -775,24 +819,40 @@ func parseMoldInputs(schema map[string]any, inputOrder []string, defaults map[st
 	return inputs
 }
 
+// formatDefault renders a default value as a string; arrays and objects as
+// JSON, so that they can be decoded again
+func formatDefault(v any) string {
+	switch v.(type) {
+	case []any, map[string]any:
+		data, err := json.Marshal(v)
+		if err == nil {
+			return string(data)
+		}
+	}
+	return fmt.Sprintf("%v", v)
+}
+
 // MoldsResponse is the response from /forge/molds
 type MoldsResponse struct {
 	Molds []Mold `json:"molds"`
+	Total int    `json:"total"`
 }
 
 // ForgeRun represents a workflow run (canonical type for the api package)
 type ForgeRun struct {
-	ID          string `json:"id"`
-	Action      string `json:"action"`
-	MoldSlug    string `json:"mold_slug"`
-	Status      string `json:"status"`
-	DryRun      bool   `json:"dry_run"`
-	CreatedBy   string `json:"created_by"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at,omitempty"`
-	StartedAt   string `json:"started_at,omitempty"`
-	CompletedAt string `json:"completed_at,omitempty"`
-	Error       string `json:"error,omitempty"`
+	ID          string         `json:"id"`
+	Action      string         `json:"action"`
+	MoldSlug    string         `json:"mold_slug"`
+	Status      string         `json:"status"`
+	DryRun      bool           `json:"dry_run"`
+	CreatedBy   string         `json:"created_by"`
+	CreatedAt   string         `json:"created_at"`
+	UpdatedAt   string         `json:"updated_at,omitempty"`
+	StartedAt   string         `json:"started_at,omitempty"`
+	CompletedAt string         `json:"completed_at,omitempty"`
+	Error       string         `json:"error,omitempty"`
+	Inputs      map[string]any `json:"inputs,omitempty"`
+	Steps       []RunStep      `json:"steps,omitempty"`
 }
 
 // ForgeRunsResponse is the response from /forge/runs
@@ -813,18 +873,22 @@ type CreateRunRequest struct {
 	DryRun   bool           `json:"dry_run,omitempty"`
 }
 
-// ListMolds returns all forge molds
-func (c *Client) ListMolds(ctx context.Context) ([]*Mold, error) {
-	var resp MoldsResponse
-	if err := c.Get(ctx, "/api/v1/forge/molds", &resp); err != nil {
-		return nil, err
-	}
-	molds := make([]*Mold, len(resp.Molds))
-	for i := range resp.Molds {
-		m := resp.Molds[i]
-		molds[i] = &m
-	}
-	return molds, nil
+// ListMolds returns forge molds, paging with limit/offset until page.Limit is
+// reached. more reports whether further molds were left unfetched.
+func (c *Client) ListMolds(ctx context.Context, page PageOptions) (molds []*Mold, more bool, err error) {
+	return paginate(ctx, page, offsetPages(func(m *Mold) string { return m.ID },
+		func(ctx context.Context, size, offset int) ([]*Mold, int, error) {
+			var resp MoldsResponse
+			if err := c.getCached(ctx, "/api/v1/forge/molds?"+offsetQuery(size, offset), &resp); err != nil {
+				return nil, 0, err
+			}
+			molds := make([]*Mold, len(resp.Molds))
+			for i := range resp.Molds {
+				m := resp.Molds[i]
+				molds[i] = &m
+			}
+			return molds, resp.Total, nil
+		}))
 }
 
 // GetMold fetches a single mold by slug
@@ -832,7 +896,7 @@ func (c *Client) GetMold(ctx context.Context, slug string) (*MoldDetail, error)
 	var wrapper struct {
 		Mold moldAPIResponse `json:"mold"`
 	}
-	if err := c.Get(ctx, "/api/v1/forge/molds/"+slug, &wrapper); err != nil {
+	if err := c.getCached(ctx, "/api/v1/forge/molds/"+slug, &wrapper); err != nil {
 		return nil, err
 	}
 	raw := wrapper.Mold
@@ -848,6 +912,7 @@ func (c *Client) GetMold(ctx context.Context, slug string) (*MoldDetail, error)
 		},
 		Actions: raw.Actions,
 		Inputs:  inputs,
+		Schema:  raw.Schema,
 	}, nil
 }
 
@@ -868,13 +933,37 @@ func (c *Client) CreateRun(ctx context.Context, moldSlug, action string, inputs
 	return &wrapper.Run, nil
 }
 
-// ListRuns returns forge workflow runs
-func (c *Client) ListRuns(ctx context.Context) (*ForgeRunsResponse, error) {
-	var resp ForgeRunsResponse
-	if err := c.Get(ctx, "/api/v1/forge/runs", &resp); err != nil {
+// ListRuns returns forge workflow runs, following the cursor across pages
+// until page.Limit is reached. Pagination.HasMore reports whether further runs
+// were left unfetched.
+func (c *Client) ListRuns(ctx context.Context, page PageOptions) (*ForgeRunsResponse, error) {
+	var total int64
+	runs, more, err := paginate(ctx, page, func(ctx context.Context, size int, cursor string) ([]ForgeRun, string, error) {
+		q := url.Values{}
+		q.Set("limit", strconv.Itoa(size))
+		if cursor != "" {
+			q.Set("cursor", cursor)
+		}
+
+		var resp ForgeRunsResponse
+		if err := c.Get(ctx, "/api/v1/forge/runs?"+q.Encode(), &resp); err != nil {
+			return nil, "", err
+		}
+		total = resp.Pagination.TotalCount
+		next := resp.Pagination.NextCursor
+		if !resp.Pagination.HasMore {
+			next = ""
+		}
+		return resp.Runs, next, nil
+	})
+	if err != nil {
 		return nil, err
 	}
-	return &resp, nil
+
+	resp := &ForgeRunsResponse{Runs: runs}
+	resp.Pagination.TotalCount = total
+	resp.Pagination.HasMore = more
+	return resp, nil
 }
 
 // GetRun fetches a single forge run by ID
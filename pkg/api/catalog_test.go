@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestGetEntity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/entities/orders" {
+			t.Errorf("unexpected request: %s", r.URL.Path)
+		}
+		w.Write([]byte(`{"entity":{"service":{"id":"orders","name":"Orders","type":"service","tier":"1"},
+			"owner":[{"type":"team","id":"payments"}],"lifecycle":"production",
+			"links":[{"name":"Runbook","url":"https://runbooks.example.com"}]}}`))
+	}))
+	defer server.Close()
+
+	e, err := NewClient(server.URL).GetEntity(context.Background(), "orders")
+	if err != nil {
+		t.Fatalf("GetEntity() = %v", err)
+	}
+	if e.ID != "orders" || e.Owner != "payments" || e.Tier != "1" || e.Lifecycle != "production" {
+		t.Errorf("entity = %+v, want orders owned by payments, tier 1, production", e)
+	}
+	if len(e.Links) != 1 || e.Links[0].Title != "Runbook" {
+		t.Errorf("links = %+v, want Runbook", e.Links)
+	}
+}
+
+func TestDeleteEntity(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete || r.URL.Path != "/api/v1/entities/orders" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := NewClient(server.URL).DeleteEntity(context.Background(), "orders"); err != nil {
+		t.Fatalf("DeleteEntity() = %v", err)
+	}
+}
+
+func TestParseMoldInputs(t *testing.T) {
+	var schema map[string]any
+	json.Unmarshal([]byte(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"visibility": {"type": "string", "enum": ["public", "private"], "default": "private"},
+			"replicas": {"type": "integer", "enum": [1, 3]},
+			"name": {"type": "string", "description": "Repository name"},
+			"archived": {"type": "boolean"},
+			"topics": {"type": "array", "default": ["go", "grpc"]}
+		}
+	}`), &schema)
+
+	got := parseMoldInputs(schema, []string{"name", "visibility"}, map[string]any{"archived": false})
+	want := []MoldInput{
+		{Name: "name", Type: "string", Required: true, Description: "Repository name"},
+		{Name: "visibility", Type: "string", Default: "private", Enum: []string{"public", "private"}},
+		// Inputs missing from the order follow it, sorted
+		{Name: "archived", Type: "boolean", Default: "false"},
+		{Name: "replicas", Type: "integer", Enum: []string{"1", "3"}},
+		{Name: "topics", Type: "array", Default: `["go","grpc"]`},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseMoldInputs() =\n  %+v\nwant\n  %+v", got, want)
+	}
+}
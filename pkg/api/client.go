@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/shoehorn-dev/cli/pkg/config"
@@ -20,7 +21,17 @@ var loadConfig = config.Load
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
-	token      string
+
+	tokenMu sync.RWMutex
+	token   string
+
+	// refresher renews expired OIDC tokens; nil for PATs and unauthenticated clients
+	refresher *tokenRefresher
+
+	retry RetryPolicy
+
+	// cache serves and stores catalog reads; nil disables caching
+	cache *responseCache
 }
 
 // NewClient creates a new API client
@@ -30,60 +41,103 @@ func NewClient(baseURL string) *Client {
 		httpClient: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		retry: DefaultRetryPolicy,
 	}
 }
 
 // SetToken sets the Bearer token for authenticated requests
 func (c *Client) SetToken(token string) {
+	c.tokenMu.Lock()
+	defer c.tokenMu.Unlock()
 	c.token = token
 }
 
 // GetToken returns the current token
 func (c *Client) GetToken() string {
+	c.tokenMu.RLock()
+	defer c.tokenMu.RUnlock()
 	return c.token
 }
 
-// do executes an HTTP request and handles the response
-func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
-	var reqBody io.Reader
+// send executes an HTTP request and returns the status code and raw body.
+// An expired OIDC token is refreshed before the request, and a 401 response
+// triggers one refresh and retry. Transient failures of idempotent requests
+// are retried according to the client's RetryPolicy. header holds extra
+// request headers and may be nil.
+func (c *Client) send(ctx context.Context, method, path string, body any, header http.Header) (int, http.Header, []byte, error) {
+	if c.cache != nil && c.cache.offline {
+		return 0, nil, nil, fmt.Errorf("%s %s: %w", method, path, ErrOffline)
+	}
+
+	var payload []byte
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return fmt.Errorf("marshal request: %w", err)
+			return 0, nil, nil, fmt.Errorf("marshal request: %w", err)
 		}
-		reqBody = bytes.NewReader(jsonData)
+		payload = jsonData
+	}
+
+	if err := c.refreshIfExpired(ctx); err != nil {
+		return 0, nil, nil, err
+	}
+
+	token := c.GetToken()
+	status, respHeader, respBody, err := c.attempt(ctx, method, path, payload, header, token)
+	if err != nil || status != http.StatusUnauthorized || c.refresher == nil {
+		return status, respHeader, respBody, err
+	}
+
+	// The server rejected the token before its recorded expiry (revoked or clock skew)
+	if err := c.refreshToken(ctx, token); err != nil {
+		return 0, nil, nil, err
+	}
+	return c.attempt(ctx, method, path, payload, header, c.GetToken())
+}
+
+// roundTrip performs a single request with the given headers and token
+func (c *Client) roundTrip(ctx context.Context, method, path string, payload []byte, header http.Header, token string) (int, http.Header, []byte, error) {
+	var reqBody io.Reader
+	if payload != nil {
+		reqBody = bytes.NewReader(payload)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return 0, nil, nil, fmt.Errorf("create request: %w", err)
 	}
 
+	for k, v := range header {
+		req.Header[k] = v
+	}
 	req.Header.Set("Content-Type", "application/json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
 	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("do request: %w", err)
+		return 0, nil, nil, fmt.Errorf("do request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	// Read response body for error messages
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return fmt.Errorf("read response: %w", err)
+		return resp.StatusCode, resp.Header, nil, fmt.Errorf("read response: %w", err)
+	}
+	return resp.StatusCode, resp.Header, respBody, nil
+}
+
+// do executes an HTTP request and handles the response
+func (c *Client) do(ctx context.Context, method, path string, body, result any) error {
+	status, header, respBody, err := c.send(ctx, method, path, body, nil)
+	if err != nil {
+		return err
 	}
 
 	// Handle non-2xx status codes
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		var errResp ErrorResponse
-		if err := json.Unmarshal(respBody, &errResp); err != nil {
-			// Couldn't parse error response, return raw status
-			return fmt.Errorf("API error (%d): %s", resp.StatusCode, string(respBody))
-		}
-		return fmt.Errorf("API error (%d): %s", resp.StatusCode, errResp.Error.Message)
+	if status < 200 || status >= 300 {
+		return newAPIError(status, header, respBody)
 	}
 
 	// Decode success response
@@ -99,43 +153,18 @@ func (c *Client) do(ctx context.Context, method, path string, body, result any)
 // doIgnoreStatus performs an HTTP request and decodes the body into result
 // regardless of HTTP status code. Returns the status code alongside any error.
 func (c *Client) doIgnoreStatus(ctx context.Context, method, path string, body, result any) (int, error) {
-	var reqBody io.Reader
-	if body != nil {
-		jsonData, err := json.Marshal(body)
-		if err != nil {
-			return 0, fmt.Errorf("marshal request: %w", err)
-		}
-		reqBody = bytes.NewReader(jsonData)
-	}
-
-	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reqBody)
-	if err != nil {
-		return 0, fmt.Errorf("create request: %w", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
-	}
-
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return 0, fmt.Errorf("do request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	respBody, err := io.ReadAll(resp.Body)
+	status, _, respBody, err := c.send(ctx, method, path, body, nil)
 	if err != nil {
-		return resp.StatusCode, fmt.Errorf("read response: %w", err)
+		return status, err
 	}
 
 	if result != nil && len(respBody) > 0 {
 		if err := json.Unmarshal(respBody, result); err != nil {
-			return resp.StatusCode, fmt.Errorf("decode response: %w", err)
+			return status, fmt.Errorf("decode response: %w", err)
 		}
 	}
 
-	return resp.StatusCode, nil
+	return status, nil
 }
 
 // Get performs a GET request
@@ -159,28 +188,52 @@ func (c *Client) Delete(ctx context.Context, path string) error {
 }
 
 // NewClientFromConfig creates an API client from the current config profile.
+// OIDC profiles with a refresh token renew expired access tokens transparently,
+// and catalog reads go through the profile's on-disk response cache.
 // Returns an error if not authenticated.
 func NewClientFromConfig() (*Client, error) {
 	cfg, err := loadConfig()
 	if err != nil {
 		return nil, fmt.Errorf("load config: %w", err)
 	}
-	if !cfg.IsAuthenticated() {
-		return nil, fmt.Errorf("not authenticated — run: shoehorn auth login --token <PAT>")
-	}
-	profile, err := cfg.GetCurrentProfile()
+	return newClientForProfile(cfg, cfg.ActiveProfileName())
+}
+
+// NewClientForProfile creates an authenticated client for the named profile,
+// regardless of which profile is active. Its cache and token refreshes are
+// scoped to that profile.
+func NewClientForProfile(name string) (*Client, error) {
+	cfg, err := loadConfig()
 	if err != nil {
-		return nil, fmt.Errorf("get profile: %w", err)
+		return nil, fmt.Errorf("load config: %w", err)
+	}
+	if err := cfg.LoadCredentials(name); err != nil {
+		return nil, err
+	}
+	return newClientForProfile(cfg, name)
+}
+
+func newClientForProfile(cfg *config.Config, name string) (*Client, error) {
+	profile := cfg.Profiles[name]
+	if profile == nil {
+		return nil, fmt.Errorf("get profile: profile '%s' not found", name)
+	}
+	if profile.Auth == nil || profile.Auth.AccessToken == "" {
+		return nil, ErrNotAuthenticated
 	}
 	c := NewClient(profile.Server)
 	c.SetToken(profile.Auth.AccessToken)
+	c.refresher = newProfileRefresher(name, profile.Auth)
+	c.retry = resolveRetryPolicy(profile.Retry)
+	c.cache = newProfileCache(name)
 	return c, nil
 }
 
 // ErrorResponse represents an API error response
 type ErrorResponse struct {
 	Error struct {
-		Message string `json:"message"`
-		Code    string `json:"code,omitempty"`
+		Message   string `json:"message"`
+		Code      string `json:"code,omitempty"`
+		RequestID string `json:"request_id,omitempty"`
 	} `json:"error"`
 }
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrNotAuthenticated is returned when the active profile has no usable credentials
+var ErrNotAuthenticated = errors.New("not authenticated — run: shoehorn auth login")
+
+// APIError is a non-2xx response from the Shoehorn API
+type APIError struct {
+	StatusCode int    // HTTP status code
+	Code       string // server error code, e.g. "not_found"; empty if the body had none
+	Message    string // server message, or the raw body when it was not an ErrorResponse
+	RequestID  string // server request ID for support, from the body or X-Request-Id
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("API error (%d): %s", e.StatusCode, e.Message)
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request ID: %s)", e.RequestID)
+	}
+	return msg
+}
+
+// IsNotFound reports whether err is an API 404 response
+func IsNotFound(err error) bool {
+	var apiErr *APIError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// newAPIError builds an APIError from a failed response
+func newAPIError(status int, header http.Header, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: status,
+		Message:    strings.TrimSpace(string(body)),
+		RequestID:  header.Get("X-Request-Id"),
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error.Message != "" {
+		apiErr.Message = errResp.Error.Message
+		apiErr.Code = errResp.Error.Code
+		if errResp.Error.RequestID != "" {
+			apiErr.RequestID = errResp.Error.RequestID
+		}
+	}
+	if apiErr.Message == "" {
+		apiErr.Message = http.StatusText(status)
+	}
+	return apiErr
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestClient_ReturnsAPIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "req-header")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"message":"entity not found","code":"not_found","request_id":"req-body"}}`))
+	}))
+	defer server.Close()
+
+	err := NewClient(server.URL).Get(context.Background(), "/api/v1/entities/x", nil)
+
+	var apiErr *APIError
+	if !errors.As(fmt.Errorf("get entity: %w", err), &apiErr) {
+		t.Fatalf("error %v is not an *APIError", err)
+	}
+	if apiErr.StatusCode != 404 || apiErr.Code != "not_found" || apiErr.Message != "entity not found" {
+		t.Errorf("APIError = %+v", apiErr)
+	}
+	if apiErr.RequestID != "req-body" {
+		t.Errorf("RequestID = %q, want the body's request_id", apiErr.RequestID)
+	}
+	if !strings.Contains(err.Error(), "req-body") {
+		t.Errorf("Error() = %q, want request ID included", err.Error())
+	}
+	if !IsNotFound(fmt.Errorf("wrapped: %w", err)) {
+		t.Error("IsNotFound() = false for wrapped 404")
+	}
+}
+
+func TestNewAPIError_NonJSONBody(t *testing.T) {
+	header := http.Header{}
+	header.Set("X-Request-Id", "req-123")
+
+	apiErr := newAPIError(http.StatusBadGateway, header, []byte("<html>bad gateway</html>\n"))
+	if apiErr.Message != "<html>bad gateway</html>" || apiErr.Code != "" {
+		t.Errorf("APIError = %+v, want raw body as message", apiErr)
+	}
+	if apiErr.RequestID != "req-123" {
+		t.Errorf("RequestID = %q, want header value", apiErr.RequestID)
+	}
+
+	if got := newAPIError(http.StatusServiceUnavailable, http.Header{}, nil).Message; got != "Service Unavailable" {
+		t.Errorf("empty body message = %q, want status text", got)
+	}
+}
+
+func TestNewClientFromConfig_NotAuthenticated(t *testing.T) {
+	withTestConfig(t, `version: "1.0"
+current_profile: default
+credential_store: plaintext
+profiles:
+  default:
+    name: Default
+    server: http://localhost:8080
+`)
+
+	if _, err := NewClientFromConfig(); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("NewClientFromConfig() = %v, want ErrNotAuthenticated", err)
+	}
+}
+
+func TestNewClientForProfile(t *testing.T) {
+	withTestConfig(t, `version: "1.0"
+current_profile: default
+credential_store: plaintext
+profiles:
+  default:
+    name: Default
+    server: http://localhost:8080
+  prod:
+    name: Production
+    server: https://prod.example.com
+    auth:
+      provider_type: pat
+      access_token: shp_prod
+`)
+
+	c, err := NewClientForProfile("prod")
+	if err != nil {
+		t.Fatalf("NewClientForProfile(prod) = %v", err)
+	}
+	if c.baseURL != "https://prod.example.com" || c.GetToken() != "shp_prod" {
+		t.Errorf("client = %s with token %q, want prod server and token", c.baseURL, c.GetToken())
+	}
+
+	if _, err := NewClientForProfile("default"); !errors.Is(err, ErrNotAuthenticated) {
+		t.Errorf("NewClientForProfile(default) = %v, want ErrNotAuthenticated", err)
+	}
+	if _, err := NewClientForProfile("missing"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("NewClientForProfile(missing) = %v, want not found", err)
+	}
+}
@@ -2,7 +2,6 @@ package api
 
 import (
 	"context"
-	"fmt"
 )
 
 // ManifestValidationResult represents the validation result from the API
@@ -11,10 +10,14 @@ type ManifestValidationResult struct {
 	Errors []ManifestValidationError `json:"errors"`
 }
 
-// ManifestValidationError represents a validation error
+// ManifestValidationError represents a validation error. Line and Column
+// locate Field in the manifest source (1-based); they are filled in by the
+// CLI and zero when unknown.
 type ManifestValidationError struct {
 	Field   string `json:"field"`
 	Message string `json:"message"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
 }
 
 // ManifestConversionRequest represents a conversion request
@@ -60,12 +63,12 @@ func (c *Client) ValidateManifest(ctx context.Context, content string) (*Validat
 
 	// 5xx = server error
 	if statusCode >= 500 {
-		return nil, fmt.Errorf("API server error (%d)", statusCode)
+		return nil, &APIError{StatusCode: statusCode, Message: "server error"}
 	}
 
 	// 401/403 = auth error
 	if statusCode == 401 || statusCode == 403 {
-		return nil, fmt.Errorf("API error (%d): not authorized", statusCode)
+		return nil, &APIError{StatusCode: statusCode, Message: "not authorized"}
 	}
 
 	// 2xx or 4xx with a parsed body = validation result (valid or invalid)
@@ -87,3 +90,29 @@ func (c *Client) ConvertManifest(ctx context.Context, content string, targetType
 
 	return &resp, nil
 }
+
+// ApplyManifestRequest is the body for POST /manifests/apply
+type ApplyManifestRequest struct {
+	Content string `json:"content"`
+}
+
+// ApplyManifestResponse reports what applying a manifest changed
+type ApplyManifestResponse struct {
+	EntityID string `json:"entityId"`
+	Action   string `json:"action"` // "created", "updated", or "unchanged"
+}
+
+// ApplyManifest creates or updates the catalog entity described by a manifest.
+// Invalid manifests are rejected with a 400/422 APIError.
+func (c *Client) ApplyManifest(ctx context.Context, content string) (*ApplyManifestResponse, error) {
+	req := ApplyManifestRequest{
+		Content: content,
+	}
+
+	var resp ApplyManifestResponse
+	if err := c.Post(ctx, "/api/v1/manifests/apply", req, &resp); err != nil {
+		return nil, err
+	}
+
+	return &resp, nil
+}
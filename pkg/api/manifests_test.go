@@ -0,0 +1,47 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestApplyManifest(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/manifests/apply" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req ApplyManifestRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Content != "service:\n  id: orders\n" {
+			t.Errorf("content = %q", req.Content)
+		}
+		w.Write([]byte(`{"entityId":"orders","action":"created"}`))
+	}))
+	defer server.Close()
+
+	resp, err := NewClient(server.URL).ApplyManifest(context.Background(), "service:\n  id: orders\n")
+	if err != nil {
+		t.Fatalf("ApplyManifest() = %v", err)
+	}
+	if resp.EntityID != "orders" || resp.Action != "created" {
+		t.Errorf("response = %+v, want orders created", resp)
+	}
+}
+
+func TestApplyManifest_Invalid(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		w.Write([]byte(`{"error":"service.id is required"}`))
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).ApplyManifest(context.Background(), "service: {}\n")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusUnprocessableEntity {
+		t.Errorf("ApplyManifest() = %v, want 422 APIError", err)
+	}
+}
@@ -0,0 +1,459 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// DefaultOAuthScopes are requested when the server does not advertise any
+var DefaultOAuthScopes = []string{"openid", "profile", "email", "offline_access"}
+
+// devicePollUnit scales the device-flow polling interval (tests shorten it)
+var devicePollUnit = time.Second
+
+// loopbackTimeout is how long LoopbackLogin waits for the browser to come
+// back to the loopback listener (tests shorten it)
+var loopbackTimeout = 5 * time.Minute
+
+// ─── Server-advertised CLI auth settings ────────────────────────────────────
+
+// CLIAuthConfig tells the CLI which OIDC issuer and client to use for interactive login
+type CLIAuthConfig struct {
+	Issuer   string   `json:"issuer"`
+	ClientID string   `json:"client_id"`
+	Scopes   []string `json:"scopes,omitempty"`
+}
+
+// GetCLIAuthConfig fetches the OIDC issuer and public client ID (unauthenticated)
+func (c *Client) GetCLIAuthConfig(ctx context.Context) (*CLIAuthConfig, error) {
+	var resp CLIAuthConfig
+	if err := c.Get(ctx, "/api/v1/auth/cli/config", &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ─── OIDC / OAuth 2.0 ───────────────────────────────────────────────────────
+
+// OIDCConfig is the subset of the OpenID Provider metadata the CLI uses
+type OIDCConfig struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	RevocationEndpoint          string `json:"revocation_endpoint"`
+}
+
+// DeviceAuthorization is the response from the device authorization endpoint (RFC 8628)
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete,omitempty"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval,omitempty"`
+}
+
+// TokenResponse is a successful token endpoint response
+type TokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	IDToken      string `json:"id_token,omitempty"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in,omitempty"`
+}
+
+// ExpiresAt converts ExpiresIn to an absolute time. Zero means no expiry was given.
+func (t *TokenResponse) ExpiresAt() time.Time {
+	if t.ExpiresIn <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+}
+
+// OAuthError is an error response from an OAuth endpoint
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description,omitempty"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth error %s: %s", e.Code, e.Description)
+	}
+	return "oauth error " + e.Code
+}
+
+// OAuthClient performs OAuth 2.0 flows against an OIDC issuer
+type OAuthClient struct {
+	issuer     string
+	clientID   string
+	scopes     []string
+	httpClient *http.Client
+	metadata   *OIDCConfig
+}
+
+// NewOAuthClient creates a client for the given issuer and public client ID
+func NewOAuthClient(issuer, clientID string, scopes []string) *OAuthClient {
+	if len(scopes) == 0 {
+		scopes = DefaultOAuthScopes
+	}
+	return &OAuthClient{
+		issuer:   strings.TrimRight(issuer, "/"),
+		clientID: clientID,
+		scopes:   scopes,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// Discover fetches and caches the issuer's OpenID Provider metadata
+func (o *OAuthClient) Discover(ctx context.Context) (*OIDCConfig, error) {
+	if o.metadata != nil {
+		return o.metadata, nil
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, o.issuer+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("oidc discovery: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("oidc discovery: unexpected status %d", resp.StatusCode)
+	}
+	var meta OIDCConfig
+	if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery: decode: %w", err)
+	}
+	if meta.TokenEndpoint == "" {
+		return nil, fmt.Errorf("oidc discovery: issuer has no token_endpoint")
+	}
+	o.metadata = &meta
+	return &meta, nil
+}
+
+// postForm sends a form-encoded request to an OAuth endpoint and decodes the JSON response.
+// Non-2xx responses are returned as *OAuthError when the body is an OAuth error.
+func (o *OAuthClient) postForm(ctx context.Context, endpoint string, form url.Values, result any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := o.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("do request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var oerr OAuthError
+		if json.Unmarshal(body, &oerr) == nil && oerr.Code != "" {
+			return &oerr
+		}
+		return fmt.Errorf("oauth request failed (%d): %s", resp.StatusCode, string(body))
+	}
+
+	if result != nil && len(body) > 0 {
+		if err := json.Unmarshal(body, result); err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+	}
+	return nil
+}
+
+// StartDeviceAuthorization requests a device and user code (RFC 8628 §3.1)
+func (o *OAuthClient) StartDeviceAuthorization(ctx context.Context) (*DeviceAuthorization, error) {
+	meta, err := o.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if meta.DeviceAuthorizationEndpoint == "" {
+		return nil, fmt.Errorf("issuer does not support the device authorization flow; try --web")
+	}
+
+	form := url.Values{}
+	form.Set("client_id", o.clientID)
+	form.Set("scope", strings.Join(o.scopes, " "))
+
+	var da DeviceAuthorization
+	if err := o.postForm(ctx, meta.DeviceAuthorizationEndpoint, form, &da); err != nil {
+		return nil, fmt.Errorf("device authorization: %w", err)
+	}
+	return &da, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user approves or denies
+// the request, or the device code expires (RFC 8628 §3.4-3.5).
+func (o *OAuthClient) PollDeviceToken(ctx context.Context, da *DeviceAuthorization) (*TokenResponse, error) {
+	meta, err := o.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := da.Interval
+	if interval <= 0 {
+		interval = 5
+	}
+	if da.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(da.ExpiresIn)*devicePollUnit)
+		defer cancel()
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	form.Set("device_code", da.DeviceCode)
+	form.Set("client_id", o.clientID)
+
+	for {
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				return nil, fmt.Errorf("device code expired before login was approved")
+			}
+			return nil, ctx.Err()
+		case <-time.After(time.Duration(interval) * devicePollUnit):
+		}
+
+		var tok TokenResponse
+		err := o.postForm(ctx, meta.TokenEndpoint, form, &tok)
+		if err == nil {
+			return &tok, nil
+		}
+
+		var oerr *OAuthError
+		if !errors.As(err, &oerr) {
+			return nil, fmt.Errorf("poll token: %w", err)
+		}
+		switch oerr.Code {
+		case "authorization_pending":
+			continue
+		case "slow_down":
+			interval += 5
+			continue
+		case "access_denied":
+			return nil, fmt.Errorf("login was denied")
+		case "expired_token":
+			return nil, fmt.Errorf("device code expired before login was approved")
+		default:
+			return nil, fmt.Errorf("poll token: %w", err)
+		}
+	}
+}
+
+// PKCE holds a code verifier and its S256 challenge (RFC 7636)
+type PKCE struct {
+	Verifier  string
+	Challenge string
+}
+
+// NewPKCE generates a random code verifier and its S256 challenge
+func NewPKCE() (*PKCE, error) {
+	verifier, err := randomURLString(32)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	return &PKCE{
+		Verifier:  verifier,
+		Challenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+	}, nil
+}
+
+// AuthCodeURL builds the authorization URL for the PKCE authorization code flow
+func (o *OAuthClient) AuthCodeURL(ctx context.Context, redirectURI, state string, pkce *PKCE) (string, error) {
+	meta, err := o.Discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	if meta.AuthorizationEndpoint == "" {
+		return "", fmt.Errorf("issuer has no authorization_endpoint")
+	}
+	q := url.Values{}
+	q.Set("response_type", "code")
+	q.Set("client_id", o.clientID)
+	q.Set("redirect_uri", redirectURI)
+	q.Set("scope", strings.Join(o.scopes, " "))
+	q.Set("state", state)
+	q.Set("code_challenge", pkce.Challenge)
+	q.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return meta.AuthorizationEndpoint + sep + q.Encode(), nil
+}
+
+// ExchangeCode trades an authorization code for tokens
+func (o *OAuthClient) ExchangeCode(ctx context.Context, code, redirectURI, verifier string) (*TokenResponse, error) {
+	meta, err := o.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", redirectURI)
+	form.Set("client_id", o.clientID)
+	form.Set("code_verifier", verifier)
+
+	var tok TokenResponse
+	if err := o.postForm(ctx, meta.TokenEndpoint, form, &tok); err != nil {
+		return nil, fmt.Errorf("exchange code: %w", err)
+	}
+	return &tok, nil
+}
+
+// Refresh exchanges a refresh token for a new access token (RFC 6749 §6).
+// Providers that do not rotate refresh tokens return an empty RefreshToken.
+func (o *OAuthClient) Refresh(ctx context.Context, refreshToken string) (*TokenResponse, error) {
+	meta, err := o.Discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+	form := url.Values{}
+	form.Set("grant_type", "refresh_token")
+	form.Set("refresh_token", refreshToken)
+	form.Set("client_id", o.clientID)
+
+	var tok TokenResponse
+	if err := o.postForm(ctx, meta.TokenEndpoint, form, &tok); err != nil {
+		return nil, fmt.Errorf("refresh token: %w", err)
+	}
+	return &tok, nil
+}
+
+// Revoke invalidates a token at the issuer's revocation endpoint (RFC 7009).
+// tokenTypeHint is "refresh_token" or "access_token". Revoking a token that is
+// already invalid succeeds.
+func (o *OAuthClient) Revoke(ctx context.Context, token, tokenTypeHint string) error {
+	meta, err := o.Discover(ctx)
+	if err != nil {
+		return err
+	}
+	if meta.RevocationEndpoint == "" {
+		return fmt.Errorf("issuer does not support token revocation")
+	}
+	form := url.Values{}
+	form.Set("token", token)
+	form.Set("token_type_hint", tokenTypeHint)
+	form.Set("client_id", o.clientID)
+
+	if err := o.postForm(ctx, meta.RevocationEndpoint, form, nil); err != nil {
+		return fmt.Errorf("revoke token: %w", err)
+	}
+	return nil
+}
+
+// LoopbackLogin runs the authorization code + PKCE flow with a redirect to a
+// temporary listener on 127.0.0.1. open is called with the authorization URL
+// and should launch (or print) it for the user. It gives up if the browser
+// has not come back within five minutes.
+func (o *OAuthClient) LoopbackLogin(ctx context.Context, open func(authURL string) error) (*TokenResponse, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("start loopback listener: %w", err)
+	}
+	defer listener.Close()
+
+	redirectURI := fmt.Sprintf("http://%s/callback", listener.Addr().String())
+
+	pkce, err := NewPKCE()
+	if err != nil {
+		return nil, err
+	}
+	state, err := randomURLString(16)
+	if err != nil {
+		return nil, err
+	}
+	authURL, err := o.AuthCodeURL(ctx, redirectURI, state, pkce)
+	if err != nil {
+		return nil, err
+	}
+
+	type callbackResult struct {
+		code string
+		err  error
+	}
+	resultCh := make(chan callbackResult, 1)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var res callbackResult
+		switch {
+		case q.Get("state") != state:
+			res.err = fmt.Errorf("login callback state mismatch")
+		case q.Get("error") != "":
+			res.err = &OAuthError{Code: q.Get("error"), Description: q.Get("error_description")}
+		case q.Get("code") == "":
+			res.err = fmt.Errorf("login callback missing code")
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			http.Error(w, "Login failed. You can close this window and return to the terminal.", http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "Login complete. You can close this window and return to the terminal.")
+		}
+		select {
+		case resultCh <- res:
+		default:
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() { _ = srv.Serve(listener) }()
+	defer srv.Close()
+
+	if err := open(authURL); err != nil {
+		return nil, err
+	}
+
+	wait, cancel := context.WithTimeout(ctx, loopbackTimeout)
+	defer cancel()
+	select {
+	case <-wait.Done():
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("login was not completed in the browser within %s", loopbackTimeout)
+	case res := <-resultCh:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return o.ExchangeCode(ctx, res.code, redirectURI, pkce.Verifier)
+	}
+}
+
+// randomURLString returns n random bytes encoded as unpadded base64url
+func randomURLString(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate random string: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
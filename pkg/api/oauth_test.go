@@ -0,0 +1,258 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mockOIDC is a minimal OpenID provider supporting discovery, the device flow
+// and the authorization code flow with PKCE.
+type mockOIDC struct {
+	*httptest.Server
+	t *testing.T
+
+	mu            sync.Mutex
+	pendingPolls  int    // authorization_pending responses before success
+	denyDevice    bool   // respond access_denied to device polls
+	codeChallenge string // challenge received at /authorize
+	rejectRefresh bool   // respond invalid_grant to refresh requests
+	refreshes     int    // successful refresh_token grants
+	tokenRequests []url.Values
+	revoked       []url.Values
+}
+
+func newMockOIDC(t *testing.T) *mockOIDC {
+	t.Helper()
+	m := &mockOIDC{t: t}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OIDCConfig{
+			Issuer:                      m.URL,
+			AuthorizationEndpoint:       m.URL + "/authorize",
+			TokenEndpoint:               m.URL + "/token",
+			DeviceAuthorizationEndpoint: m.URL + "/device",
+			RevocationEndpoint:          m.URL + "/revoke",
+		})
+	})
+	mux.HandleFunc("/device", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "shoehorn-cli" {
+			t.Errorf("device: client_id = %q", r.Form.Get("client_id"))
+		}
+		json.NewEncoder(w).Encode(DeviceAuthorization{
+			DeviceCode:      "dev-123",
+			UserCode:        "ABCD-EFGH",
+			VerificationURI: m.URL + "/activate",
+			ExpiresIn:       600,
+			Interval:        1,
+		})
+	})
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("code_challenge_method") != "S256" {
+			t.Errorf("authorize: code_challenge_method = %q", q.Get("code_challenge_method"))
+		}
+		m.mu.Lock()
+		m.codeChallenge = q.Get("code_challenge")
+		m.mu.Unlock()
+		redirect := q.Get("redirect_uri") + "?code=auth-code&state=" + url.QueryEscape(q.Get("state"))
+		http.Redirect(w, r, redirect, http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.tokenRequests = append(m.tokenRequests, r.Form)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.Form.Get("grant_type") {
+		case "urn:ietf:params:oauth:grant-type:device_code":
+			if m.denyDevice {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(OAuthError{Code: "access_denied"})
+				return
+			}
+			if m.pendingPolls > 0 {
+				m.pendingPolls--
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(OAuthError{Code: "authorization_pending"})
+				return
+			}
+		case "refresh_token":
+			if m.rejectRefresh {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(OAuthError{Code: "invalid_grant"})
+				return
+			}
+			// Rotate both tokens on every refresh
+			m.refreshes++
+			json.NewEncoder(w).Encode(TokenResponse{
+				AccessToken:  fmt.Sprintf("access-refreshed-%d", m.refreshes),
+				RefreshToken: fmt.Sprintf("refresh-%d", m.refreshes+1),
+				TokenType:    "Bearer",
+				ExpiresIn:    3600,
+			})
+			return
+		case "authorization_code":
+			sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+			if base64.RawURLEncoding.EncodeToString(sum[:]) != m.codeChallenge {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(OAuthError{Code: "invalid_grant", Description: "PKCE verification failed"})
+				return
+			}
+		}
+		json.NewEncoder(w).Encode(TokenResponse{
+			AccessToken:  "access-" + r.Form.Get("grant_type"),
+			RefreshToken: "refresh-1",
+			TokenType:    "Bearer",
+			ExpiresIn:    3600,
+		})
+	})
+	mux.HandleFunc("/revoke", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.revoked = append(m.revoked, r.Form)
+	})
+	m.Server = httptest.NewServer(mux)
+	t.Cleanup(m.Close)
+	return m
+}
+
+func fastDevicePolling(t *testing.T) {
+	t.Helper()
+	prev := devicePollUnit
+	devicePollUnit = time.Millisecond
+	t.Cleanup(func() { devicePollUnit = prev })
+}
+
+func TestDeviceFlow_PendingThenSuccess(t *testing.T) {
+	fastDevicePolling(t)
+	m := newMockOIDC(t)
+	m.pendingPolls = 2
+
+	oc := NewOAuthClient(m.URL, "shoehorn-cli", nil)
+	da, err := oc.StartDeviceAuthorization(context.Background())
+	if err != nil {
+		t.Fatalf("StartDeviceAuthorization() = %v", err)
+	}
+	if da.UserCode != "ABCD-EFGH" {
+		t.Errorf("UserCode = %q", da.UserCode)
+	}
+
+	tok, err := oc.PollDeviceToken(context.Background(), da)
+	if err != nil {
+		t.Fatalf("PollDeviceToken() = %v", err)
+	}
+	if !strings.HasPrefix(tok.AccessToken, "access-") || tok.RefreshToken != "refresh-1" {
+		t.Errorf("unexpected token: %+v", tok)
+	}
+	if len(m.tokenRequests) != 3 {
+		t.Errorf("token requests = %d, want 3 (2 pending + 1 success)", len(m.tokenRequests))
+	}
+	if tok.ExpiresAt().Before(time.Now().Add(59 * time.Minute)) {
+		t.Errorf("ExpiresAt() = %v, want ~1h from now", tok.ExpiresAt())
+	}
+}
+
+func TestDeviceFlow_Denied(t *testing.T) {
+	fastDevicePolling(t)
+	m := newMockOIDC(t)
+	m.denyDevice = true
+
+	oc := NewOAuthClient(m.URL, "shoehorn-cli", nil)
+	da, err := oc.StartDeviceAuthorization(context.Background())
+	if err != nil {
+		t.Fatalf("StartDeviceAuthorization() = %v", err)
+	}
+	if _, err := oc.PollDeviceToken(context.Background(), da); err == nil || !strings.Contains(err.Error(), "denied") {
+		t.Errorf("PollDeviceToken() = %v, want denied error", err)
+	}
+}
+
+func TestDeviceFlow_UnsupportedIssuer(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OIDCConfig{TokenEndpoint: "http://example/token"})
+	}))
+	defer server.Close()
+
+	oc := NewOAuthClient(server.URL, "shoehorn-cli", nil)
+	if _, err := oc.StartDeviceAuthorization(context.Background()); err == nil {
+		t.Error("expected error when issuer has no device endpoint")
+	}
+}
+
+func TestLoopbackLogin_PKCE(t *testing.T) {
+	m := newMockOIDC(t)
+	oc := NewOAuthClient(m.URL, "shoehorn-cli", nil)
+
+	// Simulate the browser: follow the authorize redirect to the loopback listener
+	browser := func(authURL string) error {
+		go func() {
+			resp, err := http.Get(authURL)
+			if err != nil {
+				t.Errorf("browser GET: %v", err)
+				return
+			}
+			resp.Body.Close()
+		}()
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	tok, err := oc.LoopbackLogin(ctx, browser)
+	if err != nil {
+		t.Fatalf("LoopbackLogin() = %v", err)
+	}
+	if tok.AccessToken != "access-authorization_code" {
+		t.Errorf("AccessToken = %q", tok.AccessToken)
+	}
+
+	last := m.tokenRequests[len(m.tokenRequests)-1]
+	if !strings.HasPrefix(last.Get("redirect_uri"), "http://127.0.0.1:") {
+		t.Errorf("redirect_uri = %q, want loopback", last.Get("redirect_uri"))
+	}
+}
+
+func TestLoopbackLogin_Timeout(t *testing.T) {
+	defer func(d time.Duration) { loopbackTimeout = d }(loopbackTimeout)
+	loopbackTimeout = 50 * time.Millisecond
+
+	m := newMockOIDC(t)
+	oc := NewOAuthClient(m.URL, "shoehorn-cli", nil)
+
+	// The browser never comes back
+	_, err := oc.LoopbackLogin(context.Background(), func(string) error { return nil })
+	if err == nil || !strings.Contains(err.Error(), "not completed in the browser within 50ms") {
+		t.Fatalf("LoopbackLogin() = %v, want timeout error", err)
+	}
+}
+
+func TestGetCLIAuthConfig(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/auth/cli/config" {
+			t.Errorf("unexpected path: %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(CLIAuthConfig{Issuer: "https://idp.example.com", ClientID: "shoehorn-cli"})
+	}))
+	defer server.Close()
+
+	cfg, err := NewClient(server.URL).GetCLIAuthConfig(context.Background())
+	if err != nil {
+		t.Fatalf("GetCLIAuthConfig() = %v", err)
+	}
+	if cfg.Issuer != "https://idp.example.com" || cfg.ClientID != "shoehorn-cli" {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+}
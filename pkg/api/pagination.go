@@ -0,0 +1,104 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// DefaultPageSize is the number of items requested per page unless
+// PageOptions.PageSize says otherwise.
+const DefaultPageSize = 100
+
+// PageOptions controls how list calls page through results
+type PageOptions struct {
+	Limit    int // stop after this many items; 0 fetches every page
+	PageSize int // items per request; 0 uses DefaultPageSize
+}
+
+// fetchPage requests up to size items starting at cursor ("" for the first
+// page). It returns the items and the cursor of the following page, or ""
+// when this was the last one.
+type fetchPage[T any] func(ctx context.Context, size int, cursor string) ([]T, string, error)
+
+// paginate calls fetch until the results run out or opts.Limit is reached.
+// more reports whether the server had results beyond the limit.
+func paginate[T any](ctx context.Context, opts PageOptions, fetch fetchPage[T]) (items []T, more bool, err error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	// Pages are always requested at full size, even when the limit needs
+	// fewer items, so the request URLs — and response cache keys — do not
+	// depend on the limit.
+	seen := map[string]bool{}
+	cursor := ""
+	for {
+		page, next, err := fetch(ctx, pageSize, cursor)
+		if err != nil {
+			return nil, false, err
+		}
+		items = append(items, page...)
+
+		if opts.Limit > 0 && len(items) >= opts.Limit {
+			return items[:opts.Limit], next != "" || len(items) > opts.Limit, nil
+		}
+		if next == "" {
+			return items, false, nil
+		}
+		// A server that hands back the same cursor would loop forever
+		if seen[next] {
+			return nil, false, fmt.Errorf("pagination: server repeated cursor %q", next)
+		}
+		seen[next] = true
+		cursor = next
+	}
+}
+
+// offsetPages adapts an endpoint paged with limit/offset query parameters to
+// fetchPage, using the offset as the cursor. fetch returns one page and the
+// server's total item count (0 if it did not send one); key identifies an item.
+//
+// Iteration ends on a short page, once total is reached, or when a page starts
+// with the same item as the previous one — the server ignored the offset.
+func offsetPages[T any](key func(T) string, fetch func(ctx context.Context, size, offset int) ([]T, int, error)) fetchPage[T] {
+	var firstKey string
+	return func(ctx context.Context, size int, cursor string) ([]T, string, error) {
+		offset := 0
+		if cursor != "" {
+			n, err := strconv.Atoi(cursor)
+			if err != nil {
+				return nil, "", fmt.Errorf("pagination: invalid offset %q", cursor)
+			}
+			offset = n
+		}
+
+		items, total, err := fetch(ctx, size, offset)
+		if err != nil {
+			return nil, "", err
+		}
+		if len(items) == 0 {
+			return items, "", nil
+		}
+		if offset > 0 && key(items[0]) == firstKey {
+			return nil, "", nil
+		}
+		firstKey = key(items[0])
+
+		next := offset + len(items)
+		if len(items) < size || (total > 0 && next >= total) {
+			return items, "", nil
+		}
+		return items, strconv.Itoa(next), nil
+	}
+}
+
+// offsetQuery encodes limit/offset paging parameters
+func offsetQuery(limit, offset int) string {
+	q := url.Values{}
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("offset", strconv.Itoa(offset))
+	return q.Encode()
+}
@@ -0,0 +1,281 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// newEntitiesServer serves total entities from /api/v1/entities using
+// numeric cursors, counting requests.
+func newEntitiesServer(t *testing.T, total int, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		end := min(start+limit, total)
+
+		var resp entitiesAPIResponse
+		for i := start; i < end; i++ {
+			resp.Entities = append(resp.Entities, entityAPIItem{
+				Service: entityServiceInfo{ID: fmt.Sprintf("svc-%d", i)},
+			})
+		}
+		resp.Page.Total = total
+		if end < total {
+			resp.Page.NextCursor = strconv.Itoa(end)
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListEntities_FollowsCursor(t *testing.T) {
+	var requests atomic.Int32
+	server := newEntitiesServer(t, 2400, &requests)
+
+	c := NewClient(server.URL)
+	entities, more, err := c.ListEntities(context.Background(), ListEntitiesOpts{})
+	if err != nil {
+		t.Fatalf("ListEntities() = %v", err)
+	}
+	if len(entities) != 2400 || more {
+		t.Errorf("got %d entities (more=%v), want 2400 (more=false)", len(entities), more)
+	}
+	if entities[2399].ID != "svc-2399" {
+		t.Errorf("last entity = %q, want svc-2399", entities[2399].ID)
+	}
+	if requests.Load() != 24 {
+		t.Errorf("requests = %d, want 24 pages of %d", requests.Load(), DefaultPageSize)
+	}
+}
+
+func TestListEntities_Limit(t *testing.T) {
+	tests := []struct {
+		name         string
+		page         PageOptions
+		total        int
+		wantCount    int
+		wantMore     bool
+		wantRequests int32
+	}{
+		{"stops at limit", PageOptions{Limit: 250, PageSize: 100}, 2400, 250, true, 3},
+		{"limit smaller than page", PageOptions{Limit: 10, PageSize: 100}, 2400, 10, true, 1},
+		{"limit equals total", PageOptions{Limit: 150, PageSize: 100}, 150, 150, false, 2},
+		{"limit above total", PageOptions{Limit: 500, PageSize: 100}, 120, 120, false, 2},
+		{"custom page size", PageOptions{PageSize: 500}, 1200, 1200, false, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := newEntitiesServer(t, tt.total, &requests)
+
+			c := NewClient(server.URL)
+			entities, more, err := c.ListEntities(context.Background(), ListEntitiesOpts{PageOptions: tt.page})
+			if err != nil {
+				t.Fatalf("ListEntities() = %v", err)
+			}
+			if len(entities) != tt.wantCount || more != tt.wantMore {
+				t.Errorf("got %d entities (more=%v), want %d (more=%v)", len(entities), more, tt.wantCount, tt.wantMore)
+			}
+			if requests.Load() != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests.Load(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestPaginate_RepeatedCursor(t *testing.T) {
+	fetch := func(ctx context.Context, size int, cursor string) ([]int, string, error) {
+		return []int{1}, "same", nil
+	}
+	_, _, err := paginate(context.Background(), PageOptions{}, fetch)
+	if err == nil || !strings.Contains(err.Error(), "repeated cursor") {
+		t.Fatalf("paginate() = %v, want repeated cursor error", err)
+	}
+}
+
+// newTeamsServer serves total teams with limit/offset paging. If ignoreParams
+// is set it returns every team on each request, as a server without paging would.
+func newTeamsServer(t *testing.T, total int, ignoreParams bool, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		start, end := 0, total
+		if !ignoreParams {
+			limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+			start, _ = strconv.Atoi(r.URL.Query().Get("offset"))
+			start = min(start, total)
+			end = min(start+limit, total)
+		}
+
+		var resp TeamsResponse
+		for i := start; i < end; i++ {
+			resp.Teams = append(resp.Teams, Team{ID: fmt.Sprintf("team-%d", i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestListTeams_OffsetPaging(t *testing.T) {
+	tests := []struct {
+		name         string
+		total        int
+		ignoreParams bool
+		page         PageOptions
+		wantCount    int
+		wantMore     bool
+		wantRequests int32
+	}{
+		{"short last page", 250, false, PageOptions{}, 250, false, 3},
+		{"exact multiple of page size", 200, false, PageOptions{}, 200, false, 3},
+		{"limit", 250, false, PageOptions{Limit: 120}, 120, true, 2},
+		{"small page size", 40, false, PageOptions{PageSize: 10}, 40, false, 5},
+		{"server returns everything", 40, true, PageOptions{PageSize: 10}, 40, false, 2},
+		{"server returns exactly one page", 10, true, PageOptions{PageSize: 10}, 10, false, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := newTeamsServer(t, tt.total, tt.ignoreParams, &requests)
+
+			c := NewClient(server.URL)
+			teams, more, err := c.ListTeams(context.Background(), tt.page)
+			if err != nil {
+				t.Fatalf("ListTeams() = %v", err)
+			}
+			if len(teams) != tt.wantCount || more != tt.wantMore {
+				t.Errorf("got %d teams (more=%v), want %d (more=%v)", len(teams), more, tt.wantCount, tt.wantMore)
+			}
+			seen := map[string]bool{}
+			for _, team := range teams {
+				if seen[team.ID] {
+					t.Fatalf("duplicate team %q", team.ID)
+				}
+				seen[team.ID] = true
+			}
+			if requests.Load() != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests.Load(), tt.wantRequests)
+			}
+		})
+	}
+}
+
+func TestListUsers_StopsAtReportedTotal(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		resp := usersAPIResponse{Total: 4}
+		for i := offset; i < min(offset+2, 4); i++ {
+			resp.Items = append(resp.Items, userAPIItem{ID: strconv.Itoa(i), Username: "u" + strconv.Itoa(i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	users, more, err := c.ListUsers(context.Background(), PageOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListUsers() = %v", err)
+	}
+	if len(users) != 4 || more {
+		t.Errorf("got %d users (more=%v), want 4 (more=false)", len(users), more)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("requests = %d, want 2 (no request past the total)", requests.Load())
+	}
+}
+
+func TestListGroups_OffsetPaging(t *testing.T) {
+	var offsets []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offsets = append(offsets, r.URL.Query().Get("offset"))
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		var resp groupsAPIResponse
+		for i := offset; i < min(offset+limit, 5); i++ {
+			resp.Items = append(resp.Items, groupAPIItem{Name: fmt.Sprintf("group-%d", i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	groups, more, err := NewClient(server.URL).ListGroups(context.Background(), PageOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListGroups() = %v", err)
+	}
+	if len(groups) != 5 || more || groups[4].Name != "group-4" {
+		t.Errorf("got %d groups (more=%v), want 5 (more=false)", len(groups), more)
+	}
+	if got := strings.Join(offsets, ","); got != "0,2,4" {
+		t.Errorf("offsets = %s, want 0,2,4", got)
+	}
+}
+
+func TestListK8sAgents_Limit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		resp := k8sAgentsAPIResponse{Total: 30}
+		for i := offset; i < min(offset+limit, 30); i++ {
+			resp.Agents = append(resp.Agents, k8sAgentAPIItem{ID: i, ClusterID: fmt.Sprintf("cluster-%d", i)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	agents, more, err := NewClient(server.URL).ListK8sAgents(context.Background(), PageOptions{Limit: 15, PageSize: 10})
+	if err != nil {
+		t.Fatalf("ListK8sAgents() = %v", err)
+	}
+	if len(agents) != 15 || !more || agents[14].ClusterName != "cluster-14" {
+		t.Errorf("got %d agents (more=%v), want 15 (more=true)", len(agents), more)
+	}
+}
+
+func TestListRuns_Paging(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var resp ForgeRunsResponse
+		resp.Pagination.TotalCount = 3
+		switch r.URL.Query().Get("cursor") {
+		case "":
+			resp.Runs = []ForgeRun{{ID: "run-1"}, {ID: "run-2"}}
+			resp.Pagination.NextCursor = "c2"
+			resp.Pagination.HasMore = true
+		case "c2":
+			resp.Runs = []ForgeRun{{ID: "run-3"}}
+		default:
+			t.Errorf("unexpected cursor %q", r.URL.Query().Get("cursor"))
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	resp, err := c.ListRuns(context.Background(), PageOptions{PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListRuns() = %v", err)
+	}
+	if len(resp.Runs) != 3 || resp.Pagination.HasMore || resp.Pagination.TotalCount != 3 {
+		t.Errorf("got %d runs, pagination %+v; want 3 runs, no more", len(resp.Runs), resp.Pagination)
+	}
+
+	resp, err = c.ListRuns(context.Background(), PageOptions{Limit: 1, PageSize: 2})
+	if err != nil {
+		t.Fatalf("ListRuns() = %v", err)
+	}
+	if len(resp.Runs) != 1 || !resp.Pagination.HasMore {
+		t.Errorf("got %d runs, pagination %+v; want 1 run with more", len(resp.Runs), resp.Pagination)
+	}
+}
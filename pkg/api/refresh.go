@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
+)
+
+// refreshSkew renews tokens slightly before they expire so a request does not
+// race the expiry on the wire.
+const refreshSkew = 30 * time.Second
+
+// warnings is where problems that do not fail the request are reported
+var warnings io.Writer = os.Stderr
+
+// tokenRefresher renews an expired OIDC access token with the profile's
+// refresh token. mu serializes refreshes across concurrent requests.
+type tokenRefresher struct {
+	mu           sync.Mutex
+	oauth        *OAuthClient
+	refreshToken string
+	expiresAt    time.Time
+
+	// persist saves rotated tokens; refreshToken is the one to store, which
+	// is the previous token when the provider does not rotate it.
+	persist func(tok *TokenResponse, refreshToken string) error
+}
+
+// newProfileRefresher returns a refresher for an OIDC profile, or nil when the
+// profile cannot be refreshed (PAT, or no refresh token was issued).
+func newProfileRefresher(profileName string, auth *config.Auth) *tokenRefresher {
+	if auth == nil || auth.ProviderType != "oidc" || auth.RefreshToken == "" || auth.Issuer == "" || auth.ClientID == "" {
+		return nil
+	}
+	return &tokenRefresher{
+		oauth:        NewOAuthClient(auth.Issuer, auth.ClientID, nil),
+		refreshToken: auth.RefreshToken,
+		expiresAt:    auth.ExpiresAt,
+		persist: func(tok *TokenResponse, refreshToken string) error {
+			return saveRefreshedToken(profileName, tok, refreshToken)
+		},
+	}
+}
+
+// expired reports whether the access token is at or near its expiry.
+// A zero expiry is never considered expired; a 401 triggers the refresh instead.
+func (r *tokenRefresher) expired() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return !r.expiresAt.IsZero() && time.Now().Add(refreshSkew).After(r.expiresAt)
+}
+
+// refreshIfExpired renews the token before a request if it has expired
+func (c *Client) refreshIfExpired(ctx context.Context) error {
+	if c.refresher == nil {
+		return nil
+	}
+	stale := c.GetToken()
+	if !c.refresher.expired() {
+		return nil
+	}
+	return c.refreshToken(ctx, stale)
+}
+
+// refreshToken replaces stale with a new access token. If another request has
+// already refreshed it while this one waited for the lock, it returns at once.
+func (c *Client) refreshToken(ctx context.Context, stale string) error {
+	r := c.refresher
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if c.GetToken() != stale {
+		return nil
+	}
+
+	tok, err := r.oauth.Refresh(ctx, r.refreshToken)
+	if err != nil {
+		return fmt.Errorf("%w (session expired: %w)", ErrNotAuthenticated, err)
+	}
+	if tok.RefreshToken != "" {
+		r.refreshToken = tok.RefreshToken
+	}
+	r.expiresAt = tok.ExpiresAt()
+	c.SetToken(tok.AccessToken)
+
+	// The new token is good for this run whether or not it can be saved;
+	// failing to save only means the next run refreshes again
+	if r.persist != nil {
+		if err := r.persist(tok, r.refreshToken); err != nil {
+			fmt.Fprintf(warnings, "Warning: could not save refreshed token: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// saveRefreshedToken writes renewed tokens back to the named profile. The
+// config is reloaded so changes made since this process started are kept.
+func saveRefreshedToken(profileName string, tok *TokenResponse, refreshToken string) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	profile := cfg.Profiles[profileName]
+	if profile == nil || profile.Auth == nil {
+		// Logged out in the meantime; don't resurrect the session
+		return nil
+	}
+	if err := cfg.LoadCredentials(profileName); err != nil {
+		return err
+	}
+	profile.Auth.AccessToken = tok.AccessToken
+	profile.Auth.RefreshToken = refreshToken
+	profile.Auth.ExpiresAt = tok.ExpiresAt()
+	if tok.TokenType != "" {
+		profile.Auth.TokenType = tok.TokenType
+	}
+	return cfg.Save()
+}
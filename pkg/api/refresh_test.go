@@ -0,0 +1,238 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
+)
+
+// withTestConfig points the config package at a temporary file with the given contents
+func withTestConfig(t *testing.T, contents string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	config.SetPathOverride(path)
+	t.Cleanup(func() { config.SetPathOverride("") })
+	t.Setenv(config.EnvProfile, "")
+}
+
+// newRefreshAPI returns an API server that only accepts refreshed access tokens
+func newRefreshAPI(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "Bearer access-refreshed-") {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"error":{"message":"token expired"}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newRefreshingClient returns a client holding a stale token that refreshes
+// against m, recording persisted refresh tokens.
+func newRefreshingClient(baseURL string, m *mockOIDC, expiresAt time.Time, persisted *[]string) *Client {
+	c := NewClient(baseURL)
+	c.SetToken("stale-access")
+	var mu sync.Mutex
+	c.refresher = &tokenRefresher{
+		oauth:        NewOAuthClient(m.URL, "shoehorn-cli", nil),
+		refreshToken: "refresh-1",
+		expiresAt:    expiresAt,
+		persist: func(_ *TokenResponse, refreshToken string) error {
+			mu.Lock()
+			defer mu.Unlock()
+			*persisted = append(*persisted, refreshToken)
+			return nil
+		},
+	}
+	return c
+}
+
+func TestClient_RefreshesExpiredTokenBeforeRequest(t *testing.T) {
+	m := newMockOIDC(t)
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	var persisted []string
+	c := newRefreshingClient(server.URL, m, time.Now().Add(-time.Minute), &persisted)
+
+	if err := c.Get(context.Background(), "/api/v1/me", nil); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("API requests = %d, want 1 (no request with the expired token)", requests.Load())
+	}
+	if c.GetToken() != "access-refreshed-1" {
+		t.Errorf("token = %q, want access-refreshed-1", c.GetToken())
+	}
+	if len(persisted) != 1 || persisted[0] != "refresh-2" {
+		t.Errorf("persisted refresh tokens = %v, want [refresh-2]", persisted)
+	}
+}
+
+func TestClient_RefreshesOn401AndRetries(t *testing.T) {
+	m := newMockOIDC(t)
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	var persisted []string
+	c := newRefreshingClient(server.URL, m, time.Now().Add(time.Hour), &persisted)
+
+	if err := c.Get(context.Background(), "/api/v1/me", nil); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if requests.Load() != 2 {
+		t.Errorf("API requests = %d, want 2 (rejected + retry)", requests.Load())
+	}
+	if m.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", m.refreshes)
+	}
+}
+
+func TestClient_RefreshSurvivesPersistFailure(t *testing.T) {
+	var buf strings.Builder
+	defer func(w io.Writer) { warnings = w }(warnings)
+	warnings = &buf
+
+	m := newMockOIDC(t)
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	var persisted []string
+	c := newRefreshingClient(server.URL, m, time.Now().Add(-time.Minute), &persisted)
+	c.refresher.persist = func(*TokenResponse, string) error {
+		return errors.New("credentials file is encrypted")
+	}
+
+	if err := c.Get(context.Background(), "/api/v1/me", nil); err != nil {
+		t.Fatalf("Get() = %v, want success with a warning", err)
+	}
+	if !strings.Contains(buf.String(), "could not save refreshed token: credentials file is encrypted") {
+		t.Errorf("warning = %q", buf.String())
+	}
+}
+
+func TestClient_ConcurrentRequestsRefreshOnce(t *testing.T) {
+	m := newMockOIDC(t)
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	var persisted []string
+	c := newRefreshingClient(server.URL, m, time.Now().Add(-time.Minute), &persisted)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- c.Get(context.Background(), "/api/v1/me", nil)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("Get() = %v", err)
+		}
+	}
+	if m.refreshes != 1 {
+		t.Errorf("refreshes = %d, want 1", m.refreshes)
+	}
+	if len(persisted) != 1 {
+		t.Errorf("persist calls = %d, want 1", len(persisted))
+	}
+}
+
+func TestClient_RefreshRejected(t *testing.T) {
+	m := newMockOIDC(t)
+	m.rejectRefresh = true
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	var persisted []string
+	c := newRefreshingClient(server.URL, m, time.Now().Add(-time.Minute), &persisted)
+
+	err := c.Get(context.Background(), "/api/v1/me", nil)
+	if err == nil || !strings.Contains(err.Error(), "shoehorn auth login") {
+		t.Fatalf("Get() = %v, want error suggesting login", err)
+	}
+	if len(persisted) != 0 {
+		t.Errorf("persisted = %v, want none", persisted)
+	}
+}
+
+func TestClient_NoRefresherPassesThrough401(t *testing.T) {
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	c := NewClient(server.URL)
+	c.SetToken("shp_pat")
+	err := c.Get(context.Background(), "/api/v1/me", nil)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatalf("Get() = %v, want 401 error", err)
+	}
+	if requests.Load() != 1 {
+		t.Errorf("API requests = %d, want 1", requests.Load())
+	}
+}
+
+func TestNewClientFromConfig_PersistsRefreshedToken(t *testing.T) {
+	m := newMockOIDC(t)
+	var requests atomic.Int32
+	server := newRefreshAPI(t, &requests)
+
+	withTestConfig(t, `version: "1.0"
+current_profile: dev
+credential_store: plaintext
+profiles:
+  dev:
+    name: dev
+    server: `+server.URL+`
+    auth:
+      provider_type: oidc
+      issuer: `+m.URL+`
+      client_id: shoehorn-cli
+      access_token: stale-access
+      refresh_token: refresh-1
+      expires_at: 2020-01-01T00:00:00Z
+`)
+
+	c, err := NewClientFromConfig()
+	if err != nil {
+		t.Fatalf("NewClientFromConfig() = %v", err)
+	}
+	if err := c.Get(context.Background(), "/api/v1/me", nil); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	auth := cfg.Profiles["dev"].Auth
+	if auth.AccessToken != "access-refreshed-1" || auth.RefreshToken != "refresh-2" {
+		t.Errorf("saved tokens = %q / %q, want rotated tokens", auth.AccessToken, auth.RefreshToken)
+	}
+	if !auth.ExpiresAt.After(time.Now()) {
+		t.Errorf("saved ExpiresAt = %v, want in the future", auth.ExpiresAt)
+	}
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
+)
+
+// RetryPolicy controls how idempotent requests (GET, PUT, DELETE) are retried
+// on connection errors, 429 and 5xx responses.
+type RetryPolicy struct {
+	MaxRetries int           // retries after the first attempt; 0 disables retries
+	BaseDelay  time.Duration // backoff before the first retry, doubled each time
+	MaxDelay   time.Duration // cap on a single wait, including Retry-After
+}
+
+// DefaultRetryPolicy is used unless the profile or flags override it
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// retryOverride holds the global --retries and --retry-max-delay flags.
+// A negative maxRetries or zero maxDelay means the flag was not set.
+var retryOverride = struct {
+	maxRetries int
+	maxDelay   time.Duration
+}{maxRetries: -1}
+
+// SetRetryOverride applies the global retry flags to clients created by
+// NewClientFromConfig. Pass a negative maxRetries or zero maxDelay to leave
+// that setting to the profile.
+func SetRetryOverride(maxRetries int, maxDelay time.Duration) {
+	retryOverride.maxRetries = maxRetries
+	retryOverride.maxDelay = maxDelay
+}
+
+// resolveRetryPolicy applies profile settings, then flag overrides, to the defaults
+func resolveRetryPolicy(settings *config.RetrySettings) RetryPolicy {
+	policy := DefaultRetryPolicy
+	if settings != nil {
+		if settings.MaxRetries != nil {
+			policy.MaxRetries = *settings.MaxRetries
+		}
+		if settings.MaxDelay > 0 {
+			policy.MaxDelay = settings.MaxDelay
+		}
+	}
+	if retryOverride.maxRetries >= 0 {
+		policy.MaxRetries = retryOverride.maxRetries
+	}
+	if retryOverride.maxDelay > 0 {
+		policy.MaxDelay = retryOverride.maxDelay
+	}
+	return policy
+}
+
+// SetRetryPolicy replaces the client's retry policy
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retry = policy
+}
+
+// idempotent reports whether a request with method may be safely repeated
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+	return false
+}
+
+// retryableStatus reports whether a response status indicates a transient failure
+func retryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// transientError reports whether a transport error is worth retrying: refused or
+// reset connections, timeouts, and connections closed before the response ended.
+// Errors such as an invalid server URL fail the same way every time.
+func transientError(err error) bool {
+	var opErr *net.OpError
+	var netErr net.Error
+	return errors.As(err, &opErr) ||
+		(errors.As(err, &netErr) && netErr.Timeout()) ||
+		errors.Is(err, io.EOF) ||
+		errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// backoff returns the wait before retry number n (0-based). A Retry-After
+// header takes precedence; otherwise the delay doubles from BaseDelay with
+// jitter so that concurrent clients do not retry in lockstep.
+func (p RetryPolicy) backoff(n int, header http.Header) time.Duration {
+	if d, ok := parseRetryAfter(header); ok {
+		return min(d, p.MaxDelay)
+	}
+	d := p.BaseDelay << n
+	if d <= 0 || d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	// Equal jitter: at least half the computed delay
+	half := d / 2
+	return half + rand.N(half+1)
+}
+
+// parseRetryAfter reads a Retry-After header in seconds or HTTP-date form
+func parseRetryAfter(header http.Header) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs >= 0 {
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// attempt performs a request, retrying idempotent methods on transient failures
+func (c *Client) attempt(ctx context.Context, method, path string, payload []byte, reqHeader http.Header, token string) (int, http.Header, []byte, error) {
+	for n := 0; ; n++ {
+		status, header, respBody, err := c.roundTrip(ctx, method, path, payload, reqHeader, token)
+
+		retry := n < c.retry.MaxRetries && idempotent(method) && ctx.Err() == nil
+		if err != nil {
+			retry = retry && transientError(err)
+		} else {
+			retry = retry && retryableStatus(status)
+		}
+		if !retry {
+			return status, header, respBody, err
+		}
+
+		timer := time.NewTimer(c.retry.backoff(n, header))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return status, header, respBody, err
+		case <-timer.C:
+		}
+	}
+}
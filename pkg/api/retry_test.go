@@ -0,0 +1,197 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/config"
+)
+
+// fastRetries is a retry policy with millisecond waits for tests
+var fastRetries = RetryPolicy{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Millisecond}
+
+// newFlakyServer fails the first failures requests with status, then succeeds
+func newFlakyServer(t *testing.T, failures int32, status int, attempts *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) <= failures {
+			w.WriteHeader(status)
+			w.Write([]byte(`{"error":{"message":"bad gateway"}}`))
+			return
+		}
+		w.Write([]byte(`{"ok":true}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestClient_RetriesGetOn502(t *testing.T) {
+	var attempts atomic.Int32
+	server := newFlakyServer(t, 2, http.StatusBadGateway, &attempts)
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(fastRetries)
+	if err := c.Get(context.Background(), "/api/v1/entities", nil); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if attempts.Load() != 3 {
+		t.Errorf("attempts = %d, want 3", attempts.Load())
+	}
+}
+
+func TestClient_GivesUpAfterMaxRetries(t *testing.T) {
+	var attempts atomic.Int32
+	server := newFlakyServer(t, 100, http.StatusServiceUnavailable, &attempts)
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(fastRetries)
+	if err := c.Get(context.Background(), "/api/v1/entities", nil); err == nil {
+		t.Fatal("expected error after exhausting retries")
+	}
+	if attempts.Load() != 4 {
+		t.Errorf("attempts = %d, want 4 (1 + 3 retries)", attempts.Load())
+	}
+}
+
+func TestClient_DoesNotRetryPost(t *testing.T) {
+	var attempts atomic.Int32
+	server := newFlakyServer(t, 1, http.StatusServiceUnavailable, &attempts)
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(fastRetries)
+	if err := c.Post(context.Background(), "/api/v1/forge/runs", map[string]string{}, nil); err == nil {
+		t.Fatal("expected error from POST")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1", attempts.Load())
+	}
+}
+
+func TestClient_DoesNotRetryClientErrors(t *testing.T) {
+	var attempts atomic.Int32
+	server := newFlakyServer(t, 1, http.StatusNotFound, &attempts)
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(fastRetries)
+	if err := c.Get(context.Background(), "/api/v1/entities/x", nil); err == nil {
+		t.Fatal("expected 404 error")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1", attempts.Load())
+	}
+}
+
+func TestClient_RetriesDroppedConnection(t *testing.T) {
+	var attempts atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			// Close the connection without a response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(fastRetries)
+	if err := c.Get(context.Background(), "/api/v1/entities", nil); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if attempts.Load() != 2 {
+		t.Errorf("attempts = %d, want 2", attempts.Load())
+	}
+}
+
+func TestClient_RetryDisabled(t *testing.T) {
+	var attempts atomic.Int32
+	server := newFlakyServer(t, 1, http.StatusBadGateway, &attempts)
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(RetryPolicy{})
+	if err := c.Get(context.Background(), "/api/v1/entities", nil); err == nil {
+		t.Fatal("expected error with retries disabled")
+	}
+	if attempts.Load() != 1 {
+		t.Errorf("attempts = %d, want 1", attempts.Load())
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := RetryPolicy{MaxRetries: 5, BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for n := range 6 {
+		want := min(p.BaseDelay<<n, p.MaxDelay)
+		got := p.backoff(n, nil)
+		if got < want/2 || got > want {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", n, got, want/2, want)
+		}
+	}
+
+	h := http.Header{}
+	h.Set("Retry-After", "0")
+	if got := p.backoff(0, h); got != 0 {
+		t.Errorf("backoff with Retry-After: 0 = %v, want 0", got)
+	}
+	h.Set("Retry-After", "120")
+	if got := p.backoff(0, h); got != p.MaxDelay {
+		t.Errorf("backoff with Retry-After: 120 = %v, want capped at %v", got, p.MaxDelay)
+	}
+	h.Set("Retry-After", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	if got := p.backoff(0, h); got != p.MaxDelay {
+		t.Errorf("backoff with Retry-After date = %v, want capped at %v", got, p.MaxDelay)
+	}
+}
+
+func TestClient_HonorsRetryAfter(t *testing.T) {
+	var attempts atomic.Int32
+	var first atomic.Int64 // unix nanos of the rate-limited attempt
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) == 1 {
+			first.Store(time.Now().UnixNano())
+			w.Header().Set("Retry-After", "1")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		if elapsed := time.Since(time.Unix(0, first.Load())); elapsed < 900*time.Millisecond {
+			t.Errorf("retried after %v, want >= 1s per Retry-After", elapsed)
+		}
+		w.Write([]byte(`{}`))
+	}))
+	defer server.Close()
+
+	c := NewClient(server.URL)
+	c.SetRetryPolicy(RetryPolicy{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: 5 * time.Second})
+	if err := c.Get(context.Background(), "/api/v1/entities", nil); err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+}
+
+func TestResolveRetryPolicy(t *testing.T) {
+	t.Cleanup(func() { SetRetryOverride(-1, 0) })
+	five := 5
+
+	SetRetryOverride(-1, 0)
+	if got := resolveRetryPolicy(nil); got != DefaultRetryPolicy {
+		t.Errorf("no settings = %+v, want defaults", got)
+	}
+
+	profile := &config.RetrySettings{MaxRetries: &five, MaxDelay: time.Minute}
+	got := resolveRetryPolicy(profile)
+	if got.MaxRetries != 5 || got.MaxDelay != time.Minute || got.BaseDelay != DefaultRetryPolicy.BaseDelay {
+		t.Errorf("profile settings = %+v", got)
+	}
+
+	SetRetryOverride(0, 0)
+	got = resolveRetryPolicy(profile)
+	if got.MaxRetries != 0 || got.MaxDelay != time.Minute {
+		t.Errorf("flag override = %+v, want retries 0 and the profile's max delay", got)
+	}
+}
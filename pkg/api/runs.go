@@ -0,0 +1,284 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Run statuses a forge run ends in
+const (
+	RunCompleted  = "completed"
+	RunFailed     = "failed"
+	RunCancelled  = "cancelled"
+	RunRolledBack = "rolled_back"
+)
+
+// RunStep is the progress of one step of a forge run
+type RunStep struct {
+	Name        string `json:"name"`
+	Action      string `json:"action,omitempty"`
+	Status      string `json:"status"`
+	StartedAt   string `json:"started_at,omitempty"`
+	CompletedAt string `json:"completed_at,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RunLog is a log line written by a forge run step
+type RunLog struct {
+	Seq       int64  `json:"seq"`
+	Step      string `json:"step,omitempty"`
+	Timestamp string `json:"timestamp,omitempty"`
+	Level     string `json:"level,omitempty"`
+	Message   string `json:"message"`
+}
+
+// Done reports whether the run has finished, successfully or not
+func (r *ForgeRun) Done() bool {
+	switch r.Status {
+	case RunCompleted, RunFailed, RunCancelled, RunRolledBack:
+		return true
+	}
+	return false
+}
+
+// Retryable returns why the run cannot be retried, or nil if it can. A retry
+// starts a new run with the same mold, action and inputs, so the run must
+// have finished without completing and must record all three.
+func (r *ForgeRun) Retryable() error {
+	switch {
+	case !r.Done():
+		return fmt.Errorf("run %s is still %s; wait for it or cancel it first", r.ID, r.Status)
+	case r.Status == RunCompleted:
+		return fmt.Errorf("run %s completed; start a new one with \"shoehorn forge execute %s\"", r.ID, r.MoldSlug)
+	case r.MoldSlug == "" || r.Action == "":
+		return fmt.Errorf("run %s does not record its mold and action; start a new one with \"shoehorn forge execute\"", r.ID)
+	case r.Inputs == nil:
+		return fmt.Errorf("run %s does not record its inputs; start a new one with \"shoehorn forge execute %s\"", r.ID, r.MoldSlug)
+	}
+	return nil
+}
+
+// GetRunLogs returns the log lines of a run with a sequence number above after
+func (c *Client) GetRunLogs(ctx context.Context, runID string, after int64) ([]RunLog, error) {
+	var resp struct {
+		Logs []RunLog `json:"logs"`
+	}
+	q := url.Values{}
+	q.Set("after", strconv.FormatInt(after, 10))
+	if err := c.Get(ctx, "/api/v1/forge/runs/"+runID+"/logs?"+q.Encode(), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Logs, nil
+}
+
+// CancelRun stops a pending or executing run
+func (c *Client) CancelRun(ctx context.Context, runID string) (*ForgeRun, error) {
+	return c.runAction(ctx, runID, "cancel")
+}
+
+// RollbackRun undoes the steps a finished run has carried out
+func (c *Client) RollbackRun(ctx context.Context, runID string) (*ForgeRun, error) {
+	return c.runAction(ctx, runID, "rollback")
+}
+
+// runAction posts to /forge/runs/{id}/{action} and returns the updated run
+func (c *Client) runAction(ctx context.Context, runID, action string) (*ForgeRun, error) {
+	var wrapper struct {
+		Run ForgeRun `json:"run"`
+	}
+	if err := c.Post(ctx, "/api/v1/forge/runs/"+runID+"/"+action, nil, &wrapper); err != nil {
+		return nil, err
+	}
+	return &wrapper.Run, nil
+}
+
+// RunUpdate is a change to a watched run: its new state, or new log lines
+type RunUpdate struct {
+	Run  *ForgeRun
+	Logs []RunLog
+}
+
+// WatchRun follows a run until it finishes or ctx is done, calling fn with
+// every change of its state and every batch of new log lines, and returns
+// the final run. It consumes the server's event stream
+// (/forge/runs/{id}/events) when the server offers one, and polls every
+// interval otherwise or when the stream breaks off.
+func (c *Client) WatchRun(ctx context.Context, runID string, interval time.Duration, fn func(RunUpdate)) (*ForgeRun, error) {
+	w := &runWatcher{client: c, runID: runID, fn: fn, logs: true}
+	run, err := w.stream(ctx)
+	if err == nil {
+		return run, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return w.poll(ctx, interval)
+}
+
+// runWatcher holds what has been reported so far, so that switching from
+// the stream to polling repeats nothing
+type runWatcher struct {
+	client  *Client
+	runID   string
+	fn      func(RunUpdate)
+	last    *ForgeRun
+	lastSeq int64
+	unseq   map[RunLog]bool // lines without a sequence number, by content
+	logs    bool            // whether the server has a logs endpoint
+}
+
+func (w *runWatcher) updateRun(run *ForgeRun) {
+	if w.last != nil && reflect.DeepEqual(w.last, run) {
+		return
+	}
+	w.last = run
+	w.fn(RunUpdate{Run: run})
+}
+
+// updateLogs reports the lines not seen yet. Lines are numbered by Seq; a
+// server that leaves it out gets its lines deduplicated by content instead,
+// as it may send them again on every poll.
+func (w *runWatcher) updateLogs(logs []RunLog) {
+	fresh := logs[:0:0]
+	for _, l := range logs {
+		switch {
+		case l.Seq == 0:
+			if w.unseq == nil {
+				w.unseq = map[RunLog]bool{}
+			}
+			if !w.unseq[l] {
+				w.unseq[l] = true
+				fresh = append(fresh, l)
+			}
+		case l.Seq > w.lastSeq:
+			fresh = append(fresh, l)
+			w.lastSeq = l.Seq
+		}
+	}
+	if len(fresh) > 0 {
+		w.fn(RunUpdate{Logs: fresh})
+	}
+}
+
+func (w *runWatcher) poll(ctx context.Context, interval time.Duration) (*ForgeRun, error) {
+	for {
+		run, err := w.client.GetRun(ctx, w.runID)
+		if err != nil {
+			return nil, err
+		}
+
+		// Logs first: they lead up to the state the run is now in
+		if w.logs {
+			logs, err := w.client.GetRunLogs(ctx, w.runID, w.lastSeq)
+			var apiErr *APIError
+			switch {
+			case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound:
+				w.logs = false
+			case err != nil:
+				return nil, err
+			default:
+				w.updateLogs(logs)
+			}
+		}
+		w.updateRun(run)
+
+		if run.Done() {
+			return run, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// errNoStream means the server does not offer a run event stream
+var errNoStream = errors.New("no event stream")
+
+// stream consumes the run's server-sent events: "run" events carry the run,
+// "log" events one log line. It returns the run once it is done, and an error
+// if the stream is unavailable or ends before that.
+func (w *runWatcher) stream(ctx context.Context) (*ForgeRun, error) {
+	c := w.client
+	if c.cache != nil && c.cache.offline {
+		return nil, errNoStream
+	}
+	if err := c.refreshIfExpired(ctx); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/api/v1/forge/runs/"+w.runID+"/events", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if token := c.GetToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// The client's timeout bounds whole requests, which a stream outlives
+	streamClient := &http.Client{Transport: c.httpClient.Transport}
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return nil, errNoStream
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream") {
+		return nil, errNoStream
+	}
+
+	var event string
+	var data []string
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			field, value, _ := strings.Cut(line, ":")
+			value = strings.TrimPrefix(value, " ")
+			switch field {
+			case "event":
+				event = value
+			case "data":
+				data = append(data, value)
+			}
+			continue
+		}
+
+		// A blank line dispatches the event
+		payload := strings.Join(data, "\n")
+		name := event
+		event, data = "", nil
+		switch name {
+		case "run":
+			var run ForgeRun
+			if err := json.Unmarshal([]byte(payload), &run); err != nil {
+				return nil, fmt.Errorf("decode run event: %w", err)
+			}
+			w.updateRun(&run)
+			if run.Done() {
+				return &run, nil
+			}
+		case "log":
+			var l RunLog
+			if err := json.Unmarshal([]byte(payload), &l); err != nil {
+				return nil, fmt.Errorf("decode log event: %w", err)
+			}
+			w.updateLogs([]RunLog{l})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, errors.New("event stream ended before the run finished")
+}
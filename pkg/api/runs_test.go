@@ -0,0 +1,289 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWatchRun_Polls(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/forge/runs/run-1/events":
+			http.NotFound(w, r)
+		case "/api/v1/forge/runs/run-1":
+			status := "executing"
+			if polls.Add(1) >= 3 {
+				status = "completed"
+			}
+			fmt.Fprintf(w, `{"run":{"id":"run-1","status":%q,"steps":[{"name":"create-repo","status":%q}]}}`, status, status)
+		case "/api/v1/forge/runs/run-1/logs":
+			// The server returns everything; already-seen lines must be dropped
+			fmt.Fprintf(w, `{"logs":[{"seq":1,"step":"create-repo","message":"creating"},{"seq":%d,"message":"poll %s"}]}`, 1+polls.Load(), r.URL.Query().Get("after"))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var statuses, logs []string
+	run, err := NewClient(server.URL).WatchRun(context.Background(), "run-1", time.Millisecond, func(u RunUpdate) {
+		if u.Run != nil {
+			statuses = append(statuses, u.Run.Status)
+		}
+		for _, l := range u.Logs {
+			logs = append(logs, l.Message)
+		}
+	})
+	if err != nil {
+		t.Fatalf("WatchRun() = %v", err)
+	}
+	if run.Status != RunCompleted || !run.Done() {
+		t.Errorf("final status = %q", run.Status)
+	}
+	// An unchanged run is reported once
+	if want := []string{"executing", "completed"}; !reflect.DeepEqual(statuses, want) {
+		t.Errorf("statuses = %v, want %v", statuses, want)
+	}
+	if want := []string{"creating", "poll 0", "poll 2", "poll 3"}; !reflect.DeepEqual(logs, want) {
+		t.Errorf("logs = %v, want %v", logs, want)
+	}
+}
+
+func TestWatchRun_LogsWithoutSeq(t *testing.T) {
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/forge/runs/run-1/events":
+			http.NotFound(w, r)
+		case "/api/v1/forge/runs/run-1":
+			status := "executing"
+			if polls.Add(1) >= 3 {
+				status = "completed"
+			}
+			fmt.Fprintf(w, `{"run":{"id":"run-1","status":%q}}`, status)
+		case "/api/v1/forge/runs/run-1/logs":
+			// No seq: every poll returns the whole log so far
+			lines := []string{`{"timestamp":"t1","message":"creating"}`, `{"timestamp":"t2","message":"pushing"}`}
+			fmt.Fprintf(w, `{"logs":[%s]}`, strings.Join(lines[:min(int(polls.Load()), 2)], ","))
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	var logs []string
+	_, err := NewClient(server.URL).WatchRun(context.Background(), "run-1", time.Millisecond, func(u RunUpdate) {
+		for _, l := range u.Logs {
+			logs = append(logs, l.Message)
+		}
+	})
+	if err != nil {
+		t.Fatalf("WatchRun() = %v", err)
+	}
+	if want := []string{"creating", "pushing"}; !reflect.DeepEqual(logs, want) {
+		t.Errorf("logs = %v, want %v", logs, want)
+	}
+}
+
+func TestWatchRun_NoLogsEndpoint(t *testing.T) {
+	var logRequests atomic.Int32
+	var polls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/forge/runs/run-1":
+			status := "pending"
+			if polls.Add(1) >= 3 {
+				status = "failed"
+			}
+			fmt.Fprintf(w, `{"run":{"id":"run-1","status":%q,"error":"boom"}}`, status)
+		case "/api/v1/forge/runs/run-1/logs":
+			logRequests.Add(1)
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	run, err := NewClient(server.URL).WatchRun(context.Background(), "run-1", time.Millisecond, func(RunUpdate) {})
+	if err != nil {
+		t.Fatalf("WatchRun() = %v", err)
+	}
+	if run.Status != RunFailed || run.Error != "boom" {
+		t.Errorf("run = %+v", run)
+	}
+	if n := logRequests.Load(); n != 1 {
+		t.Errorf("logs requested %d times, want once", n)
+	}
+}
+
+func TestWatchRun_EventStream(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/forge/runs/run-1/events" {
+			t.Errorf("unexpected request %s", r.URL.Path)
+			http.NotFound(w, r)
+			return
+		}
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("Authorization = %q", got)
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, ": keep-alive\n\n")
+		fmt.Fprint(w, "event: run\ndata: {\"id\":\"run-1\",\"status\":\"executing\"}\n\n")
+		fmt.Fprint(w, "event: log\ndata: {\"seq\":1,\"message\":\"line one\"}\n\n")
+		fmt.Fprint(w, "event: log\ndata: {\"seq\":1,\"message\":\"repeated\"}\n\n")
+		fmt.Fprint(w, "event: run\ndata: {\"id\":\"run-1\",\n")
+		fmt.Fprint(w, "data: \"status\":\"rolled_back\"}\n\n")
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetToken("tok")
+	var updates []string
+	run, err := client.WatchRun(context.Background(), "run-1", time.Millisecond, func(u RunUpdate) {
+		if u.Run != nil {
+			updates = append(updates, "run "+u.Run.Status)
+		}
+		for _, l := range u.Logs {
+			updates = append(updates, "log "+l.Message)
+		}
+	})
+	if err != nil {
+		t.Fatalf("WatchRun() = %v", err)
+	}
+	if run.Status != RunRolledBack {
+		t.Errorf("final status = %q", run.Status)
+	}
+	if want := []string{"run executing", "log line one", "run rolled_back"}; !reflect.DeepEqual(updates, want) {
+		t.Errorf("updates = %v, want %v", updates, want)
+	}
+}
+
+func TestWatchRun_StreamBreaksOff(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/forge/runs/run-1/events":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: log\ndata: {\"seq\":1,\"message\":\"from stream\"}\n\n")
+		case "/api/v1/forge/runs/run-1":
+			fmt.Fprint(w, `{"run":{"id":"run-1","status":"completed"}}`)
+		case "/api/v1/forge/runs/run-1/logs":
+			fmt.Fprint(w, `{"logs":[{"seq":1,"message":"from stream"},{"seq":2,"message":"from poll"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	var logs []string
+	if _, err := NewClient(server.URL).WatchRun(context.Background(), "run-1", time.Millisecond, func(u RunUpdate) {
+		for _, l := range u.Logs {
+			logs = append(logs, l.Message)
+		}
+	}); err != nil {
+		t.Fatalf("WatchRun() = %v", err)
+	}
+	if got := strings.Join(logs, ","); got != "from stream,from poll" {
+		t.Errorf("logs = %s", got)
+	}
+}
+
+func TestWatchRun_ContextDone(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/forge/runs/run-1" {
+			fmt.Fprint(w, `{"run":{"id":"run-1","status":"executing"}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := NewClient(server.URL).WatchRun(ctx, "run-1", 10*time.Millisecond, func(RunUpdate) {})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("WatchRun() = %v, want deadline exceeded", err)
+	}
+}
+
+func TestRunActions(t *testing.T) {
+	tests := []struct {
+		action string
+		call   func(*Client) (*ForgeRun, error)
+		status string
+	}{
+		{"cancel", func(c *Client) (*ForgeRun, error) { return c.CancelRun(context.Background(), "run-1") }, RunCancelled},
+		{"rollback", func(c *Client) (*ForgeRun, error) { return c.RollbackRun(context.Background(), "run-1") }, "rolling_back"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.action, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost || r.URL.Path != "/api/v1/forge/runs/run-1/"+tt.action {
+					t.Errorf("request = %s %s", r.Method, r.URL.Path)
+				}
+				fmt.Fprintf(w, `{"run":{"id":"run-1","status":%q}}`, tt.status)
+			}))
+			defer server.Close()
+
+			run, err := tt.call(NewClient(server.URL))
+			if err != nil {
+				t.Fatalf("%s = %v", tt.action, err)
+			}
+			if run.ID != "run-1" || run.Status != tt.status {
+				t.Errorf("run = %+v", run)
+			}
+		})
+	}
+}
+
+func TestRunActions_Conflict(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		fmt.Fprint(w, `{"error":{"message":"run already completed"}}`)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(server.URL).CancelRun(context.Background(), "run-1")
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusConflict {
+		t.Fatalf("CancelRun() = %v, want 409 APIError", err)
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	inputs := map[string]any{"name": "orders"}
+	tests := []struct {
+		name string
+		run  ForgeRun
+		want string // substring of the error; empty for retryable
+	}{
+		{"failed", ForgeRun{ID: "r", Status: RunFailed, MoldSlug: "repo", Action: "create", Inputs: inputs}, ""},
+		{"cancelled", ForgeRun{ID: "r", Status: RunCancelled, MoldSlug: "repo", Action: "create", Inputs: inputs}, ""},
+		{"rolled back", ForgeRun{ID: "r", Status: RunRolledBack, MoldSlug: "repo", Action: "create", Inputs: inputs}, ""},
+		{"empty inputs", ForgeRun{ID: "r", Status: RunFailed, MoldSlug: "repo", Action: "create", Inputs: map[string]any{}}, ""},
+		{"running", ForgeRun{ID: "r", Status: "running", MoldSlug: "repo", Action: "create", Inputs: inputs}, "is still running"},
+		{"pending", ForgeRun{ID: "r", Status: "pending", MoldSlug: "repo", Action: "create", Inputs: inputs}, "is still pending"},
+		{"completed", ForgeRun{ID: "r", Status: RunCompleted, MoldSlug: "repo", Action: "create", Inputs: inputs}, `completed; start a new one with "shoehorn forge execute repo"`},
+		{"no mold", ForgeRun{ID: "r", Status: RunFailed, Action: "create", Inputs: inputs}, "does not record its mold and action"},
+		{"no action", ForgeRun{ID: "r", Status: RunFailed, MoldSlug: "repo", Inputs: inputs}, "does not record its mold and action"},
+		{"nil inputs", ForgeRun{ID: "r", Status: RunFailed, MoldSlug: "repo", Action: "create"}, "does not record its inputs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run.Retryable()
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Retryable() = %v, want nil", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Retryable() = %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// ─── Personal Access Token Types ─────────────────────────────────────────────
+
+// PersonalAccessToken is the metadata of a PAT. The secret itself is only
+// returned once, when the token is created.
+type PersonalAccessToken struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Prefix     string    `json:"prefix,omitempty"` // leading characters of the secret, e.g. "shp_3fa9"
+	Scopes     []string  `json:"scopes,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	ExpiresAt  time.Time `json:"expires_at,omitzero"`
+	LastUsedAt time.Time `json:"last_used_at,omitzero"`
+}
+
+// Expired reports whether the token has passed its expiry
+func (t *PersonalAccessToken) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
+
+// CreateTokenRequest describes a new PAT. Empty Scopes grants the server's
+// default scopes; a zero ExpiresAt uses the server's default lifetime.
+type CreateTokenRequest struct {
+	Name      string    `json:"name"`
+	Scopes    []string  `json:"scopes,omitempty"`
+	ExpiresAt time.Time `json:"expires_at,omitzero"`
+}
+
+// CreatedToken is a newly created PAT including its secret
+type CreatedToken struct {
+	PersonalAccessToken
+	Token string `json:"token"`
+}
+
+// ─── API Methods ──────────────────────────────────────────────────────────────
+
+// ListTokens returns the current user's Personal Access Tokens
+func (c *Client) ListTokens(ctx context.Context) ([]*PersonalAccessToken, error) {
+	var resp struct {
+		Tokens []*PersonalAccessToken `json:"tokens"`
+	}
+	if err := c.Get(ctx, "/api/v1/auth/tokens", &resp); err != nil {
+		return nil, fmt.Errorf("list tokens: %w", err)
+	}
+	return resp.Tokens, nil
+}
+
+// CreateToken creates a Personal Access Token. The returned secret cannot be retrieved again.
+func (c *Client) CreateToken(ctx context.Context, req CreateTokenRequest) (*CreatedToken, error) {
+	var token CreatedToken
+	if err := c.Post(ctx, "/api/v1/auth/tokens", req, &token); err != nil {
+		return nil, fmt.Errorf("create token: %w", err)
+	}
+	if token.Token == "" {
+		return nil, fmt.Errorf("create token: server response did not include the token secret")
+	}
+	return &token, nil
+}
+
+// RevokeTokenByID revokes one of the current user's Personal Access Tokens
+func (c *Client) RevokeTokenByID(ctx context.Context, id string) error {
+	if err := c.Delete(ctx, "/api/v1/auth/tokens/"+url.PathEscape(id)); err != nil {
+		return fmt.Errorf("revoke token %s: %w", id, err)
+	}
+	return nil
+}
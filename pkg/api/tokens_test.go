@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestListTokens(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet || r.URL.Path != "/api/v1/auth/tokens" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		w.Write([]byte(`{"tokens":[
+			{"id":"t1","name":"ci","scopes":["catalog:read"],"created_at":"2026-01-01T00:00:00Z","last_used_at":"2026-02-01T00:00:00Z"},
+			{"id":"t2","name":"old","created_at":"2025-01-01T00:00:00Z","expires_at":"2025-06-01T00:00:00Z"}
+		]}`))
+	}))
+	defer server.Close()
+
+	tokens, err := NewClient(server.URL).ListTokens(context.Background())
+	if err != nil {
+		t.Fatalf("ListTokens() = %v", err)
+	}
+	if len(tokens) != 2 {
+		t.Fatalf("len = %d, want 2", len(tokens))
+	}
+	if tokens[0].LastUsedAt.IsZero() || tokens[0].Expired() {
+		t.Errorf("token t1 = %+v, want used and unexpired", tokens[0])
+	}
+	if !tokens[1].Expired() {
+		t.Errorf("token t2 should be expired")
+	}
+}
+
+func TestCreateToken(t *testing.T) {
+	expires := time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/v1/auth/tokens" {
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+		}
+		var req CreateTokenRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Name != "ci" || len(req.Scopes) != 1 || !req.ExpiresAt.Equal(expires) {
+			t.Errorf("request = %+v", req)
+		}
+		json.NewEncoder(w).Encode(CreatedToken{
+			PersonalAccessToken: PersonalAccessToken{ID: "t1", Name: req.Name, Scopes: req.Scopes, ExpiresAt: req.ExpiresAt},
+			Token:               "shp_secret",
+		})
+	}))
+	defer server.Close()
+
+	token, err := NewClient(server.URL).CreateToken(context.Background(), CreateTokenRequest{
+		Name:      "ci",
+		Scopes:    []string{"catalog:read"},
+		ExpiresAt: expires,
+	})
+	if err != nil {
+		t.Fatalf("CreateToken() = %v", err)
+	}
+	if token.ID != "t1" || token.Token != "shp_secret" {
+		t.Errorf("token = %+v", token)
+	}
+}
+
+func TestCreateToken_OmitsUnsetExpiry(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw map[string]any
+		json.NewDecoder(r.Body).Decode(&raw)
+		if _, ok := raw["expires_at"]; ok {
+			t.Errorf("expires_at sent without a TTL: %v", raw)
+		}
+		w.Write([]byte(`{"id":"t1","name":"ci"}`))
+	}))
+	defer server.Close()
+
+	if _, err := NewClient(server.URL).CreateToken(context.Background(), CreateTokenRequest{Name: "ci"}); err == nil {
+		t.Error("expected error when the response has no secret")
+	}
+}
+
+func TestRevokeTokenByID(t *testing.T) {
+	var gotPath string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			t.Errorf("method = %s, want DELETE", r.Method)
+		}
+		gotPath = r.URL.EscapedPath()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	if err := NewClient(server.URL).RevokeTokenByID(context.Background(), "a/b"); err != nil {
+		t.Fatalf("RevokeTokenByID() = %v", err)
+	}
+	if gotPath != "/api/v1/auth/tokens/a%2Fb" {
+		t.Errorf("path = %q", gotPath)
+	}
+}
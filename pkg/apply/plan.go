@@ -0,0 +1,164 @@
+// Package apply plans the changes that bring the catalog in line with a set
+// of Shoehorn manifests.
+package apply
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+)
+
+// ErrStaleData is returned when a prune would be planned from cached reads
+var ErrStaleData = errors.New("catalog data came from the local cache; refusing to plan deletions from it")
+
+// Source is a manifest file and its content
+type Source struct {
+	Name string
+	Data []byte
+}
+
+// Change is one step of a plan
+type Change struct {
+	ID     string   `json:"id,omitempty" yaml:"id,omitempty"`
+	Action string   `json:"action" yaml:"action"` // create, update, delete, unchanged, invalid
+	File   string   `json:"file,omitempty" yaml:"file,omitempty"`
+	Fields []string `json:"fields,omitempty" yaml:"fields,omitempty"` // changed fields, for updates
+	Error  string   `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Content is the manifest sent to the server for creates and updates
+	Content string `json:"-" yaml:"-"`
+}
+
+// Plan is the plan, and after applying, its outcome
+type Plan struct {
+	DryRun  bool      `json:"dry_run" yaml:"dry_run"`
+	Changes []*Change `json:"changes" yaml:"changes"`
+	Summary Summary   `json:"summary" yaml:"summary"`
+}
+
+// Summary counts a plan's changes by action
+type Summary struct {
+	Create    int `json:"create" yaml:"create"`
+	Update    int `json:"update" yaml:"update"`
+	Delete    int `json:"delete" yaml:"delete"`
+	Unchanged int `json:"unchanged" yaml:"unchanged"`
+	Invalid   int `json:"invalid" yaml:"invalid"`
+	Failed    int `json:"failed" yaml:"failed"`
+}
+
+// BuildPlan validates each manifest and compares it with the live catalog.
+// Invalid manifests become "invalid" changes; errors talking to the server
+// abort. With prune, entities owned by the manifests' owners that no
+// manifest describes become deletes.
+//
+// The live catalog is read past the response cache, since a plan built from
+// stale data could delete entities that a manifest now describes. Should any
+// read still have been answered from the cache, a prune is refused with
+// ErrStaleData.
+func BuildPlan(ctx context.Context, client *api.Client, sources []Source, prune bool) (*Plan, error) {
+	client.BypassCache()
+
+	plan := &Plan{Changes: []*Change{}}
+	defined := map[string]string{} // entity ID → file
+	var owners []string
+
+	for _, src := range sources {
+		change := &Change{File: src.Name, Content: string(src.Data)}
+		plan.Changes = append(plan.Changes, change)
+
+		desired, err := manifest.Parse(src.Data)
+		if err != nil {
+			change.Action, change.Error = "invalid", err.Error()
+			continue
+		}
+		change.ID = desired.Service.ID
+		if other, ok := defined[change.ID]; ok {
+			change.Action, change.Error = "invalid", fmt.Sprintf("entity %s is also defined in %s", change.ID, other)
+			continue
+		}
+		defined[change.ID] = src.Name
+
+		v, err := client.ValidateManifest(ctx, change.Content)
+		if err != nil {
+			return nil, fmt.Errorf("validate %s: %w", src.Name, err)
+		}
+		if !v.Valid {
+			change.Action, change.Error = "invalid", ValidationMessage(v.Errors)
+			continue
+		}
+		if owner := desired.OwnerID(); owner != "" && !slices.Contains(owners, owner) {
+			owners = append(owners, owner)
+		}
+
+		live, err := client.GetEntity(ctx, change.ID)
+		switch {
+		case api.IsNotFound(err):
+			change.Action = "create"
+		case err != nil:
+			return nil, fmt.Errorf("get entity %s: %w", change.ID, err)
+		default:
+			change.Fields = manifest.Changes(manifest.FromEntity(live), desired)
+			change.Action = "update"
+			if len(change.Fields) == 0 {
+				change.Action = "unchanged"
+			}
+		}
+	}
+
+	if prune {
+		var deletes []*Change
+		for _, owner := range owners {
+			entities, _, err := client.ListEntities(ctx, api.ListEntitiesOpts{Owner: owner})
+			if err != nil {
+				return nil, fmt.Errorf("list entities owned by %s: %w", owner, err)
+			}
+			for _, e := range entities {
+				if _, ok := defined[e.ID]; !ok {
+					defined[e.ID] = ""
+					deletes = append(deletes, &Change{ID: e.ID, Action: "delete"})
+				}
+			}
+		}
+		if _, cached := api.CachedSince(); cached {
+			return nil, ErrStaleData
+		}
+		slices.SortFunc(deletes, func(a, b *Change) int { return strings.Compare(a.ID, b.ID) })
+		plan.Changes = append(plan.Changes, deletes...)
+	}
+
+	for _, c := range plan.Changes {
+		switch c.Action {
+		case "create":
+			plan.Summary.Create++
+		case "update":
+			plan.Summary.Update++
+		case "delete":
+			plan.Summary.Delete++
+		case "unchanged":
+			plan.Summary.Unchanged++
+		case "invalid":
+			plan.Summary.Invalid++
+		}
+	}
+	return plan, nil
+}
+
+// ValidationMessage joins server validation errors into one line
+func ValidationMessage(errs []api.ManifestValidationError) string {
+	if len(errs) == 0 {
+		return "invalid manifest"
+	}
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.Message
+		if e.Field != "" {
+			msgs[i] = e.Field + ": " + e.Message
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
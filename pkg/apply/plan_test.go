@@ -0,0 +1,205 @@
+package apply
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+// catalogEntity is an entity served by newCatalogServer
+type catalogEntity struct {
+	ID, Name, Owner string
+}
+
+// newCatalogServer fakes the manifest and entity endpoints over a fixed
+// catalog. Manifests containing "invalid: true" fail validation.
+func newCatalogServer(t *testing.T, entities []catalogEntity) *api.Client {
+	t.Helper()
+	item := func(e catalogEntity) map[string]any {
+		return map[string]any{
+			"service": map[string]string{"id": e.ID, "name": e.Name},
+			"owner":   []map[string]string{{"type": "team", "id": e.Owner}},
+		}
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/manifests/validate":
+			var req api.ValidateManifestRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			if strings.Contains(req.Content, "invalid: true") {
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				w.Write([]byte(`{"valid":false,"errors":[{"field":"invalid","message":"is not allowed"}]}`))
+				return
+			}
+			w.Write([]byte(`{"valid":true,"errors":[]}`))
+		case r.URL.Path == "/api/v1/entities":
+			owner := r.URL.Query().Get("owner")
+			var page []map[string]any
+			for _, e := range entities {
+				if e.Owner == owner {
+					page = append(page, item(e))
+				}
+			}
+			json.NewEncoder(w).Encode(map[string]any{"entities": page})
+		case strings.HasPrefix(r.URL.Path, "/api/v1/entities/"):
+			id := strings.TrimPrefix(r.URL.Path, "/api/v1/entities/")
+			for _, e := range entities {
+				if e.ID == id {
+					json.NewEncoder(w).Encode(map[string]any{"entity": item(e)})
+					return
+				}
+			}
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error":"not found"}`))
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	c := api.NewClient(server.URL)
+	c.SetRetryPolicy(api.RetryPolicy{})
+	return c
+}
+
+func source(name, id, entityName, owner string) Source {
+	return Source{Name: name, Data: []byte("schemaVersion: 1\nservice:\n  id: " + id +
+		"\n  name: " + entityName + "\nowner:\n  - type: team\n    id: " + owner + "\n")}
+}
+
+func TestBuildPlan(t *testing.T) {
+	catalog := []catalogEntity{
+		{ID: "orders", Name: "Orders", Owner: "payments"},
+		{ID: "billing", Name: "Billing", Owner: "payments"},
+		{ID: "ledger", Name: "Ledger", Owner: "payments"},
+		{ID: "legacy", Name: "Legacy", Owner: "payments"},
+		{ID: "search", Name: "Search", Owner: "discovery"},
+	}
+
+	tests := []struct {
+		name    string
+		sources []Source
+		prune   bool
+		want    []Change
+		summary Summary
+	}{
+		{
+			name: "create, update and unchanged",
+			sources: []Source{
+				source("new.yml", "invoices", "Invoices", "payments"),
+				source("orders.yml", "orders", "Orders v2", "payments"),
+				source("billing.yml", "billing", "Billing", "payments"),
+			},
+			want: []Change{
+				{ID: "invoices", Action: "create", File: "new.yml"},
+				{ID: "orders", Action: "update", File: "orders.yml", Fields: []string{"service.name"}},
+				{ID: "billing", Action: "unchanged", File: "billing.yml"},
+			},
+			summary: Summary{Create: 1, Update: 1, Unchanged: 1},
+		},
+		{
+			name: "duplicate IDs",
+			sources: []Source{
+				source("a.yml", "orders", "Orders", "payments"),
+				source("b.yml", "orders", "Orders", "payments"),
+			},
+			want: []Change{
+				{ID: "orders", Action: "unchanged", File: "a.yml"},
+				{ID: "orders", Action: "invalid", File: "b.yml", Error: "entity orders is also defined in a.yml"},
+			},
+			summary: Summary{Unchanged: 1, Invalid: 1},
+		},
+		{
+			name: "invalid manifests",
+			sources: []Source{
+				{Name: "empty.yml", Data: []byte("description: no service\n")},
+				{Name: "rejected.yml", Data: []byte("schemaVersion: 1\nservice:\n  id: orders\ninvalid: true\n")},
+			},
+			want: []Change{
+				{Action: "invalid", File: "empty.yml", Error: "parse manifest: service.id is required"},
+				{ID: "orders", Action: "invalid", File: "rejected.yml", Error: "invalid: is not allowed"},
+			},
+			summary: Summary{Invalid: 2},
+		},
+		{
+			name: "prune deletes the owners' undescribed entities",
+			sources: []Source{
+				source("orders.yml", "orders", "Orders", "payments"),
+				source("billing.yml", "billing", "Billing", "payments"),
+			},
+			prune: true,
+			want: []Change{
+				{ID: "orders", Action: "unchanged", File: "orders.yml"},
+				{ID: "billing", Action: "unchanged", File: "billing.yml"},
+				{ID: "ledger", Action: "delete"},
+				{ID: "legacy", Action: "delete"},
+			},
+			summary: Summary{Delete: 2, Unchanged: 2},
+		},
+		{
+			name: "without prune nothing is deleted",
+			sources: []Source{
+				source("orders.yml", "orders", "Orders", "payments"),
+			},
+			want: []Change{
+				{ID: "orders", Action: "unchanged", File: "orders.yml"},
+			},
+			summary: Summary{Unchanged: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newCatalogServer(t, catalog)
+			plan, err := BuildPlan(context.Background(), client, tt.sources, tt.prune)
+			if err != nil {
+				t.Fatalf("BuildPlan() = %v", err)
+			}
+			got := make([]Change, len(plan.Changes))
+			for i, c := range plan.Changes {
+				got[i] = *c
+				got[i].Content = ""
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("changes =\n%+v\nwant\n%+v", got, tt.want)
+			}
+			if plan.Summary != tt.summary {
+				t.Errorf("summary = %+v, want %+v", plan.Summary, tt.summary)
+			}
+		})
+	}
+}
+
+func TestBuildPlan_ServerErrorAborts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+	client := api.NewClient(server.URL)
+	client.SetRetryPolicy(api.RetryPolicy{})
+
+	_, err := BuildPlan(context.Background(), client, []Source{source("orders.yml", "orders", "Orders", "payments")}, false)
+	if err == nil || !strings.Contains(err.Error(), "validate orders.yml") {
+		t.Errorf("BuildPlan() = %v, want validate error", err)
+	}
+}
+
+func TestValidationMessage(t *testing.T) {
+	if got := ValidationMessage(nil); got != "invalid manifest" {
+		t.Errorf("ValidationMessage(nil) = %q", got)
+	}
+	got := ValidationMessage([]api.ManifestValidationError{
+		{Field: "service.id", Message: "is required"},
+		{Message: "manifest is empty"},
+	})
+	if want := "service.id: is required; manifest is empty"; got != want {
+		t.Errorf("ValidationMessage() = %q, want %q", got, want)
+	}
+}
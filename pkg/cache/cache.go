@@ -0,0 +1,213 @@
+// Package cache stores API responses on disk so that catalog reads can be
+// revalidated cheaply and served when the server is unreachable.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Entry is a cached response body with the validators needed to revalidate it
+type Entry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	StoredAt     time.Time `json:"stored_at"`
+	Body         []byte    `json:"body"`
+}
+
+// Age returns how long ago the entry was stored or last revalidated
+func (e *Entry) Age() time.Duration {
+	return time.Since(e.StoredAt)
+}
+
+// Store is an on-disk cache with one directory per profile and one file per URL.
+// Files are written atomically, so concurrent CLI invocations never see a
+// partial entry.
+type Store struct {
+	dir string
+}
+
+// New returns a store rooted at dir. The directory is created on first write.
+func New(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Dir returns the cache root directory
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Get returns the entry for rawURL in profile, or nil if there is none
+func (s *Store) Get(profile, rawURL string) (*Entry, error) {
+	data, err := os.ReadFile(s.entryPath(profile, rawURL))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache entry: %w", err)
+	}
+	var e Entry
+	if err := json.Unmarshal(data, &e); err != nil || e.URL != rawURL {
+		// Corrupt or colliding entry; treat it as a miss and let Put replace it
+		return nil, nil
+	}
+	return &e, nil
+}
+
+// Put stores e under profile, replacing any previous entry for e.URL
+func (s *Store) Put(profile string, e *Entry) error {
+	dir := s.profileDir(profile)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("create cache directory: %w", err)
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return fmt.Errorf("encode cache entry: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.entryPath(profile, e.URL)); err != nil {
+		return fmt.Errorf("write cache entry: %w", err)
+	}
+	return nil
+}
+
+// Clear removes the cache for the given profiles, or for all profiles if none
+// are given, and returns the number of entries removed.
+func (s *Store) Clear(profiles ...string) (int, error) {
+	if len(profiles) == 0 {
+		var err error
+		if profiles, err = s.profiles(); err != nil {
+			return 0, err
+		}
+	}
+	removed := 0
+	for _, p := range profiles {
+		st, err := s.profileStats(p)
+		if err != nil {
+			return removed, err
+		}
+		if err := os.RemoveAll(s.profileDir(p)); err != nil {
+			return removed, fmt.Errorf("clear cache for %s: %w", p, err)
+		}
+		removed += st.Entries
+	}
+	return removed, nil
+}
+
+// ProfileStats summarizes the cache for one profile
+type ProfileStats struct {
+	Profile string    `json:"profile"`
+	Entries int       `json:"entries"`
+	Bytes   int64     `json:"bytes"`
+	Oldest  time.Time `json:"oldest,omitzero"`
+	Newest  time.Time `json:"newest,omitzero"`
+}
+
+// Stats returns per-profile statistics, sorted by profile name
+func (s *Store) Stats() ([]ProfileStats, error) {
+	profiles, err := s.profiles()
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]ProfileStats, 0, len(profiles))
+	for _, p := range profiles {
+		st, err := s.profileStats(p)
+		if err != nil {
+			return nil, err
+		}
+		stats = append(stats, st)
+	}
+	return stats, nil
+}
+
+// profileStats counts the entries of a single profile. File modification
+// times stand in for StoredAt so that no entry has to be decoded.
+func (s *Store) profileStats(profile string) (ProfileStats, error) {
+	st := ProfileStats{Profile: profile}
+	files, err := os.ReadDir(s.profileDir(profile))
+	if errors.Is(err, fs.ErrNotExist) {
+		return st, nil
+	}
+	if err != nil {
+		return st, fmt.Errorf("read cache: %w", err)
+	}
+	for _, f := range files {
+		if !strings.HasSuffix(f.Name(), ".json") {
+			continue
+		}
+		info, err := f.Info()
+		if err != nil {
+			continue
+		}
+		st.Entries++
+		st.Bytes += info.Size()
+		if st.Oldest.IsZero() || info.ModTime().Before(st.Oldest) {
+			st.Oldest = info.ModTime()
+		}
+		if info.ModTime().After(st.Newest) {
+			st.Newest = info.ModTime()
+		}
+	}
+	return st, nil
+}
+
+// profiles lists the profiles that have a cache directory
+func (s *Store) profiles() ([]string, error) {
+	dirs, err := os.ReadDir(s.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read cache: %w", err)
+	}
+	var names []string
+	for _, d := range dirs {
+		if !d.IsDir() {
+			continue
+		}
+		if name, err := url.PathUnescape(d.Name()); err == nil {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// profileDir returns the directory for profile. Names are escaped so that
+// one containing "/" or starting with "." stays inside the cache root.
+func (s *Store) profileDir(profile string) string {
+	name := url.PathEscape(profile)
+	if name == "" || strings.HasPrefix(name, ".") {
+		name = "%2E" + strings.TrimPrefix(name, ".")
+	}
+	return filepath.Join(s.dir, name)
+}
+
+// entryPath returns the file holding the entry for rawURL
+func (s *Store) entryPath(profile, rawURL string) string {
+	sum := sha256.Sum256([]byte(rawURL))
+	return filepath.Join(s.profileDir(profile), hex.EncodeToString(sum[:])+".json")
+}
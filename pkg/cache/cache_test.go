@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStore_PutGet(t *testing.T) {
+	s := New(t.TempDir())
+
+	if e, err := s.Get("dev", "https://api/x"); err != nil || e != nil {
+		t.Fatalf("Get() on empty cache = %v, %v; want nil, nil", e, err)
+	}
+
+	want := &Entry{URL: "https://api/x", ETag: `"v1"`, StoredAt: time.Now().Round(0), Body: []byte(`{"ok":true}`)}
+	if err := s.Put("dev", want); err != nil {
+		t.Fatalf("Put() = %v", err)
+	}
+	got, err := s.Get("dev", "https://api/x")
+	if err != nil || got == nil {
+		t.Fatalf("Get() = %v, %v", got, err)
+	}
+	if got.ETag != want.ETag || string(got.Body) != string(want.Body) || !got.StoredAt.Equal(want.StoredAt) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+
+	if e, _ := s.Get("prod", "https://api/x"); e != nil {
+		t.Error("entry leaked across profiles")
+	}
+}
+
+func TestStore_CorruptEntryIsMiss(t *testing.T) {
+	s := New(t.TempDir())
+	if err := s.Put("dev", &Entry{URL: "u", Body: []byte(`{}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(s.entryPath("dev", "u"), []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if e, err := s.Get("dev", "u"); e != nil || err != nil {
+		t.Errorf("Get() = %v, %v; want miss", e, err)
+	}
+}
+
+func TestStore_ProfileDirStaysInsideRoot(t *testing.T) {
+	root := t.TempDir()
+	s := New(root)
+	for _, name := range []string{"..", "../evil", "a/b", ".hidden", ""} {
+		dir := s.profileDir(name)
+		if filepath.Dir(dir) != root {
+			t.Errorf("profileDir(%q) = %q, want a direct child of %q", name, dir, root)
+		}
+	}
+}
+
+func TestStore_StatsAndClear(t *testing.T) {
+	s := New(t.TempDir())
+	for _, e := range []struct{ profile, url string }{
+		{"dev", "a"}, {"dev", "b"}, {"prod", "a"}, {"team/x", "a"},
+	} {
+		if err := s.Put(e.profile, &Entry{URL: e.url, StoredAt: time.Now(), Body: []byte(`{}`)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stats, err := s.Stats()
+	if err != nil {
+		t.Fatalf("Stats() = %v", err)
+	}
+	if len(stats) != 3 || stats[0].Profile != "dev" || stats[0].Entries != 2 || stats[2].Profile != "team/x" {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+	if stats[0].Bytes == 0 || stats[0].Oldest.IsZero() {
+		t.Errorf("Stats() for dev = %+v, want size and times", stats[0])
+	}
+
+	removed, err := s.Clear("dev")
+	if err != nil || removed != 2 {
+		t.Fatalf("Clear(dev) = %d, %v; want 2", removed, err)
+	}
+	if e, _ := s.Get("prod", "a"); e == nil {
+		t.Error("Clear(dev) removed prod entries")
+	}
+
+	removed, err = s.Clear()
+	if err != nil || removed != 2 {
+		t.Fatalf("Clear() = %d, %v; want 2", removed, err)
+	}
+	if stats, _ := s.Stats(); len(stats) != 0 {
+		t.Errorf("Stats() after Clear() = %+v, want empty", stats)
+	}
+}
+
+func TestStore_StatsMissingDir(t *testing.T) {
+	s := New(filepath.Join(t.TempDir(), "nope"))
+	stats, err := s.Stats()
+	if err != nil || len(stats) != 0 {
+		t.Errorf("Stats() = %v, %v; want empty", stats, err)
+	}
+}
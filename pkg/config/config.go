@@ -4,46 +4,112 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
+// Environment variables consulted when the corresponding global flag is not set
+const (
+	EnvConfigPath = "SHOEHORN_CONFIG"
+	EnvProfile    = "SHOEHORN_PROFILE"
+)
+
+// pathOverride and profileOverride hold the values of the global --config and
+// --profile flags. They are set once by the root command before any subcommand runs.
+var (
+	pathOverride    string
+	profileOverride string
+)
+
+// SetPathOverride sets the config file path from the --config flag.
+// An empty value falls back to $SHOEHORN_CONFIG, then the default path.
+func SetPathOverride(path string) {
+	pathOverride = path
+}
+
+// SetProfileOverride sets the active profile from the --profile flag.
+// An empty value falls back to $SHOEHORN_PROFILE, then current_profile from the file.
+func SetProfileOverride(name string) {
+	profileOverride = name
+}
+
 // Config represents the CLI configuration
 type Config struct {
-	Version        string              `yaml:"version"`
-	CurrentProfile string              `yaml:"current_profile"`
-	Profiles       map[string]*Profile `yaml:"profiles"`
+	Version        string `yaml:"version" json:"version"`
+	CurrentProfile string `yaml:"current_profile" json:"current_profile"`
+
+	// CredentialStore selects where tokens are kept: auto, keyring, file, or plaintext.
+	// Empty means auto; the resolved backend is written back on the next save.
+	CredentialStore string `yaml:"credential_store,omitempty" json:"credential_store,omitempty"`
+
+	Profiles map[string]*Profile `yaml:"profiles" json:"profiles"`
+
+	// activeProfile is the profile selected by flag or env for this invocation.
+	// It is never written back, so --profile does not change current_profile.
+	activeProfile string
+
+	creds credentialState
 }
 
 // Profile represents an authentication profile
 type Profile struct {
-	Name   string `yaml:"name"`
-	Server string `yaml:"server"`
-	Auth   *Auth  `yaml:"auth,omitempty"`
+	Name   string         `yaml:"name" json:"name"`
+	Server string         `yaml:"server" json:"server"`
+	Auth   *Auth          `yaml:"auth,omitempty" json:"auth,omitempty"`
+	Retry  *RetrySettings `yaml:"retry,omitempty" json:"retry,omitempty"`
+}
+
+// RetrySettings overrides the API client's retry budget for a profile.
+// Unset fields keep the client defaults.
+type RetrySettings struct {
+	// MaxRetries is the number of retries after the first attempt; 0 disables retries
+	MaxRetries *int `yaml:"max_retries,omitempty" json:"max_retries,omitempty"`
+	// MaxDelay caps a single backoff wait, including a server's Retry-After
+	MaxDelay time.Duration `yaml:"max_delay,omitempty" json:"max_delay,omitempty"`
 }
 
 // Auth contains authentication credentials
 type Auth struct {
-	ProviderType string    `yaml:"provider_type"`
-	Issuer       string    `yaml:"issuer"`
-	ClientID     string    `yaml:"client_id"`
-	AccessToken  string    `yaml:"access_token,omitempty"`
-	RefreshToken string    `yaml:"refresh_token,omitempty"`
-	TokenType    string    `yaml:"token_type,omitempty"`
-	ExpiresAt    time.Time `yaml:"expires_at,omitempty"`
-	User         *User     `yaml:"user,omitempty"`
+	ProviderType string    `yaml:"provider_type" json:"provider_type"`
+	Issuer       string    `yaml:"issuer" json:"issuer"`
+	ClientID     string    `yaml:"client_id" json:"client_id"`
+	AccessToken  string    `yaml:"access_token,omitempty" json:"access_token,omitempty"`
+	RefreshToken string    `yaml:"refresh_token,omitempty" json:"refresh_token,omitempty"`
+	TokenType    string    `yaml:"token_type,omitempty" json:"token_type,omitempty"`
+	ExpiresAt    time.Time `yaml:"expires_at,omitempty" json:"expires_at,omitzero"`
+	User         *User     `yaml:"user,omitempty" json:"user,omitempty"`
+
+	// CredentialRef points at the tokens in the credential store. When set,
+	// AccessToken and RefreshToken are not written to the config file.
+	CredentialRef string `yaml:"credential_ref,omitempty" json:"credential_ref,omitempty"`
+}
+
+// HasCredentials reports whether the profile holds a token, either loaded or in the credential store
+func (a *Auth) HasCredentials() bool {
+	return a != nil && (a.AccessToken != "" || a.CredentialRef != "")
 }
 
 // User contains user information from token
 type User struct {
-	Email    string `yaml:"email"`
-	Name     string `yaml:"name,omitempty"`
-	TenantID string `yaml:"tenant_id,omitempty"`
+	Email    string `yaml:"email" json:"email"`
+	Name     string `yaml:"name,omitempty" json:"name,omitempty"`
+	TenantID string `yaml:"tenant_id,omitempty" json:"tenant_id,omitempty"`
 }
 
-// GetConfigPath returns the path to the config file
+// redactedValue replaces secrets in Redacted output
+const redactedValue = "REDACTED"
+
+// GetConfigPath returns the path to the config file.
+// Resolution order: --config flag, $SHOEHORN_CONFIG, $HOME/.shoehorn/config.yaml.
 func GetConfigPath() (string, error) {
+	if pathOverride != "" {
+		return pathOverride, nil
+	}
+	if p := os.Getenv(EnvConfigPath); p != "" {
+		return p, nil
+	}
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -51,15 +117,31 @@ func GetConfigPath() (string, error) {
 	return filepath.Join(home, ".shoehorn", "config.yaml"), nil
 }
 
+// CacheDir returns the API response cache directory, which sits next to the
+// config file (by default $HOME/.shoehorn/cache).
+func CacheDir() (string, error) {
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(filepath.Dir(configPath), "cache"), nil
+}
+
 // EnsureConfigDir creates the config directory if it doesn't exist
 func EnsureConfigDir() error {
-	home, err := os.UserHomeDir()
+	configPath, err := GetConfigPath()
 	if err != nil {
 		return err
 	}
+	return os.MkdirAll(filepath.Dir(configPath), 0700)
+}
 
-	configDir := filepath.Join(home, ".shoehorn")
-	return os.MkdirAll(configDir, 0700)
+// resolveActiveProfile returns the profile name selected by flag or env, if any.
+func resolveActiveProfile() string {
+	if profileOverride != "" {
+		return profileOverride
+	}
+	return os.Getenv(EnvProfile)
 }
 
 // Load reads the config file
@@ -80,6 +162,7 @@ func Load() (*Config, error) {
 					Server: "http://localhost:8080",
 				},
 			},
+			activeProfile: resolveActiveProfile(),
 		}, nil
 	}
 
@@ -92,6 +175,15 @@ func Load() (*Config, error) {
 	if err := yaml.Unmarshal(data, &cfg); err != nil {
 		return nil, err
 	}
+	cfg.activeProfile = resolveActiveProfile()
+	cfg.creds.storedRefs = cfg.credentialRefs()
+	cfg.creds.inline = cfg.inlineTokens()
+
+	// Only the active profile's tokens are loaded eagerly, so an encrypted
+	// store is unlocked at most once and other profiles stay untouched.
+	if err := cfg.LoadCredentials(cfg.ActiveProfileName()); err != nil {
+		return nil, err
+	}
 
 	return &cfg, nil
 }
@@ -107,19 +199,40 @@ func (c *Config) Save() error {
 		return err
 	}
 
-	data, err := yaml.Marshal(c)
+	out := c.clone()
+	if err := c.storeCredentials(out); err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(out)
 	if err != nil {
 		return err
 	}
 
-	return os.WriteFile(configPath, data, 0600)
+	if err := os.WriteFile(configPath, data, 0600); err != nil {
+		return err
+	}
+
+	// Secrets left behind by a credential store migration are removed only
+	// once the config no longer references them.
+	return c.purgeOldCredentials()
+}
+
+// ActiveProfileName returns the name of the profile in effect for this invocation:
+// the --profile flag, then $SHOEHORN_PROFILE, then current_profile from the file.
+func (c *Config) ActiveProfileName() string {
+	if c.activeProfile != "" {
+		return c.activeProfile
+	}
+	return c.CurrentProfile
 }
 
 // GetCurrentProfile returns the current active profile
 func (c *Config) GetCurrentProfile() (*Profile, error) {
-	profile, ok := c.Profiles[c.CurrentProfile]
-	if !ok {
-		return nil, fmt.Errorf("profile '%s' not found", c.CurrentProfile)
+	name := c.ActiveProfileName()
+	profile, ok := c.Profiles[name]
+	if !ok || profile == nil {
+		return nil, fmt.Errorf("profile '%s' not found", name)
 	}
 	return profile, nil
 }
@@ -132,6 +245,99 @@ func (c *Config) SetProfile(name string, profile *Profile) {
 	c.Profiles[name] = profile
 }
 
+// UseProfile switches current_profile to an existing profile
+func (c *Config) UseProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	c.CurrentProfile = name
+	return nil
+}
+
+// DeleteProfile removes a profile. The current profile cannot be deleted;
+// switch to another profile first.
+func (c *Config) DeleteProfile(name string) error {
+	if _, ok := c.Profiles[name]; !ok {
+		return fmt.Errorf("profile '%s' not found", name)
+	}
+	if name == c.CurrentProfile {
+		return fmt.Errorf("profile '%s' is the current profile; switch with 'shoehorn config use-profile <name>' first", name)
+	}
+	delete(c.Profiles, name)
+	return nil
+}
+
+// RenameProfile renames a profile, keeping current_profile pointed at it if needed
+func (c *Config) RenameProfile(oldName, newName string) error {
+	profile, ok := c.Profiles[oldName]
+	if !ok {
+		return fmt.Errorf("profile '%s' not found", oldName)
+	}
+	if _, exists := c.Profiles[newName]; exists {
+		return fmt.Errorf("profile '%s' already exists", newName)
+	}
+	delete(c.Profiles, oldName)
+	c.Profiles[newName] = profile
+	if c.CurrentProfile == oldName {
+		c.CurrentProfile = newName
+	}
+	return nil
+}
+
+// ProfileNames returns all profile names in sorted order
+func (c *Config) ProfileNames() []string {
+	names := make([]string, 0, len(c.Profiles))
+	for name := range c.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clone returns a deep copy of the serialized fields of the config
+func (c *Config) clone() *Config {
+	out := &Config{
+		Version:         c.Version,
+		CurrentProfile:  c.CurrentProfile,
+		Profiles:        make(map[string]*Profile, len(c.Profiles)),
+		CredentialStore: c.CredentialStore,
+	}
+	for name, p := range c.Profiles {
+		if p == nil {
+			continue
+		}
+		cp := *p
+		if p.Auth != nil {
+			auth := *p.Auth
+			if p.Auth.User != nil {
+				user := *p.Auth.User
+				auth.User = &user
+			}
+			cp.Auth = &auth
+		}
+		out.Profiles[name] = &cp
+	}
+	return out
+}
+
+// Redacted returns a deep copy of the config with all tokens replaced,
+// safe to print or share.
+func (c *Config) Redacted() *Config {
+	out := c.clone()
+	for _, p := range out.Profiles {
+		if p.Auth == nil {
+			continue
+		}
+		if p.Auth.AccessToken != "" {
+			p.Auth.AccessToken = redactedValue
+		}
+		if p.Auth.RefreshToken != "" {
+			p.Auth.RefreshToken = redactedValue
+		}
+	}
+	return out
+}
+
 // IsAuthenticated checks if the current profile has valid auth
 func (c *Config) IsAuthenticated() bool {
 	profile, err := c.GetCurrentProfile()
@@ -0,0 +1,206 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withOverrides resets the package-level flag overrides after the test.
+func withOverrides(t *testing.T, path, profile string) {
+	t.Helper()
+	SetPathOverride(path)
+	SetProfileOverride(profile)
+	t.Cleanup(func() {
+		SetPathOverride("")
+		SetProfileOverride("")
+	})
+}
+
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+}
+
+const twoProfiles = `version: "1.0"
+current_profile: staging
+profiles:
+  staging:
+    name: Staging
+    server: https://staging.example.com
+  prod:
+    name: Production
+    server: https://prod.example.com
+`
+
+func TestGetConfigPath_Precedence(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv(EnvConfigPath, "")
+	withOverrides(t, "", "")
+
+	got, err := GetConfigPath()
+	if err != nil {
+		t.Fatalf("GetConfigPath() = %v", err)
+	}
+	if want := filepath.Join(home, ".shoehorn", "config.yaml"); got != want {
+		t.Errorf("default path = %q, want %q", got, want)
+	}
+
+	t.Setenv(EnvConfigPath, "/env/config.yaml")
+	if got, _ := GetConfigPath(); got != "/env/config.yaml" {
+		t.Errorf("env path = %q, want /env/config.yaml", got)
+	}
+
+	SetPathOverride("/flag/config.yaml")
+	if got, _ := GetConfigPath(); got != "/flag/config.yaml" {
+		t.Errorf("flag path = %q, want /flag/config.yaml", got)
+	}
+}
+
+func TestLoad_ProfilePrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, twoProfiles)
+	t.Setenv(EnvProfile, "")
+	withOverrides(t, path, "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if got := cfg.ActiveProfileName(); got != "staging" {
+		t.Errorf("file profile = %q, want staging", got)
+	}
+
+	t.Setenv(EnvProfile, "prod")
+	cfg, _ = Load()
+	p, err := cfg.GetCurrentProfile()
+	if err != nil {
+		t.Fatalf("GetCurrentProfile() = %v", err)
+	}
+	if p.Server != "https://prod.example.com" {
+		t.Errorf("env profile server = %q, want prod", p.Server)
+	}
+
+	SetProfileOverride("staging")
+	cfg, _ = Load()
+	if got := cfg.ActiveProfileName(); got != "staging" {
+		t.Errorf("flag profile = %q, want staging", got)
+	}
+}
+
+func TestLoad_UnknownProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, twoProfiles)
+	withOverrides(t, path, "missing")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if _, err := cfg.GetCurrentProfile(); err == nil {
+		t.Error("expected error for unknown profile")
+	}
+}
+
+func TestSave_DoesNotPersistProfileOverride(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "config.yaml")
+	writeConfig(t, path, twoProfiles)
+	withOverrides(t, path, "prod")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	SetProfileOverride("")
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	if reloaded.CurrentProfile != "staging" {
+		t.Errorf("current_profile = %q, want staging (override must not be saved)", reloaded.CurrentProfile)
+	}
+}
+
+func TestProfileManagement(t *testing.T) {
+	cfg := &Config{
+		CurrentProfile: "default",
+		Profiles: map[string]*Profile{
+			"default": {Name: "Default", Server: "http://localhost:8080"},
+			"staging": {Name: "Staging", Server: "https://staging.example.com"},
+		},
+	}
+
+	if err := cfg.UseProfile("missing"); err == nil {
+		t.Error("UseProfile(missing) = nil, want error")
+	}
+	if err := cfg.UseProfile("staging"); err != nil {
+		t.Fatalf("UseProfile(staging) = %v", err)
+	}
+	if cfg.CurrentProfile != "staging" {
+		t.Errorf("current = %q, want staging", cfg.CurrentProfile)
+	}
+
+	if err := cfg.DeleteProfile("staging"); err == nil {
+		t.Error("deleting the current profile should fail")
+	}
+
+	if err := cfg.RenameProfile("staging", "default"); err == nil {
+		t.Error("renaming onto an existing profile should fail")
+	}
+	if err := cfg.RenameProfile("staging", "stage"); err != nil {
+		t.Fatalf("RenameProfile() = %v", err)
+	}
+	if cfg.CurrentProfile != "stage" {
+		t.Errorf("current after rename = %q, want stage", cfg.CurrentProfile)
+	}
+
+	if err := cfg.DeleteProfile("default"); err != nil {
+		t.Fatalf("DeleteProfile(default) = %v", err)
+	}
+	if got := cfg.ProfileNames(); len(got) != 1 || got[0] != "stage" {
+		t.Errorf("ProfileNames() = %v, want [stage]", got)
+	}
+}
+
+func TestRedacted(t *testing.T) {
+	cfg := &Config{
+		CurrentProfile: "default",
+		Profiles: map[string]*Profile{
+			"default": {
+				Server: "http://localhost:8080",
+				Auth: &Auth{
+					ProviderType: "oidc",
+					AccessToken:  "secret-access",
+					RefreshToken: "secret-refresh",
+					User:         &User{Email: "jane@example.com"},
+				},
+			},
+			"anon": {Server: "http://localhost:8080"},
+		},
+	}
+
+	red := cfg.Redacted()
+	auth := red.Profiles["default"].Auth
+	if auth.AccessToken != "REDACTED" || auth.RefreshToken != "REDACTED" {
+		t.Errorf("tokens not redacted: %+v", auth)
+	}
+	if auth.User.Email != "jane@example.com" {
+		t.Errorf("user email = %q, want preserved", auth.User.Email)
+	}
+	if cfg.Profiles["default"].Auth.AccessToken != "secret-access" {
+		t.Error("Redacted() must not modify the original config")
+	}
+	if red.Profiles["anon"].Auth != nil {
+		t.Error("profile without auth should stay without auth")
+	}
+}
@@ -0,0 +1,258 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/shoehorn-dev/cli/pkg/credentials"
+)
+
+// openStore is a package-level alias so tests can substitute an in-memory store
+var openStore = credentials.Open
+
+// credentialState tracks the credential store for a loaded config
+type credentialState struct {
+	store      credentials.Store
+	kind       credentials.Kind
+	storedRefs map[string]bool         // refs referenced by the file when it was loaded
+	inline     map[string]storedSecret // tokens written inline in the file when it was loaded
+
+	// Set by SetCredentialStore: secrets to delete from the previous backend after saving
+	oldStore credentials.Store
+	oldRefs  map[string]bool
+}
+
+// storedSecret is the payload kept in the credential store for a profile
+type storedSecret struct {
+	AccessToken  string `json:"access_token,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// credentialKind returns the configured backend
+func (c *Config) credentialKind() (credentials.Kind, error) {
+	return credentials.ParseKind(c.CredentialStore)
+}
+
+// openCredentialStore opens the configured backend once per config.
+// Returns a nil store for the plaintext backend.
+func (c *Config) openCredentialStore() (credentials.Store, credentials.Kind, error) {
+	if c.creds.kind != "" {
+		return c.creds.store, c.creds.kind, nil
+	}
+	kind, err := c.credentialKind()
+	if err != nil {
+		return nil, "", err
+	}
+	configPath, err := GetConfigPath()
+	if err != nil {
+		return nil, "", err
+	}
+	store, resolved, err := openStore(kind, filepath.Dir(configPath))
+	if err != nil {
+		return nil, "", fmt.Errorf("open credential store: %w", err)
+	}
+	c.creds.store = store
+	c.creds.kind = resolved
+	return store, resolved, nil
+}
+
+// credentialRefs returns the set of refs currently referenced by profiles
+func (c *Config) credentialRefs() map[string]bool {
+	refs := map[string]bool{}
+	for _, p := range c.Profiles {
+		if p != nil && p.Auth != nil && p.Auth.CredentialRef != "" {
+			refs[p.Auth.CredentialRef] = true
+		}
+	}
+	return refs
+}
+
+// inlineTokens returns the tokens profiles hold inline, without a ref
+func (c *Config) inlineTokens() map[string]storedSecret {
+	inline := map[string]storedSecret{}
+	for name, p := range c.Profiles {
+		if p != nil && p.Auth != nil && p.Auth.CredentialRef == "" && (p.Auth.AccessToken != "" || p.Auth.RefreshToken != "") {
+			inline[name] = storedSecret{AccessToken: p.Auth.AccessToken, RefreshToken: p.Auth.RefreshToken}
+		}
+	}
+	return inline
+}
+
+// warnPlaintext tells the user, once per process, that tokens are being
+// kept inline because auto found no secure store
+var warnPlaintext = sync.OnceFunc(func() {
+	fmt.Fprintf(os.Stderr, "Warning: no OS keyring and no %s set; tokens are kept in plaintext in the config file (see \"shoehorn config set-credential-store\")\n", credentials.EnvPassphrase)
+})
+
+// LoadCredentials fills AccessToken and RefreshToken of the named profile from
+// the credential store. It is a no-op for unknown profiles, profiles without a
+// stored secret, or profiles whose tokens are already loaded.
+func (c *Config) LoadCredentials(name string) error {
+	p := c.Profiles[name]
+	if p == nil || p.Auth == nil || p.Auth.CredentialRef == "" || p.Auth.AccessToken != "" {
+		return nil
+	}
+
+	store, _, err := c.openCredentialStore()
+	if err != nil {
+		return err
+	}
+	if store == nil {
+		return nil
+	}
+
+	raw, err := store.Get(p.Auth.CredentialRef)
+	if errors.Is(err, credentials.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("load credentials for profile %q: %w", name, err)
+	}
+
+	var secret storedSecret
+	if err := json.Unmarshal([]byte(raw), &secret); err != nil {
+		return fmt.Errorf("load credentials for profile %q: %w", name, err)
+	}
+	p.Auth.AccessToken = secret.AccessToken
+	p.Auth.RefreshToken = secret.RefreshToken
+	return nil
+}
+
+// storeCredentials moves tokens from the in-memory profiles into the credential
+// store and strips them from out, the copy about to be written to disk.
+// Tokens that were inline when the config was loaded and have not changed
+// are left there: only SetCredentialStore migrates existing credentials.
+func (c *Config) storeCredentials(out *Config) error {
+	configured, err := c.credentialKind()
+	if err != nil {
+		return err
+	}
+	store, kind, err := c.openCredentialStore()
+	if err != nil {
+		return err
+	}
+
+	if store == nil && configured == credentials.KindAuto {
+		// No secure store on this host: keep everything where it is, and
+		// leave auto unresolved so a keyring is used once there is one
+		if len(c.inlineTokens()) > 0 {
+			warnPlaintext()
+		}
+		return nil
+	}
+	c.CredentialStore = string(kind)
+	out.CredentialStore = string(kind)
+
+	if store == nil {
+		// Plaintext: tokens stay inline, refs are meaningless
+		for name, p := range c.Profiles {
+			if p != nil && p.Auth != nil {
+				p.Auth.CredentialRef = ""
+				out.Profiles[name].Auth.CredentialRef = ""
+			}
+		}
+		return nil
+	}
+
+	for name, p := range c.Profiles {
+		if p == nil || p.Auth == nil {
+			continue
+		}
+		if loaded, ok := c.creds.inline[name]; ok && p.Auth.CredentialRef == "" &&
+			loaded == (storedSecret{AccessToken: p.Auth.AccessToken, RefreshToken: p.Auth.RefreshToken}) {
+			continue
+		}
+		if p.Auth.AccessToken != "" || p.Auth.RefreshToken != "" {
+			if p.Auth.CredentialRef == "" {
+				ref, err := newCredentialRef(name)
+				if err != nil {
+					return err
+				}
+				p.Auth.CredentialRef = ref
+			}
+			data, err := json.Marshal(storedSecret{
+				AccessToken:  p.Auth.AccessToken,
+				RefreshToken: p.Auth.RefreshToken,
+			})
+			if err != nil {
+				return fmt.Errorf("marshal credentials: %w", err)
+			}
+			if err := store.Set(p.Auth.CredentialRef, string(data)); err != nil {
+				return fmt.Errorf("store credentials for profile %q: %w", name, err)
+			}
+		}
+		outAuth := out.Profiles[name].Auth
+		outAuth.CredentialRef = p.Auth.CredentialRef
+		outAuth.AccessToken = ""
+		outAuth.RefreshToken = ""
+	}
+
+	// Remove secrets of profiles that were deleted or logged out
+	current := c.credentialRefs()
+	for ref := range c.creds.storedRefs {
+		if !current[ref] {
+			if err := store.Delete(ref); err != nil {
+				return fmt.Errorf("delete stale credentials: %w", err)
+			}
+		}
+	}
+	c.creds.storedRefs = current
+	return nil
+}
+
+// SetCredentialStore switches the backend used for tokens. All profiles'
+// credentials are loaded from the old backend, written to the new one on the
+// next Save, and then removed from the old one.
+func (c *Config) SetCredentialStore(kind credentials.Kind) error {
+	for name := range c.Profiles {
+		if err := c.LoadCredentials(name); err != nil {
+			return err
+		}
+	}
+
+	oldStore, _, err := c.openCredentialStore()
+	if err != nil {
+		return err
+	}
+	c.creds = credentialState{
+		oldStore: oldStore,
+		oldRefs:  c.credentialRefs(),
+	}
+	c.CredentialStore = string(kind)
+	for _, p := range c.Profiles {
+		if p != nil && p.Auth != nil {
+			p.Auth.CredentialRef = ""
+		}
+	}
+	return nil
+}
+
+// purgeOldCredentials deletes secrets from the backend replaced by SetCredentialStore
+func (c *Config) purgeOldCredentials() error {
+	if c.creds.oldStore == nil {
+		return nil
+	}
+	for ref := range c.creds.oldRefs {
+		if err := c.creds.oldStore.Delete(ref); err != nil {
+			return fmt.Errorf("remove credentials from previous store: %w", err)
+		}
+	}
+	c.creds.oldStore = nil
+	c.creds.oldRefs = nil
+	return nil
+}
+
+// newCredentialRef returns a unique, human-recognizable reference for a profile's secret
+func newCredentialRef(profile string) (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate credential ref: %w", err)
+	}
+	return profile + "-" + hex.EncodeToString(b), nil
+}
@@ -0,0 +1,222 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/credentials"
+)
+
+// memoryStore is an in-memory credentials.Store for tests
+type memoryStore map[string]string
+
+func (m memoryStore) Get(ref string) (string, error) {
+	v, ok := m[ref]
+	if !ok {
+		return "", credentials.ErrNotFound
+	}
+	return v, nil
+}
+
+func (m memoryStore) Set(ref, secret string) error { m[ref] = secret; return nil }
+
+func (m memoryStore) Delete(ref string) error { delete(m, ref); return nil }
+
+// useMemoryStore routes keyring/auto backends to a shared in-memory store
+func useMemoryStore(t *testing.T) memoryStore {
+	t.Helper()
+	mem := memoryStore{}
+	prev := openStore
+	openStore = func(kind credentials.Kind, dir string) (credentials.Store, credentials.Kind, error) {
+		if kind == credentials.KindPlaintext {
+			return nil, credentials.KindPlaintext, nil
+		}
+		return mem, credentials.KindKeyring, nil
+	}
+	t.Cleanup(func() { openStore = prev })
+	return mem
+}
+
+func TestMain(m *testing.M) {
+	// Never touch the real OS keyring from tests
+	openStore = func(kind credentials.Kind, dir string) (credentials.Store, credentials.Kind, error) {
+		if kind == credentials.KindPlaintext {
+			return nil, credentials.KindPlaintext, nil
+		}
+		return memoryStore{}, credentials.KindKeyring, nil
+	}
+	os.Exit(m.Run())
+}
+
+const legacyConfig = `version: "1.0"
+current_profile: default
+profiles:
+  default:
+    name: Default
+    server: http://localhost:8080
+    auth:
+      provider_type: pat
+      access_token: shp_legacy_plaintext
+`
+
+func TestSave_LeavesInlineTokens(t *testing.T) {
+	mem := useMemoryStore(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, legacyConfig)
+	withOverrides(t, path, "")
+
+	// An unrelated change does not migrate credentials
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	cfg.SetProfile("other", &Profile{Server: "https://other"})
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "access_token: shp_legacy_plaintext") || len(mem) != 0 {
+		t.Fatalf("token moved by an unrelated save (store has %d secrets):\n%s", len(mem), data)
+	}
+
+	// Choosing a store does
+	cfg, _ = Load()
+	if err := cfg.SetCredentialStore(credentials.KindAuto); err != nil {
+		t.Fatalf("SetCredentialStore() = %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "shp_legacy_plaintext") {
+		t.Fatalf("token still in config file:\n%s", data)
+	}
+	if !strings.Contains(string(data), "credential_store: keyring") {
+		t.Errorf("resolved backend not recorded:\n%s", data)
+	}
+	if len(mem) != 1 {
+		t.Fatalf("store has %d secrets, want 1", len(mem))
+	}
+
+	reloaded, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	p, _ := reloaded.GetCurrentProfile()
+	if p.Auth.AccessToken != "shp_legacy_plaintext" {
+		t.Errorf("AccessToken = %q, want token loaded from store", p.Auth.AccessToken)
+	}
+}
+
+func TestSave_AutoWithoutSecureStore(t *testing.T) {
+	// A headless host: no keyring, no passphrase, no terminal
+	prev := openStore
+	openStore = func(kind credentials.Kind, dir string) (credentials.Store, credentials.Kind, error) {
+		return nil, credentials.KindPlaintext, nil
+	}
+	t.Cleanup(func() { openStore = prev })
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, legacyConfig)
+	withOverrides(t, path, "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Load() = %v", err)
+	}
+	cfg.SetProfile("other", &Profile{Server: "https://other"})
+	p, _ := cfg.GetCurrentProfile()
+	p.Auth.AccessToken = "shp_rotated"
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "access_token: shp_rotated") {
+		t.Errorf("token not kept inline:\n%s", data)
+	}
+	if strings.Contains(string(data), "credential_store") {
+		t.Errorf("auto pinned to a backend:\n%s", data)
+	}
+}
+
+func TestSave_DeletesSecretOnLogout(t *testing.T) {
+	mem := useMemoryStore(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	withOverrides(t, path, "")
+
+	cfg, _ := Load()
+	p, _ := cfg.GetCurrentProfile()
+	p.Auth = &Auth{ProviderType: "pat", AccessToken: "shp_x"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	cfg, _ = Load()
+	p, _ = cfg.GetCurrentProfile()
+	p.Auth = nil
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	if len(mem) != 0 {
+		t.Errorf("store still has %d secrets after logout", len(mem))
+	}
+}
+
+func TestSave_InactiveProfileKeepsSecret(t *testing.T) {
+	mem := useMemoryStore(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	withOverrides(t, path, "")
+
+	cfg, _ := Load()
+	cfg.SetProfile("prod", &Profile{Server: "https://prod", Auth: &Auth{AccessToken: "shp_prod"}})
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	// prod is not active, so its token is not loaded; saving must not drop it
+	cfg, _ = Load()
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+	if len(mem) != 1 {
+		t.Fatalf("store has %d secrets, want 1", len(mem))
+	}
+	if err := cfg.LoadCredentials("prod"); err != nil {
+		t.Fatalf("LoadCredentials() = %v", err)
+	}
+	if got := cfg.Profiles["prod"].Auth.AccessToken; got != "shp_prod" {
+		t.Errorf("prod token = %q, want shp_prod", got)
+	}
+}
+
+func TestSetCredentialStore_MigratesToPlaintext(t *testing.T) {
+	mem := useMemoryStore(t)
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	withOverrides(t, path, "")
+
+	cfg, _ := Load()
+	p, _ := cfg.GetCurrentProfile()
+	p.Auth = &Auth{AccessToken: "shp_x", RefreshToken: "rt"}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	cfg, _ = Load()
+	if err := cfg.SetCredentialStore(credentials.KindPlaintext); err != nil {
+		t.Fatalf("SetCredentialStore() = %v", err)
+	}
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("Save() = %v", err)
+	}
+
+	if len(mem) != 0 {
+		t.Errorf("old store still has %d secrets", len(mem))
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "access_token: shp_x") || strings.Contains(string(data), "credential_ref") {
+		t.Errorf("expected inline tokens without ref:\n%s", data)
+	}
+}
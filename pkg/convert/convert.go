@@ -0,0 +1,383 @@
+// Package convert maps Backstage catalog entities to Shoehorn manifests
+// locally, without the server's /manifests/convert endpoint. Backstage fields
+// that have no Shoehorn equivalent are reported as warnings rather than
+// silently dropped.
+package convert
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+	"github.com/shoehorn-dev/cli/pkg/schema"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrNotBackstage is returned for documents without a backstage.io apiVersion
+var ErrNotBackstage = errors.New("not a Backstage manifest")
+
+// ErrUnsupportedKind is returned for Backstage kinds not in Kinds
+var ErrUnsupportedKind = errors.New("unsupported kind")
+
+// Kinds lists the Backstage kinds the local converter maps
+var Kinds = []string{"Component", "API", "System", "Resource", "Group"}
+
+// Warning is a Backstage field that did not make it into the manifest, or
+// was changed to fit it
+type Warning struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (w Warning) String() string {
+	return w.Field + ": " + w.Message
+}
+
+// Result is a converted manifest and what was lost on the way
+type Result struct {
+	Manifest *manifest.Manifest
+	Warnings []Warning
+
+	source  *yaml.Node // the Backstage document, for its comments
+	origins []origin
+}
+
+// origin records which Backstage field a manifest field came from
+type origin struct {
+	dest, src string
+}
+
+const dropped = "has no Shoehorn equivalent; dropped"
+
+// FromBackstage converts a Backstage Component, API, System, Resource or
+// Group entity to a Shoehorn manifest
+func FromBackstage(data []byte) (*Result, error) {
+	var source yaml.Node
+	if err := yaml.Unmarshal(data, &source); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	var doc any
+	if err := source.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	root, ok := doc.(map[string]any)
+	if !ok || schema.Detect(doc) != schema.KindBackstage {
+		return nil, ErrNotBackstage
+	}
+
+	c := &converter{doc: root, used: map[string]bool{}}
+	kind := c.str("kind")
+	if !slices.Contains(Kinds, kind) {
+		return nil, fmt.Errorf("%w %q (supported: %s)", ErrUnsupportedKind, kind, strings.Join(Kinds, ", "))
+	}
+	c.str("apiVersion")
+
+	m := &manifest.Manifest{SchemaVersion: manifest.SchemaVersion}
+	c.metadata(m)
+
+	switch kind {
+	case "Component":
+		m.Service.Type = c.str("spec.type")
+		m.Lifecycle = c.str("spec.lifecycle")
+		c.owner(m)
+	case "API":
+		m.Service.Type = "api"
+		c.from("service.type", "kind")
+		m.Lifecycle = c.str("spec.lifecycle")
+		c.owner(m)
+		if c.lookup("spec.definition") != nil {
+			c.used["spec.definition"] = true
+			c.warn("spec.definition", "the API definition is not part of a Shoehorn manifest; dropped")
+		}
+	case "Resource":
+		m.Service.Type = c.str("spec.type")
+		c.owner(m)
+	case "System":
+		m.Service.Type = "system"
+		c.from("service.type", "kind")
+		c.owner(m)
+	case "Group":
+		m.Service.Type = c.str("spec.type")
+		if name := c.str("spec.profile.displayName"); name != "" && c.lookup("metadata.title") == nil {
+			m.Service.Name = name
+			c.from("service.name", "spec.profile.displayName")
+		}
+	}
+	c.from("service", "metadata")
+	c.from("service.type", "spec.type")
+	c.from("lifecycle", "spec.lifecycle")
+	c.from("owner", "spec.owner")
+
+	c.unmapped(root, "")
+	return &Result{Manifest: m, Warnings: c.warnings, source: &source, origins: c.origins}, nil
+}
+
+type converter struct {
+	doc      map[string]any
+	used     map[string]bool
+	warnings []Warning
+	origins  []origin
+}
+
+// from records that the manifest field dest came from the Backstage field
+// src, so that src's comments follow it
+func (c *converter) from(dest, src string) {
+	c.origins = append(c.origins, origin{dest, src})
+}
+
+func (c *converter) warn(field, format string, args ...any) {
+	c.warnings = append(c.warnings, Warning{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// lookup returns the value at a dotted path of plain keys, or nil
+func (c *converter) lookup(path string) any {
+	var v any = c.doc
+	for _, key := range strings.Split(path, ".") {
+		m, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = m[key]
+	}
+	return v
+}
+
+// str returns the string at path and marks it as mapped. Numbers and
+// booleans are formatted; other types are left for unmapped to report.
+func (c *converter) str(path string) string {
+	switch v := c.lookup(path).(type) {
+	case string:
+		c.used[path] = true
+		return v
+	case int, float64, bool:
+		c.used[path] = true
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func (c *converter) metadata(m *manifest.Manifest) {
+	name := c.str("metadata.name")
+	m.Service.ID = serviceID(name)
+	c.from("service.id", "metadata.name")
+	if m.Service.ID != name {
+		c.warn("metadata.name", "%q became service id %q", name, m.Service.ID)
+	}
+	m.Service.Name = c.str("metadata.title")
+	c.from("service.name", "metadata.title")
+	if m.Service.Name == "" {
+		m.Service.Name = name
+	}
+	m.Description = c.str("metadata.description")
+	c.from("description", "metadata.description")
+	c.from("tags", "metadata.tags")
+	c.from("links", "metadata.links")
+
+	if ns := c.str("metadata.namespace"); ns != "" && ns != "default" {
+		c.warn("metadata.namespace", "namespace %q %s", ns, dropped)
+	}
+
+	if tags, ok := c.lookup("metadata.tags").([]any); ok {
+		c.used["metadata.tags"] = true
+		for i, t := range tags {
+			s, ok := t.(string)
+			if !ok || s == "" {
+				c.warn(schema.IndexPath("metadata.tags", i), "is not a tag; dropped")
+				continue
+			}
+			if !slices.Contains(m.Tags, s) {
+				m.Tags = append(m.Tags, s)
+			}
+		}
+	}
+
+	links, _ := c.lookup("metadata.links").([]any)
+	for i, l := range links {
+		path := schema.IndexPath("metadata.links", i)
+		link, ok := l.(map[string]any)
+		if !ok {
+			continue
+		}
+		url, _ := link["url"].(string)
+		if url == "" {
+			continue
+		}
+		title, _ := link["title"].(string)
+		icon, _ := link["icon"].(string)
+		c.used[path+".url"] = true
+		c.used[path+".title"] = true
+		c.used[path+".icon"] = true
+		if title == "" {
+			title = url
+		}
+		m.Links = append(m.Links, manifest.Link{Name: title, URL: url, Icon: icon})
+	}
+}
+
+// Marshal encodes the manifest as YAML with two-space indentation, carrying
+// over the comments of the Backstage fields each manifest field came from and
+// those at the top and bottom of the document
+func (r *Result) Marshal() ([]byte, error) {
+	var root yaml.Node
+	if err := root.Encode(r.Manifest); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	doc := &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{&root}}
+	if r.source != nil && len(r.source.Content) > 0 {
+		// The document's own comments are those set apart by a blank line
+		doc.HeadComment = r.source.HeadComment
+		doc.FootComment = r.source.FootComment
+
+		src := r.source.Content[0]
+		head := []string{src.HeadComment}
+		// A comment above the first key is the file's header
+		for _, k := range []string{"apiVersion", "kind"} {
+			if key, _ := mappingEntry(src, k); key != nil {
+				head = append(head, key.HeadComment)
+			}
+		}
+		root.HeadComment = joinComments(head...)
+		root.FootComment = src.FootComment
+
+		for _, o := range r.origins {
+			copyComments(&root, src, o.dest, o.src)
+		}
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// copyComments moves the comments of the entry at srcPath to the entry at
+// destPath. A line comment on a scalar that became a list or mapping moves to
+// the key.
+func copyComments(dest, src *yaml.Node, destPath, srcPath string) {
+	dk, dv := mappingEntry(dest, destPath)
+	sk, sv := mappingEntry(src, srcPath)
+	if dk == nil || sk == nil {
+		return
+	}
+	dk.HeadComment = joinComments(dk.HeadComment, sk.HeadComment)
+	dk.FootComment = joinComments(dk.FootComment, sk.FootComment)
+	dk.LineComment = joinComments(dk.LineComment, sk.LineComment)
+	if dv.Kind == yaml.ScalarNode || sv.Kind != yaml.ScalarNode {
+		dv.LineComment = joinComments(dv.LineComment, sv.LineComment)
+	} else {
+		dk.LineComment = joinComments(dk.LineComment, sv.LineComment)
+	}
+	if dv.Kind == sv.Kind && dv.Kind != yaml.ScalarNode {
+		dv.HeadComment = joinComments(dv.HeadComment, sv.HeadComment)
+		dv.FootComment = joinComments(dv.FootComment, sv.FootComment)
+		for i := range min(len(dv.Content), len(sv.Content)) {
+			if dv.Kind == yaml.SequenceNode {
+				dv.Content[i].HeadComment = joinComments(dv.Content[i].HeadComment, sv.Content[i].HeadComment)
+				dv.Content[i].LineComment = joinComments(dv.Content[i].LineComment, sv.Content[i].LineComment)
+			}
+		}
+	}
+}
+
+// mappingEntry returns the key and value nodes at a dotted path of plain keys
+func mappingEntry(node *yaml.Node, path string) (key, value *yaml.Node) {
+	for _, k := range strings.Split(path, ".") {
+		if node == nil || node.Kind != yaml.MappingNode {
+			return nil, nil
+		}
+		key, value = nil, nil
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == k {
+				key, value = node.Content[i], node.Content[i+1]
+				break
+			}
+		}
+		node = value
+	}
+	return key, value
+}
+
+func joinComments(comments ...string) string {
+	var parts []string
+	for _, c := range comments {
+		if c != "" {
+			parts = append(parts, c)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// owner maps spec.owner, an entity reference such as "group:default/payments"
+// or "user:jane", to an owner. Backstage groups are Shoehorn teams.
+func (c *converter) owner(m *manifest.Manifest) {
+	ref := c.str("spec.owner")
+	if ref == "" {
+		return
+	}
+	kind, name, found := strings.Cut(ref, ":")
+	if !found {
+		kind, name = "group", ref
+	}
+	if ns, n, ok := strings.Cut(name, "/"); ok {
+		if ns != "default" {
+			c.warn("spec.owner", "namespace %q %s", ns, dropped)
+		}
+		name = n
+	}
+
+	switch strings.ToLower(kind) {
+	case "group":
+		m.Owner = []manifest.OwnerRef{{Type: "team", ID: name}}
+	case "user":
+		m.Owner = []manifest.OwnerRef{{Type: "user", ID: name}}
+	default:
+		c.warn("spec.owner", "owner kind %q is not a team or user; mapped to team %q", kind, name)
+		m.Owner = []manifest.OwnerRef{{Type: "team", ID: name}}
+	}
+}
+
+// unmapped warns about every field below path that was not mapped
+func (c *converter) unmapped(v any, path string) {
+	if path != "" && c.used[path] {
+		return
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			c.unmapped(v[k], schema.FieldPath(path, k))
+		}
+	case []any:
+		if slices.ContainsFunc(v, func(e any) bool { _, ok := e.(map[string]any); return ok }) {
+			for i, e := range v {
+				c.unmapped(e, schema.IndexPath(path, i))
+			}
+			return
+		}
+		if len(v) > 0 {
+			c.warn(path, dropped)
+		}
+	case nil:
+	default:
+		c.warn(path, dropped)
+	}
+}
+
+// serviceID turns a Backstage name into a valid Shoehorn service id:
+// lowercase, with underscores as dashes
+func serviceID(name string) string {
+	return strings.ReplaceAll(strings.ToLower(name), "_", "-")
+}
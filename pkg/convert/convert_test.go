@@ -0,0 +1,101 @@
+package convert
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/schema"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// golden renders a result the way the golden files store it: the manifest,
+// then one comment line per warning
+func golden(t *testing.T, r *Result) string {
+	t.Helper()
+	out, err := r.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	b.Write(out)
+	for _, w := range r.Warnings {
+		b.WriteString("# warning: " + w.String() + "\n")
+	}
+	return b.String()
+}
+
+func TestFromBackstage_Golden(t *testing.T) {
+	inputs, err := filepath.Glob("testdata/*.yaml")
+	if err != nil || len(inputs) == 0 {
+		t.Fatalf("no testdata inputs (%v)", err)
+	}
+	for _, in := range inputs {
+		t.Run(strings.TrimSuffix(filepath.Base(in), ".yaml"), func(t *testing.T) {
+			data, err := os.ReadFile(in)
+			if err != nil {
+				t.Fatal(err)
+			}
+			r, err := FromBackstage(data)
+			if err != nil {
+				t.Fatalf("FromBackstage() = %v", err)
+			}
+			got := golden(t, r)
+
+			// Every conversion must be a valid manifest
+			out, _ := r.Marshal()
+			if res, err := schema.ValidateManifest(string(out), ""); err != nil || !res.Valid {
+				t.Errorf("converted manifest is invalid: %v %+v", err, res)
+			}
+
+			path := strings.TrimSuffix(in, ".yaml") + ".golden"
+			if *update {
+				if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("%v (run go test -update to create it)", err)
+			}
+			if got != string(want) {
+				t.Errorf("FromBackstage(%s) =\n%s\nwant\n%s", in, got, want)
+			}
+		})
+	}
+}
+
+func TestFromBackstage_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"shoehorn manifest", "schemaVersion: 1\nservice: {id: orders}\n", ErrNotBackstage.Error()},
+		{"not a mapping", "- a\n- b\n", ErrNotBackstage.Error()},
+		{"template", "apiVersion: scaffolder.backstage.io/v1beta3\nkind: Template\nmetadata: {name: t}\n", `unsupported kind "Template"`},
+		{"invalid yaml", "kind: [", "parse manifest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := FromBackstage([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("FromBackstage() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	_, err := FromBackstage([]byte("schemaVersion: 1\n"))
+	if !errors.Is(err, ErrNotBackstage) {
+		t.Errorf("FromBackstage(shoehorn) = %v, want ErrNotBackstage", err)
+	}
+	_, err = FromBackstage([]byte("apiVersion: backstage.io/v1alpha1\nkind: Location\n"))
+	if !errors.Is(err, ErrUnsupportedKind) {
+		t.Errorf("FromBackstage(Location) = %v, want ErrUnsupportedKind", err)
+	}
+}
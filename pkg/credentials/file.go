@@ -0,0 +1,234 @@
+package credentials
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"filippo.io/age"
+	"golang.org/x/term"
+)
+
+// EnvPassphrase supplies the file backend passphrase non-interactively (CI, scripts)
+const EnvPassphrase = "SHOEHORN_CREDENTIALS_PASSPHRASE"
+
+// defaultWorkFactor is the scrypt cost (log2 N) used when encrypting.
+// It is lower than age's default of 18 because the file is decrypted on every
+// CLI invocation; 15 keeps that well under 100ms.
+const defaultWorkFactor = 15
+
+// PassphraseFunc returns the passphrase for the encrypted file.
+// confirm is true when the file is being created for the first time.
+type PassphraseFunc func(confirm bool) (string, error)
+
+// FileStore keeps all secrets in a single age/scrypt-encrypted JSON file
+type FileStore struct {
+	path       string
+	passphrase PassphraseFunc
+	workFactor int
+
+	mu      sync.Mutex
+	pass    string
+	secrets map[string]string
+}
+
+// NewFileStore creates a store backed by the encrypted file at path
+func NewFileStore(path string, passphrase PassphraseFunc) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase, workFactor: defaultWorkFactor}
+}
+
+// SetWorkFactor overrides the scrypt cost used when writing the file
+func (s *FileStore) SetWorkFactor(logN int) {
+	s.workFactor = logN
+}
+
+// Get returns the secret for ref
+func (s *FileStore) Get(ref string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return "", err
+	}
+	secret, ok := s.secrets[ref]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+// Set stores the secret for ref and rewrites the file
+func (s *FileStore) Set(ref, secret string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	s.secrets[ref] = secret
+	return s.write()
+}
+
+// Delete removes the secret for ref and rewrites the file
+func (s *FileStore) Delete(ref string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.load(); err != nil {
+		return err
+	}
+	if _, ok := s.secrets[ref]; !ok {
+		return nil
+	}
+	delete(s.secrets, ref)
+	return s.write()
+}
+
+// load decrypts the file once per process. A missing file is an empty store.
+func (s *FileStore) load() error {
+	if s.secrets != nil {
+		return nil
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		s.secrets = map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("read credentials file: %w", err)
+	}
+
+	pass, err := s.getPassphrase(false)
+	if err != nil {
+		return err
+	}
+	identity, err := age.NewScryptIdentity(pass)
+	if err != nil {
+		return fmt.Errorf("credentials passphrase: %w", err)
+	}
+	r, err := age.Decrypt(bytes.NewReader(data), identity)
+	if err != nil {
+		return fmt.Errorf("decrypt credentials file (wrong passphrase?): %w", err)
+	}
+	plain, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("decrypt credentials file: %w", err)
+	}
+
+	secrets := map[string]string{}
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return fmt.Errorf("parse credentials file: %w", err)
+	}
+	s.secrets = secrets
+	return nil
+}
+
+// write encrypts the current secrets and atomically replaces the file
+func (s *FileStore) write() error {
+	creating := false
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		creating = true
+	}
+	pass, err := s.getPassphrase(creating)
+	if err != nil {
+		return err
+	}
+	recipient, err := age.NewScryptRecipient(pass)
+	if err != nil {
+		return fmt.Errorf("credentials passphrase: %w", err)
+	}
+	recipient.SetWorkFactor(s.workFactor)
+
+	plain, err := json.Marshal(s.secrets)
+	if err != nil {
+		return fmt.Errorf("marshal credentials: %w", err)
+	}
+
+	var buf bytes.Buffer
+	w, err := age.Encrypt(&buf, recipient)
+	if err != nil {
+		return fmt.Errorf("encrypt credentials: %w", err)
+	}
+	if _, err := w.Write(plain); err != nil {
+		return fmt.Errorf("encrypt credentials: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("encrypt credentials: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0700); err != nil {
+		return fmt.Errorf("create credentials directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".credentials-*.tmp")
+	if err != nil {
+		return fmt.Errorf("write credentials file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write credentials file: %w", err)
+	}
+	if err := tmp.Chmod(0600); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write credentials file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write credentials file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("write credentials file: %w", err)
+	}
+	return nil
+}
+
+// getPassphrase asks for the passphrase at most once per process
+func (s *FileStore) getPassphrase(confirm bool) (string, error) {
+	if s.pass != "" {
+		return s.pass, nil
+	}
+	pass, err := s.passphrase(confirm)
+	if err != nil {
+		return "", err
+	}
+	if pass == "" {
+		return "", fmt.Errorf("credentials passphrase must not be empty")
+	}
+	s.pass = pass
+	return pass, nil
+}
+
+// PromptPassphrase reads the passphrase from $SHOEHORN_CREDENTIALS_PASSPHRASE,
+// or prompts on the terminal. It fails when neither is available.
+func PromptPassphrase(confirm bool) (string, error) {
+	if p := os.Getenv(EnvPassphrase); p != "" {
+		return p, nil
+	}
+
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return "", fmt.Errorf("credentials file is encrypted: set %s or run in a terminal", EnvPassphrase)
+	}
+
+	fmt.Fprint(os.Stderr, "Credentials passphrase: ")
+	pass, err := term.ReadPassword(fd)
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("read passphrase: %w", err)
+	}
+
+	if confirm {
+		fmt.Fprint(os.Stderr, "Confirm passphrase: ")
+		again, err := term.ReadPassword(fd)
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("read passphrase: %w", err)
+		}
+		if string(again) != string(pass) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return string(pass), nil
+}
@@ -0,0 +1,95 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func staticPassphrase(p string) PassphraseFunc {
+	return func(bool) (string, error) { return p, nil }
+}
+
+func newTestFileStore(path, pass string) *FileStore {
+	s := NewFileStore(path, staticPassphrase(pass))
+	s.SetWorkFactor(10) // keep tests fast
+	return s
+}
+
+func TestFileStore_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+
+	s := newTestFileStore(path, "correct horse")
+	if _, err := s.Get("missing"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get(missing) = %v, want ErrNotFound", err)
+	}
+	if err := s.Set("default-abc", `{"access_token":"shp_secret"}`); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read file: %v", err)
+	}
+	if strings.Contains(string(data), "shp_secret") {
+		t.Fatal("secret written to disk in plaintext")
+	}
+	info, _ := os.Stat(path)
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("file mode = %o, want 600", perm)
+	}
+
+	// A fresh store must decrypt what the first one wrote
+	reopened := newTestFileStore(path, "correct horse")
+	got, err := reopened.Get("default-abc")
+	if err != nil {
+		t.Fatalf("Get() = %v", err)
+	}
+	if got != `{"access_token":"shp_secret"}` {
+		t.Errorf("Get() = %q", got)
+	}
+
+	if err := reopened.Delete("default-abc"); err != nil {
+		t.Fatalf("Delete() = %v", err)
+	}
+	if err := reopened.Delete("default-abc"); err != nil {
+		t.Fatalf("Delete() of missing ref = %v, want nil", err)
+	}
+	if _, err := newTestFileStore(path, "correct horse").Get("default-abc"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after delete = %v, want ErrNotFound", err)
+	}
+}
+
+func TestFileStore_WrongPassphrase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName)
+	if err := newTestFileStore(path, "right").Set("ref", "secret"); err != nil {
+		t.Fatalf("Set() = %v", err)
+	}
+	if _, err := newTestFileStore(path, "wrong").Get("ref"); err == nil {
+		t.Fatal("expected decryption error with wrong passphrase")
+	}
+}
+
+func TestPromptPassphrase_FromEnv(t *testing.T) {
+	t.Setenv(EnvPassphrase, "from-env")
+	got, err := PromptPassphrase(true)
+	if err != nil {
+		t.Fatalf("PromptPassphrase() = %v", err)
+	}
+	if got != "from-env" {
+		t.Errorf("PromptPassphrase() = %q, want from-env", got)
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	for _, s := range []string{"", "auto", "keyring", "file", "plaintext"} {
+		if _, err := ParseKind(s); err != nil {
+			t.Errorf("ParseKind(%q) = %v", s, err)
+		}
+	}
+	if _, err := ParseKind("vault"); err == nil {
+		t.Error("ParseKind(vault) = nil, want error")
+	}
+}
@@ -0,0 +1,60 @@
+package credentials
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/zalando/go-keyring"
+)
+
+// keyringService is the service name secrets are filed under in the OS keyring
+const keyringService = "shoehorn-cli"
+
+// keyringProbeRef is looked up to check the keyring is reachable
+const keyringProbeRef = "__shoehorn_probe__"
+
+// KeyringStore stores secrets in the OS keyring
+type KeyringStore struct {
+	service string
+}
+
+// NewKeyringStore creates a keyring-backed store
+func NewKeyringStore() *KeyringStore {
+	return &KeyringStore{service: keyringService}
+}
+
+// KeyringAvailable reports whether the OS keyring can be reached.
+// Headless Linux hosts without a Secret Service daemon return false.
+func KeyringAvailable() bool {
+	_, err := keyring.Get(keyringService, keyringProbeRef)
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+// Get returns the secret for ref
+func (s *KeyringStore) Get(ref string) (string, error) {
+	secret, err := keyring.Get(s.service, ref)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("keyring get: %w", err)
+	}
+	return secret, nil
+}
+
+// Set stores the secret for ref
+func (s *KeyringStore) Set(ref, secret string) error {
+	if err := keyring.Set(s.service, ref, secret); err != nil {
+		return fmt.Errorf("keyring set: %w", err)
+	}
+	return nil
+}
+
+// Delete removes the secret for ref
+func (s *KeyringStore) Delete(ref string) error {
+	err := keyring.Delete(s.service, ref)
+	if err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("keyring delete: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+// Package credentials stores profile secrets (access and refresh tokens) outside
+// the plaintext config file, in the OS keyring or an encrypted file.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Kind identifies a credential store backend
+type Kind string
+
+const (
+	KindAuto      Kind = "auto"      // Keyring when available, otherwise encrypted file when it can be unlocked, otherwise inline
+	KindKeyring   Kind = "keyring"   // OS keyring (Secret Service, macOS Keychain, Windows Credential Manager)
+	KindFile      Kind = "file"      // age/passphrase-encrypted file next to the config
+	KindPlaintext Kind = "plaintext" // Tokens stored inline in config.yaml (explicit opt-in)
+)
+
+// FileName is the encrypted credentials file created by the file backend
+const FileName = "credentials.age"
+
+// ErrNotFound is returned by Get when no secret exists for the reference
+var ErrNotFound = errors.New("credential not found")
+
+// Store persists secrets under an opaque reference stored in the profile
+type Store interface {
+	// Get returns the secret for ref, or ErrNotFound
+	Get(ref string) (string, error)
+	// Set creates or replaces the secret for ref
+	Set(ref, secret string) error
+	// Delete removes the secret for ref. Deleting a missing ref is not an error.
+	Delete(ref string) error
+}
+
+// ParseKind validates a backend name
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(s); k {
+	case KindAuto, KindKeyring, KindFile, KindPlaintext:
+		return k, nil
+	case "":
+		return KindAuto, nil
+	default:
+		return "", fmt.Errorf("unknown credential store %q (expected auto, keyring, file, or plaintext)", s)
+	}
+}
+
+// Open returns the store for kind, resolving KindAuto to a concrete backend.
+// dir is the config directory, used by the file backend.
+// KindPlaintext has no store: the caller keeps tokens inline.
+//
+// KindAuto picks the keyring when it is reachable, and otherwise the encrypted
+// file if it already exists or its passphrase is set in the environment. With
+// neither (a headless host with no Secret Service, say) it resolves to
+// KindPlaintext rather than a file that would need a passphrase prompt on
+// every run, or fail outright without a terminal.
+func Open(kind Kind, dir string) (Store, Kind, error) {
+	switch kind {
+	case KindAuto, "":
+		return openAuto(dir, KeyringAvailable, os.Getenv)
+	case KindKeyring:
+		return NewKeyringStore(), KindKeyring, nil
+	case KindFile:
+		return NewFileStore(filepath.Join(dir, FileName), PromptPassphrase), KindFile, nil
+	case KindPlaintext:
+		return nil, KindPlaintext, nil
+	default:
+		return nil, "", fmt.Errorf("unknown credential store %q", kind)
+	}
+}
+
+// openAuto resolves KindAuto; keyring and getenv are parameters for tests
+func openAuto(dir string, keyring func() bool, getenv func(string) string) (Store, Kind, error) {
+	if keyring() {
+		return NewKeyringStore(), KindKeyring, nil
+	}
+	path := filepath.Join(dir, FileName)
+	if _, err := os.Stat(path); err == nil || getenv(EnvPassphrase) != "" {
+		return NewFileStore(path, PromptPassphrase), KindFile, nil
+	}
+	return nil, KindPlaintext, nil
+}
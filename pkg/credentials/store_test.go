@@ -0,0 +1,44 @@
+package credentials
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenAuto(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	withFile := t.TempDir()
+	if err := os.WriteFile(filepath.Join(withFile, FileName), []byte("x"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		keyring bool
+		dir     string
+		env     map[string]string
+		want    Kind
+	}{
+		{"keyring", true, t.TempDir(), nil, KindKeyring},
+		{"passphrase in environment", false, t.TempDir(), map[string]string{EnvPassphrase: "pw"}, KindFile},
+		{"existing file", false, withFile, nil, KindFile},
+		{"no keyring, no passphrase, no file", false, t.TempDir(), nil, KindPlaintext},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store, kind, err := openAuto(tt.dir, func() bool { return tt.keyring }, env(tt.env))
+			if err != nil {
+				t.Fatalf("openAuto() = %v", err)
+			}
+			if kind != tt.want {
+				t.Errorf("kind = %q, want %q", kind, tt.want)
+			}
+			if (store == nil) != (tt.want == KindPlaintext) {
+				t.Errorf("store = %v for kind %q", store, kind)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+// Package inputs reads Forge run inputs from YAML or JSON files and expands
+// ${VAR} environment references in them.
+package inputs
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decode parses an inputs file, YAML or JSON, into input values by name.
+// Dates and times are kept as the strings they were written as.
+func Decode(data []byte) (map[string]any, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML or JSON: %s", strings.TrimPrefix(err.Error(), "yaml: "))
+	}
+	if doc.Kind == 0 {
+		return map[string]any{}, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, errors.New("must be a mapping of input names to values")
+	}
+	keepTimestamps(root)
+
+	values := map[string]any{}
+	if err := root.Decode(&values); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// keepTimestamps retags timestamp scalars as strings, so that a date such as
+// 2026-03-01 is not turned into a time
+func keepTimestamps(n *yaml.Node) {
+	if n.Kind == yaml.ScalarNode && n.ShortTag() == "!!timestamp" {
+		n.Tag = "!!str"
+	}
+	for _, c := range n.Content {
+		keepTimestamps(c)
+	}
+}
+
+// Interpolate expands ${VAR} and ${VAR:-default} in every string of v, a
+// decoded inputs value, looking variables up with lookup. "$${" stands for a
+// literal "${". A variable that is not set and has no default is an error
+// naming the input it appears in.
+func Interpolate(v any, lookup func(string) (string, bool)) (any, error) {
+	return interpolate(v, "", lookup)
+}
+
+func interpolate(v any, path string, lookup func(string) (string, bool)) (any, error) {
+	switch v := v.(type) {
+	case string:
+		s, err := Expand(v, lookup)
+		if err != nil {
+			if path == "" {
+				return nil, err
+			}
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		return s, nil
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			x, err := interpolate(e, joinPath(path, k), lookup)
+			if err != nil {
+				return nil, err
+			}
+			out[k] = x
+		}
+		return out, nil
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			x, err := interpolate(e, path+"["+strconv.Itoa(i)+"]", lookup)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = x
+		}
+		return out, nil
+	}
+	return v, nil
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// Expand replaces ${VAR} and ${VAR:-default} references in s. A bare $VAR is
+// left alone, and "$${" stands for a literal "${".
+func Expand(s string, lookup func(string) (string, bool)) (string, error) {
+	if !strings.Contains(s, "${") {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		switch {
+		case strings.HasPrefix(s[i:], "$${"):
+			b.WriteString("${")
+			i += 3
+		case strings.HasPrefix(s[i:], "${"):
+			end := strings.IndexByte(s[i:], '}')
+			if end < 0 {
+				return "", fmt.Errorf("unterminated ${ in %q", s)
+			}
+			ref := s[i+2 : i+end]
+			name, def, hasDefault := strings.Cut(ref, ":-")
+			if !validName(name) {
+				return "", fmt.Errorf("invalid variable name in ${%s}", ref)
+			}
+			value, ok := lookup(name)
+			switch {
+			case ok && value != "":
+				b.WriteString(value)
+			case hasDefault:
+				b.WriteString(def)
+			case ok:
+			default:
+				return "", fmt.Errorf("environment variable %s is not set", name)
+			}
+			i += end + 1
+		default:
+			b.WriteByte(s[i])
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// validName reports whether name is a shell variable name
+func validName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		if r != '_' && !(r >= 'A' && r <= 'Z') && !(r >= 'a' && r <= 'z') && (i == 0 || !(r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
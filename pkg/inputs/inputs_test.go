@@ -0,0 +1,121 @@
+package inputs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]any
+		wantErr string
+	}{
+		{
+			"yaml with multi-line string",
+			"name: orders\nreplicas: 3\npublic: false\ndescription: |\n  Line one.\n  Line two.\n",
+			map[string]any{"name": "orders", "replicas": 3, "public": false, "description": "Line one.\nLine two.\n"},
+			"",
+		},
+		{
+			"json",
+			`{"name": "orders", "labels": {"team": "payments"}, "topics": ["go", "grpc"]}`,
+			map[string]any{"name": "orders", "labels": map[string]any{"team": "payments"}, "topics": []any{"go", "grpc"}},
+			"",
+		},
+		{
+			"dates stay strings",
+			"launch: 2026-03-01\n",
+			map[string]any{"launch": "2026-03-01"},
+			"",
+		},
+		{"empty", "", map[string]any{}, ""},
+		{"not a mapping", "- a\n- b\n", nil, "must be a mapping"},
+		{"invalid", "name: [", nil, "invalid YAML or JSON"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Decode() = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Decode() = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func lookupFrom(env map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+func TestExpand(t *testing.T) {
+	env := lookupFrom(map[string]string{"TEAM": "payments", "EMPTY": ""})
+	tests := []struct {
+		in, want, wantErr string
+	}{
+		{"plain", "plain", ""},
+		{"${TEAM}-api", "payments-api", ""},
+		{"$TEAM stays", "$TEAM stays", ""},
+		{"cost: $$5", "cost: $$5", ""},
+		{"$${TEAM}", "${TEAM}", ""},
+		{"${MISSING:-fallback}", "fallback", ""},
+		{"${EMPTY:-fallback}", "fallback", ""},
+		{"[${EMPTY}]", "[]", ""},
+		{"${MISSING}", "", "MISSING is not set"},
+		{"${TEAM", "", "unterminated"},
+		{"${1X}", "", "invalid variable name"},
+	}
+	for _, tt := range tests {
+		got, err := Expand(tt.in, env)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expand(%q) = %q, %v, want error containing %q", tt.in, got, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("Expand(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestInterpolate(t *testing.T) {
+	env := lookupFrom(map[string]string{"TEAM": "payments"})
+	in := map[string]any{
+		"owner":    "${TEAM}",
+		"replicas": 3,
+		"labels":   map[string]any{"team": "${TEAM}"},
+		"topics":   []any{"go", "${TEAM}"},
+	}
+	got, err := Interpolate(in, env)
+	if err != nil {
+		t.Fatalf("Interpolate() = %v", err)
+	}
+	want := map[string]any{
+		"owner":    "payments",
+		"replicas": 3,
+		"labels":   map[string]any{"team": "payments"},
+		"topics":   []any{"go", "payments"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Interpolate() = %#v, want %#v", got, want)
+	}
+
+	_, err = Interpolate(map[string]any{"labels": map[string]any{"topics": []any{"${NOPE}"}}}, env)
+	if err == nil || err.Error() != "labels.topics[0]: environment variable NOPE is not set" {
+		t.Errorf("Interpolate() = %v, want error naming labels.topics[0]", err)
+	}
+}
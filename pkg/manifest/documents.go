@@ -0,0 +1,138 @@
+package manifest
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Document is one document of a YAML stream, such as a catalog-info.yaml
+// describing a Component and its API
+type Document struct {
+	Index   int    // position in the stream, from 1
+	Line    int    // line of the stream the document starts on, from 1
+	Content string // the document's source, without its --- separator
+}
+
+// SplitDocuments splits a YAML stream on its "---" and "..." markers, keeping
+// each document's source as is so that line numbers and comments survive.
+// A chunk holding only comments (such as a header above the first ---) is
+// joined to the document after it. Content without any document is returned
+// as a single, empty document.
+func SplitDocuments(content string) []Document {
+	var (
+		docs    []Document
+		current strings.Builder
+		start   = 1
+		pending bool // current has more than comments and blank lines
+	)
+	flush := func(next int) {
+		if pending {
+			docs = append(docs, Document{Index: len(docs) + 1, Line: start, Content: current.String()})
+			current.Reset()
+			start = next
+		} else if current.Len() == 0 {
+			start = next
+		}
+		pending = false
+	}
+
+	lines := strings.SplitAfter(content, "\n")
+	for i, line := range lines {
+		n := i + 1
+		trimmed := strings.TrimRight(line, "\r\n")
+		switch {
+		case trimmed == "---" || strings.HasPrefix(trimmed, "--- ") || strings.HasPrefix(trimmed, "---\t"):
+			flush(n + 1)
+			// Content on the separator line ("--- !tag", "--- {a: 1}")
+			// starts the document
+			if rest := strings.TrimSpace(trimmed[3:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				current.WriteString(rest + "\n")
+				start, pending = n, true
+			}
+		case trimmed == "..." || strings.HasPrefix(trimmed, "... "):
+			flush(n + 1)
+		default:
+			current.WriteString(line)
+			if t := strings.TrimSpace(trimmed); t != "" && !strings.HasPrefix(t, "#") {
+				pending = true
+			}
+		}
+	}
+	flush(len(lines) + 1)
+
+	if len(docs) == 0 {
+		return []Document{{Index: 1, Line: 1, Content: content}}
+	}
+	return docs
+}
+
+// JoinDocuments joins documents into a YAML stream, separated by ---
+func JoinDocuments(docs []string) string {
+	var b strings.Builder
+	for i, d := range docs {
+		if i > 0 {
+			b.WriteString("---\n")
+		}
+		b.WriteString(d)
+		if !strings.HasSuffix(d, "\n") {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// Sniff reports what the documents of a YAML file are: Shoehorn manifests,
+// with a schemaVersion or service key, and Backstage entities, with a
+// backstage.io apiVersion. Other YAML, such as a CI workflow or a compose
+// file, is neither; so is content that does not parse.
+func Sniff(data []byte) (shoehorn, backstage bool) {
+	for _, d := range SplitDocuments(string(data)) {
+		var doc map[string]any
+		if err := yaml.Unmarshal([]byte(d.Content), &doc); err != nil {
+			continue
+		}
+		if _, ok := doc["schemaVersion"]; ok {
+			shoehorn = true
+		} else if _, ok := doc["service"]; ok {
+			shoehorn = true
+		}
+		if v, ok := doc["apiVersion"].(string); ok && strings.Contains(v, "backstage.io/") {
+			backstage = true
+		}
+	}
+	return shoehorn, backstage
+}
+
+// Identity returns what a document describes, for messages: the Backstage
+// kind and metadata.name, or "Shoehorn" and service.id. Either is empty when
+// the document does not say.
+func (d Document) Identity() (kind, name string) {
+	var doc struct {
+		Kind     string `yaml:"kind"`
+		Metadata struct {
+			Name string `yaml:"name"`
+		} `yaml:"metadata"`
+		SchemaVersion any `yaml:"schemaVersion"`
+		Service       struct {
+			ID string `yaml:"id"`
+		} `yaml:"service"`
+	}
+	if err := yaml.Unmarshal([]byte(d.Content), &doc); err != nil {
+		return "", ""
+	}
+	if doc.Kind == "" && (doc.SchemaVersion != nil || doc.Service.ID != "") {
+		return "Shoehorn", doc.Service.ID
+	}
+	return doc.Kind, doc.Metadata.Name
+}
+
+// Describe names a document for messages: "document 2 (API orders-api)"
+func (d Document) Describe() string {
+	kind, name := d.Identity()
+	if label := strings.TrimSpace(kind + " " + name); label != "" {
+		return fmt.Sprintf("document %d (%s)", d.Index, label)
+	}
+	return fmt.Sprintf("document %d", d.Index)
+}
@@ -0,0 +1,99 @@
+package manifest
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []Document
+	}{
+		{
+			"single document",
+			"kind: Component\nmetadata: {name: a}\n",
+			[]Document{{1, 1, "kind: Component\nmetadata: {name: a}\n"}},
+		},
+		{
+			"leading separator and header comment",
+			"# header\n---\nkind: Component\n---\nkind: API\n",
+			[]Document{
+				{1, 1, "# header\nkind: Component\n"},
+				{2, 5, "kind: API\n"},
+			},
+		},
+		{
+			"empty documents and end markers are dropped",
+			"---\n---\nkind: A\n...\n---\n\n# only a comment\n",
+			[]Document{{1, 3, "kind: A\n"}},
+		},
+		{
+			"content on the separator line",
+			"a: 1\n--- {b: 2}\n",
+			[]Document{{1, 1, "a: 1\n"}, {2, 2, "{b: 2}\n"}},
+		},
+		{
+			"separator-like text is not a separator",
+			"a: |\n  ---\nb: ---x\n",
+			[]Document{{1, 1, "a: |\n  ---\nb: ---x\n"}},
+		},
+		{"empty", "", []Document{{1, 1, ""}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SplitDocuments(tt.content); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitDocuments() =\n  %+v\nwant\n  %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSniff(t *testing.T) {
+	tests := []struct {
+		name                string
+		content             string
+		shoehorn, backstage bool
+	}{
+		{"shoehorn", "schemaVersion: 1\nservice: {id: orders}\n", true, false},
+		{"shoehorn without schemaVersion", "service: {id: orders}\n", true, false},
+		{"backstage", "apiVersion: backstage.io/v1alpha1\nkind: Component\n", false, true},
+		{"backstage second document", "kind: Other\n---\napiVersion: backstage.io/v1alpha1\nkind: API\n", false, true},
+		{"workflow", "name: CI\non: [push]\njobs: {}\n", false, false},
+		{"compose", "services:\n  web: {image: nginx}\n", false, false},
+		{"template", "name: {{ .Values.name }}\n", false, false},
+		{"empty", "", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shoehorn, backstage := Sniff([]byte(tt.content))
+			if shoehorn != tt.shoehorn || backstage != tt.backstage {
+				t.Errorf("Sniff() = %v, %v, want %v, %v", shoehorn, backstage, tt.shoehorn, tt.backstage)
+			}
+		})
+	}
+}
+
+func TestJoinDocuments(t *testing.T) {
+	got := JoinDocuments([]string{"a: 1\n", "b: 2"})
+	if want := "a: 1\n---\nb: 2\n"; got != want {
+		t.Errorf("JoinDocuments() = %q, want %q", got, want)
+	}
+}
+
+func TestDocumentDescribe(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"kind: API\nmetadata: {name: orders-api}\n", "document 2 (API orders-api)"},
+		{"schemaVersion: 1\nservice: {id: orders}\n", "document 2 (Shoehorn orders)"},
+		{"kind: [", "document 2"},
+	}
+	for _, tt := range tests {
+		if got := (Document{Index: 2, Content: tt.content}).Describe(); got != tt.want {
+			t.Errorf("Describe(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,192 @@
+package manifest
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Files expands paths into manifest files, in order and without
+// duplicates. Directories contribute their *.yml and *.yaml files, and with
+// recursive those of subdirectories too (skipping .git). Globs are expanded,
+// with ** matching any number of directories. Files found in directories or
+// by globs are kept only if keep accepts them, so that other YAML in a
+// repository (CI workflows, compose files) is passed over, or if they are in
+// a .shoehorn directory. Files named explicitly are kept whatever their
+// extension or content; "-" stands for stdin.
+func Files(paths []string, recursive bool, keep func(name string, data []byte) bool) ([]string, error) {
+	var files []string
+	seen := map[string]bool{}
+	add := func(f string) {
+		if !seen[f] {
+			seen[f] = true
+			files = append(files, f)
+		}
+	}
+	addFound := func(f string) {
+		if !seen[f] && isWantedManifest(f, keep) {
+			add(f)
+		}
+	}
+
+	for _, p := range paths {
+		if p == "-" {
+			add(p)
+			continue
+		}
+		info, err := os.Stat(p)
+		if err != nil && isGlob(p) {
+			matches, err := expandGlob(p)
+			if err != nil {
+				return nil, err
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("%s: no files match", p)
+			}
+			for _, m := range matches {
+				addFound(m)
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			add(p)
+			continue
+		}
+		err = filepath.WalkDir(p, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != p && (!recursive || d.Name() == ".git") {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if isManifestFile(path) {
+				addFound(path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", p, err)
+		}
+	}
+	return files, nil
+}
+
+func isManifestFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yml" || ext == ".yaml"
+}
+
+// isWantedManifest reports whether a file found in a directory or by a glob
+// is read. Files in a .shoehorn directory are, even when broken, so that
+// their errors are reported; a file that cannot be read is too, for the same
+// reason.
+func isWantedManifest(name string, keep func(name string, data []byte) bool) bool {
+	if filepath.Base(filepath.Dir(name)) == ".shoehorn" {
+		return true
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return true
+	}
+	return keep(name, data)
+}
+
+// IsCatalogInfo reports whether a file is named like a Backstage catalog file
+func IsCatalogInfo(name string) bool {
+	base := strings.ToLower(filepath.Base(name))
+	return base == "catalog-info.yaml" || base == "catalog-info.yml"
+}
+
+func isGlob(p string) bool {
+	return strings.ContainsAny(p, "*?[")
+}
+
+// expandGlob returns the regular files matching pattern. Without ** this is
+// filepath.Glob; with it, the tree below the pattern's fixed prefix is walked
+// and matched segment by segment.
+func expandGlob(pattern string) ([]string, error) {
+	if !strings.Contains(pattern, "**") {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pattern, err)
+		}
+		var files []string
+		for _, m := range matches {
+			if info, err := os.Stat(m); err == nil && !info.IsDir() {
+				files = append(files, m)
+			}
+		}
+		return files, nil
+	}
+
+	segments := strings.Split(filepath.ToSlash(pattern), "/")
+	fixed := 0
+	for fixed < len(segments) && !isGlob(segments[fixed]) {
+		fixed++
+	}
+	root := strings.Join(segments[:fixed], "/")
+	if root == "" {
+		root = "."
+		if strings.HasPrefix(pattern, "/") {
+			root = "/"
+		}
+	}
+	rest := segments[fixed:]
+
+	var files []string
+	err := filepath.WalkDir(filepath.FromSlash(root), func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(filepath.FromSlash(root), p)
+		if err != nil {
+			return err
+		}
+		if matchSegments(rest, strings.Split(filepath.ToSlash(rel), "/")) {
+			files = append(files, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", pattern, err)
+	}
+	return files, nil
+}
+
+// matchSegments matches path segments against glob segments, where a "**"
+// segment matches zero or more path segments
+func matchSegments(pattern, name []string) bool {
+	if len(pattern) == 0 {
+		return len(name) == 0
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(name); i++ {
+			if matchSegments(pattern[1:], name[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(name) == 0 {
+		return false
+	}
+	if ok, _ := path.Match(pattern[0], name[0]); !ok {
+		return false
+	}
+	return matchSegments(pattern[1:], name[1:])
+}
@@ -0,0 +1,156 @@
+package manifest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern, name string
+		want          bool
+	}{
+		{"*.yml", "orders.yml", true},
+		{"*.yml", "svc/orders.yml", false},
+		{"**/*.yml", "orders.yml", true},
+		{"**/*.yml", "svc/orders.yml", true},
+		{"**/*.yml", "svc/payments/orders.yml", true},
+		{"**/*.yml", "svc/orders.yaml", false},
+		{"svc/**/orders.yml", "svc/orders.yml", true},
+		{"svc/**/orders.yml", "svc/a/b/orders.yml", true},
+		{"svc/**/orders.yml", "lib/a/orders.yml", false},
+		{"**/.shoehorn/*.yml", "svc/.shoehorn/orders.yml", true},
+		{"**/.shoehorn/*.yml", "svc/.shoehorn/extra/orders.yml", false},
+		{"**", "a/b/c", true},
+		{"a/**/b/**/c", "a/x/b/y/z/c", true},
+		{"a/**/b/**/c", "a/x/y/c", false},
+		{"svc/?.yml", "svc/a.yml", true},
+		{"svc/[ab].yml", "svc/c.yml", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.name, func(t *testing.T) {
+			got := matchSegments(strings.Split(tt.pattern, "/"), strings.Split(tt.name, "/"))
+			if got != tt.want {
+				t.Errorf("matchSegments(%q, %q) = %v, want %v", tt.pattern, tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+// writeTree creates files (relative, slash-separated) below dir with content
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestExpandGlob(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.yml":                "",
+		"svc/billing.yml":           "",
+		"svc/payments/ledger.yml":   "",
+		"svc/payments/notes.txt":    "",
+		".git/config.yml":           "",
+		"svc/.shoehorn/search.yaml": "",
+	})
+
+	tests := []struct {
+		pattern string
+		want    []string
+	}{
+		{"*.yml", []string{"orders.yml"}},
+		{"svc/*.yml", []string{"svc/billing.yml"}},
+		{"**/*.yml", []string{"orders.yml", "svc/billing.yml", "svc/payments/ledger.yml"}},
+		{"svc/**/*.yml", []string{"svc/billing.yml", "svc/payments/ledger.yml"}},
+		{"**/.shoehorn/*.yaml", []string{"svc/.shoehorn/search.yaml"}},
+		{"**/*.json", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			got, err := expandGlob(filepath.Join(dir, filepath.FromSlash(tt.pattern)))
+			if err != nil {
+				t.Fatalf("expandGlob() = %v", err)
+			}
+			var rel []string
+			for _, f := range got {
+				r, _ := filepath.Rel(dir, f)
+				rel = append(rel, filepath.ToSlash(r))
+			}
+			if !reflect.DeepEqual(rel, tt.want) {
+				t.Errorf("expandGlob(%q) = %v, want %v", tt.pattern, rel, tt.want)
+			}
+		})
+	}
+}
+
+func TestFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeTree(t, dir, map[string]string{
+		"orders.yml":           "schemaVersion: 1\nservice:\n  id: orders\n",
+		"ci.yml":               "on: push\n",
+		"svc/billing.yaml":     "schemaVersion: 1\nservice:\n  id: billing\n",
+		".shoehorn/broken.yml": "not: [valid\n",
+		"notes.txt":            "schemaVersion: 1\n",
+	})
+	keep := func(_ string, data []byte) bool {
+		shoehorn, _ := Sniff(data)
+		return shoehorn
+	}
+	rel := func(files []string) []string {
+		var out []string
+		for _, f := range files {
+			if r, err := filepath.Rel(dir, f); err == nil && !strings.HasPrefix(r, "..") {
+				f = filepath.ToSlash(r)
+			}
+			out = append(out, f)
+		}
+		return out
+	}
+
+	tests := []struct {
+		name      string
+		paths     []string
+		recursive bool
+		want      []string
+	}{
+		{"directory", []string{"."}, false, []string{"orders.yml"}},
+		{"recursive", []string{"."}, true, []string{".shoehorn/broken.yml", "orders.yml", "svc/billing.yaml"}},
+		{"explicit files are kept", []string{"ci.yml", "notes.txt"}, false, []string{"ci.yml", "notes.txt"}},
+		{"duplicates", []string{"orders.yml", ".", "orders.yml"}, false, []string{"orders.yml"}},
+		{"glob", []string{"**/*.y*ml"}, false, []string{".shoehorn/broken.yml", "orders.yml", "svc/billing.yaml"}},
+		{"stdin", []string{"-"}, false, []string{"-"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			paths := make([]string, len(tt.paths))
+			for i, p := range tt.paths {
+				paths[i] = p
+				if p != "-" {
+					paths[i] = filepath.Join(dir, p)
+				}
+			}
+			got, err := Files(paths, tt.recursive, keep)
+			if err != nil {
+				t.Fatalf("Files() = %v", err)
+			}
+			if !reflect.DeepEqual(rel(got), tt.want) {
+				t.Errorf("Files(%v) = %v, want %v", tt.paths, rel(got), tt.want)
+			}
+		})
+	}
+
+	if _, err := Files([]string{filepath.Join(dir, "**/*.json")}, false, keep); err == nil {
+		t.Error("Files() with an unmatched glob = nil, want error")
+	}
+}
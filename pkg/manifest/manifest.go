@@ -0,0 +1,142 @@
+// Package manifest defines the Shoehorn entity manifest, the YAML document
+// that describes a catalog entity (conventionally .shoehorn/<name>.yml).
+package manifest
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// SchemaVersion is the manifest schema version written by this CLI
+const SchemaVersion = 1
+
+// Manifest is a Shoehorn entity manifest
+type Manifest struct {
+	SchemaVersion int        `yaml:"schemaVersion" json:"schemaVersion"`
+	Service       Service    `yaml:"service" json:"service"`
+	Description   string     `yaml:"description,omitempty" json:"description,omitempty"`
+	Owner         []OwnerRef `yaml:"owner,omitempty" json:"owner,omitempty"`
+	Lifecycle     string     `yaml:"lifecycle,omitempty" json:"lifecycle,omitempty"`
+	Tags          []string   `yaml:"tags,omitempty" json:"tags,omitempty"`
+	Links         []Link     `yaml:"links,omitempty" json:"links,omitempty"`
+}
+
+// Service identifies the entity
+type Service struct {
+	ID   string `yaml:"id" json:"id"`
+	Name string `yaml:"name,omitempty" json:"name,omitempty"`
+	Type string `yaml:"type,omitempty" json:"type,omitempty"`
+	Tier string `yaml:"tier,omitempty" json:"tier,omitempty"`
+}
+
+// OwnerRef points at an owning team or user
+type OwnerRef struct {
+	Type string `yaml:"type" json:"type"`
+	ID   string `yaml:"id" json:"id"`
+}
+
+// Link is an external link shown on the entity page
+type Link struct {
+	Name string `yaml:"name" json:"name"`
+	URL  string `yaml:"url" json:"url"`
+	Icon string `yaml:"icon,omitempty" json:"icon,omitempty"`
+}
+
+// OwnerID returns the ID of the first owner, or "" if there is none
+func (m *Manifest) OwnerID() string {
+	if len(m.Owner) == 0 {
+		return ""
+	}
+	return m.Owner[0].ID
+}
+
+// Marshal encodes the manifest as YAML with two-space indentation
+func (m *Manifest) Marshal() ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(m); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encode manifest: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// Parse decodes a YAML manifest. It checks only what is needed to identify
+// the entity; full validation is the server's (or the schema's) job.
+func Parse(data []byte) (*Manifest, error) {
+	var m Manifest
+	if err := yaml.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse manifest: %w", err)
+	}
+	if m.Service.ID == "" {
+		return nil, fmt.Errorf("parse manifest: service.id is required")
+	}
+	return &m, nil
+}
+
+// FromEntity returns the manifest that describes a catalog entity as the API
+// reports it. The API exposes a single owner, which is taken to be a team.
+func FromEntity(e *api.EntityDetail) *Manifest {
+	m := &Manifest{
+		SchemaVersion: SchemaVersion,
+		Service: Service{
+			ID:   e.ID,
+			Name: e.Name,
+			Type: e.Type,
+			Tier: e.Tier,
+		},
+		Description: e.Description,
+		Lifecycle:   e.Lifecycle,
+		Tags:        e.Tags,
+	}
+	if e.Owner != "" {
+		m.Owner = []OwnerRef{{Type: "team", ID: e.Owner}}
+	}
+	for _, l := range e.Links {
+		m.Links = append(m.Links, Link{Name: l.Title, URL: l.URL, Icon: l.Icon})
+	}
+	return m
+}
+
+// Changes returns the names of the fields that differ between the live and
+// desired manifests, in manifest order. Owners are compared by the first
+// owner's ID, since that is all the API reports, and tags as a set.
+func Changes(live, desired *Manifest) []string {
+	var changed []string
+	add := func(field string, differs bool) {
+		if differs {
+			changed = append(changed, field)
+		}
+	}
+	add("service.name", live.Service.Name != desired.Service.Name)
+	add("service.type", live.Service.Type != desired.Service.Type)
+	add("service.tier", live.Service.Tier != desired.Service.Tier)
+	add("description", live.Description != desired.Description)
+	add("owner", live.OwnerID() != desired.OwnerID())
+	add("lifecycle", live.Lifecycle != desired.Lifecycle)
+	add("tags", !sameSet(live.Tags, desired.Tags))
+	add("links", !slices.Equal(live.Links, desired.Links))
+	return changed
+}
+
+// sameSet reports whether a and b hold the same strings, ignoring order and duplicates
+func sameSet(a, b []string) bool {
+	for _, s := range a {
+		if !slices.Contains(b, s) {
+			return false
+		}
+	}
+	for _, s := range b {
+		if !slices.Contains(a, s) {
+			return false
+		}
+	}
+	return true
+}
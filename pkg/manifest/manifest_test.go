@@ -0,0 +1,128 @@
+package manifest
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+func TestMarshalParse_RoundTrip(t *testing.T) {
+	want := &Manifest{
+		SchemaVersion: SchemaVersion,
+		Service:       Service{ID: "orders", Name: "Orders", Type: "service", Tier: "1"},
+		Description:   "Order API",
+		Owner:         []OwnerRef{{Type: "team", ID: "payments"}},
+		Lifecycle:     "production",
+		Tags:          []string{"go", "payments"},
+		Links:         []Link{{Name: "Runbook", URL: "https://runbooks.example.com"}},
+	}
+	data, err := want.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "schemaVersion: 1\nservice:\n  id: orders\n") {
+		t.Errorf("Marshal() =\n%s\nwant schemaVersion then service with 2-space indent", data)
+	}
+
+	got, err := Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+	}
+}
+
+func TestMarshal_OmitsEmpty(t *testing.T) {
+	data, err := (&Manifest{SchemaVersion: SchemaVersion, Service: Service{ID: "orders"}}).Marshal()
+	if err != nil {
+		t.Fatalf("Marshal() = %v", err)
+	}
+	if want := "schemaVersion: 1\nservice:\n  id: orders\n"; string(data) != want {
+		t.Errorf("Marshal() = %q, want %q", data, want)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"missing id", "schemaVersion: 1\nservice:\n  name: Orders\n", "service.id is required"},
+		{"bad yaml", "service: [", "parse manifest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestOwnerID(t *testing.T) {
+	if got := (&Manifest{}).OwnerID(); got != "" {
+		t.Errorf("OwnerID() = %q, want empty", got)
+	}
+	m := &Manifest{Owner: []OwnerRef{{Type: "team", ID: "payments"}, {Type: "user", ID: "ada"}}}
+	if got := m.OwnerID(); got != "payments" {
+		t.Errorf("OwnerID() = %q, want payments", got)
+	}
+}
+
+func TestFromEntity(t *testing.T) {
+	m := FromEntity(&api.EntityDetail{
+		Entity:    api.Entity{ID: "orders", Name: "Orders", Type: "service", Owner: "payments", Tags: []string{"go"}},
+		Links:     []api.EntityLink{{Title: "Runbook", URL: "https://runbooks.example.com", Icon: "book"}},
+		Lifecycle: "production",
+		Tier:      "1",
+	})
+	want := &Manifest{
+		SchemaVersion: SchemaVersion,
+		Service:       Service{ID: "orders", Name: "Orders", Type: "service", Tier: "1"},
+		Owner:         []OwnerRef{{Type: "team", ID: "payments"}},
+		Lifecycle:     "production",
+		Tags:          []string{"go"},
+		Links:         []Link{{Name: "Runbook", URL: "https://runbooks.example.com", Icon: "book"}},
+	}
+	if !reflect.DeepEqual(m, want) {
+		t.Errorf("FromEntity() = %+v, want %+v", m, want)
+	}
+}
+
+func TestChanges(t *testing.T) {
+	live := &Manifest{
+		Service: Service{ID: "orders", Name: "Orders", Tier: "1"},
+		Owner:   []OwnerRef{{Type: "team", ID: "payments"}},
+		Tags:    []string{"go", "grpc"},
+		Links:   []Link{{Name: "Runbook", URL: "https://a"}},
+	}
+	tests := []struct {
+		name   string
+		modify func(m *Manifest)
+		want   []string
+	}{
+		{"identical", func(m *Manifest) {}, nil},
+		{"tag order ignored", func(m *Manifest) { m.Tags = []string{"grpc", "go"} }, nil},
+		{"owner type ignored", func(m *Manifest) { m.Owner = []OwnerRef{{Type: "group", ID: "payments"}} }, nil},
+		{"owner", func(m *Manifest) { m.Owner = []OwnerRef{{Type: "team", ID: "checkout"}} }, []string{"owner"}},
+		{"tags and tier", func(m *Manifest) { m.Tags = []string{"go"}; m.Service.Tier = "2" }, []string{"service.tier", "tags"}},
+		{"links", func(m *Manifest) { m.Links = nil }, []string{"links"}},
+		{"description", func(m *Manifest) { m.Description = "Order API" }, []string{"description"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desired := *live
+			desired.Tags = slices.Clone(live.Tags)
+			tt.modify(&desired)
+			if got := Changes(live, &desired); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Changes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,268 @@
+// Package report encodes manifest validation results for CI tooling: SARIF
+// for code-scanning dashboards and JUnit XML for test dashboards.
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+// FileResult is the outcome of validating one file. Error is set instead of
+// Errors when the file could not be validated at all (unreadable, server error).
+// Files with several YAML documents also list each document's outcome;
+// Errors then holds the errors of all of them.
+type FileResult struct {
+	File      string                        `json:"file"`
+	Valid     bool                          `json:"valid"`
+	Errors    []api.ManifestValidationError `json:"errors"`
+	Error     string                        `json:"error,omitempty"`
+	Documents []DocumentResult              `json:"documents,omitempty"`
+}
+
+// DocumentResult is the outcome of validating one document of a file
+type DocumentResult struct {
+	Index  int                           `json:"index"`
+	Kind   string                        `json:"kind,omitempty"`
+	Name   string                        `json:"name,omitempty"`
+	Line   int                           `json:"line"`
+	Valid  bool                          `json:"valid"`
+	Errors []api.ManifestValidationError `json:"errors"`
+}
+
+// Summary counts results by outcome
+type Summary struct {
+	Files   int `json:"files"`
+	Valid   int `json:"valid"`
+	Invalid int `json:"invalid"`
+	Failed  int `json:"failed"`
+}
+
+// Summarize counts results by outcome
+func Summarize(results []FileResult) Summary {
+	s := Summary{Files: len(results)}
+	for _, r := range results {
+		switch {
+		case r.Error != "":
+			s.Failed++
+		case r.Valid:
+			s.Valid++
+		default:
+			s.Invalid++
+		}
+	}
+	return s
+}
+
+// FormatError renders a validation error as "field: message", or just the
+// message when it concerns the whole document
+func FormatError(e api.ManifestValidationError) string {
+	if e.Field == "" {
+		return e.Message
+	}
+	return e.Field + ": " + e.Message
+}
+
+// FormatLocated renders a validation error grep-style, as
+// "file:line:col: field: message", leaving out the parts of the position
+// that are unknown
+func FormatLocated(file string, e api.ManifestValidationError) string {
+	switch {
+	case e.Line > 0 && e.Column > 0:
+		return fmt.Sprintf("%s:%d:%d: %s", file, e.Line, e.Column, FormatError(e))
+	case e.Line > 0:
+		return fmt.Sprintf("%s:%d: %s", file, e.Line, FormatError(e))
+	}
+	return file + ": " + FormatError(e)
+}
+
+// ─── SARIF ───────────────────────────────────────────────────────────────────
+
+// Rule IDs reported in SARIF output
+const (
+	RuleInvalid = "manifest-invalid"
+	RuleFailed  = "manifest-unchecked"
+)
+
+const sarifSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID               string       `json:"id"`
+	ShortDescription sarifMessage `json:"shortDescription"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifact `json:"artifactLocation"`
+	Region           *sarifRegion  `json:"region,omitempty"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn,omitempty"`
+}
+
+type sarifArtifact struct {
+	URI string `json:"uri"`
+}
+
+// WriteSARIF writes results as a SARIF 2.1.0 log with one result per
+// validation error. toolVersion is the CLI version.
+func WriteSARIF(w io.Writer, results []FileResult, toolVersion string) error {
+	run := sarifRun{
+		Tool: sarifTool{Driver: sarifDriver{
+			Name:           "shoehorn",
+			Version:        toolVersion,
+			InformationURI: "https://github.com/shoehorn-dev/cli",
+			Rules: []sarifRule{
+				{ID: RuleInvalid, ShortDescription: sarifMessage{Text: "Manifest does not match its schema"}},
+				{ID: RuleFailed, ShortDescription: sarifMessage{Text: "Manifest could not be validated"}},
+			},
+		}},
+		Results: []sarifResult{},
+	}
+	for _, r := range results {
+		artifact := sarifArtifact{URI: filepath.ToSlash(r.File)}
+		if r.Error != "" {
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    RuleFailed,
+				Level:     "error",
+				Message:   sarifMessage{Text: r.Error},
+				Locations: []sarifLocation{{PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: artifact}}},
+			})
+			continue
+		}
+		for _, e := range r.Errors {
+			loc := sarifPhysicalLocation{ArtifactLocation: artifact}
+			if e.Line > 0 {
+				loc.Region = &sarifRegion{StartLine: e.Line, StartColumn: e.Column}
+			}
+			run.Results = append(run.Results, sarifResult{
+				RuleID:    RuleInvalid,
+				Level:     "error",
+				Message:   sarifMessage{Text: FormatError(e)},
+				Locations: []sarifLocation{{PhysicalLocation: loc}},
+			})
+		}
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(sarifLog{Schema: sarifSchema, Version: "2.1.0", Runs: []sarifRun{run}})
+}
+
+// ─── JUnit ───────────────────────────────────────────────────────────────────
+
+type junitSuites struct {
+	XMLName xml.Name     `xml:"testsuites"`
+	Tests   int          `xml:"tests,attr"`
+	Fail    int          `xml:"failures,attr"`
+	Errors  int          `xml:"errors,attr"`
+	Suites  []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name   string      `xml:"name,attr"`
+	Tests  int         `xml:"tests,attr"`
+	Fail   int         `xml:"failures,attr"`
+	Errors int         `xml:"errors,attr"`
+	Cases  []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitProblem `xml:"failure,omitempty"`
+	Error     *junitProblem `xml:"error,omitempty"`
+}
+
+type junitProblem struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Body    string `xml:",chardata"`
+}
+
+// WriteJUnit writes results as JUnit XML with one test case per file: invalid
+// files are failures, files that could not be validated are errors.
+func WriteJUnit(w io.Writer, results []FileResult) error {
+	sum := Summarize(results)
+	suite := junitSuite{Name: "shoehorn validate", Tests: sum.Files, Fail: sum.Invalid, Errors: sum.Failed}
+	for _, r := range results {
+		tc := junitCase{Name: r.File, ClassName: "shoehorn.validate"}
+		switch {
+		case r.Error != "":
+			tc.Error = &junitProblem{Message: r.Error}
+		case !r.Valid:
+			lines := make([]string, len(r.Errors))
+			for i, e := range r.Errors {
+				lines[i] = FormatLocated(r.File, e)
+			}
+			tc.Failure = &junitProblem{
+				Message: plural(len(r.Errors), "validation error"),
+				Type:    "validation",
+				Body:    strings.Join(lines, "\n"),
+			}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	doc := junitSuites{Tests: suite.Tests, Fail: suite.Fail, Errors: suite.Errors, Suites: []junitSuite{suite}}
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
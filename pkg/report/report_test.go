@@ -0,0 +1,131 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+func testResults() []FileResult {
+	return []FileResult{
+		{File: "svc/orders.yml", Valid: true, Errors: []api.ManifestValidationError{}},
+		{File: "svc/billing.yml", Valid: false, Errors: []api.ManifestValidationError{
+			{Field: "service.id", Message: "is required", Line: 2, Column: 1},
+			{Message: "manifest is empty"},
+		}},
+		{File: "svc/broken.yml", Error: "read svc/broken.yml: permission denied"},
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	got := Summarize(testResults())
+	want := Summary{Files: 3, Valid: 1, Invalid: 1, Failed: 1}
+	if got != want {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestFormatLocated(t *testing.T) {
+	tests := []struct {
+		err  api.ManifestValidationError
+		want string
+	}{
+		{api.ManifestValidationError{Field: "spec.owner", Message: "is required", Line: 9, Column: 3}, "a.yml:9:3: spec.owner: is required"},
+		{api.ManifestValidationError{Message: "invalid YAML: line 4: bad", Line: 4}, "a.yml:4: invalid YAML: line 4: bad"},
+		{api.ManifestValidationError{Message: "manifest is empty"}, "a.yml: manifest is empty"},
+	}
+	for _, tt := range tests {
+		if got := FormatLocated("a.yml", tt.err); got != tt.want {
+			t.Errorf("FormatLocated() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestWriteSARIF(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, testResults(), "1.2.3"); err != nil {
+		t.Fatalf("WriteSARIF() = %v", err)
+	}
+
+	var log sarifLog
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("version %q with %d runs, want 2.1.0 with 1", log.Version, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Version != "1.2.3" {
+		t.Errorf("driver version = %q", run.Tool.Driver.Version)
+	}
+
+	type result struct {
+		rule, uri, text string
+		line, col       int
+	}
+	var got []result
+	for _, r := range run.Results {
+		loc := r.Locations[0].PhysicalLocation
+		res := result{rule: r.RuleID, uri: loc.ArtifactLocation.URI, text: r.Message.Text}
+		if loc.Region != nil {
+			res.line, res.col = loc.Region.StartLine, loc.Region.StartColumn
+		}
+		got = append(got, res)
+	}
+	want := []result{
+		{RuleInvalid, "svc/billing.yml", "service.id: is required", 2, 1},
+		{RuleInvalid, "svc/billing.yml", "manifest is empty", 0, 0},
+		{RuleFailed, "svc/broken.yml", "read svc/broken.yml: permission denied", 0, 0},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("results = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("result %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestWriteSARIF_NoResults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteSARIF(&buf, nil, ""); err != nil {
+		t.Fatal(err)
+	}
+	// Code-scanning uploads reject a run without a results array
+	if !strings.Contains(buf.String(), `"results": []`) {
+		t.Errorf("empty run should have an empty results array:\n%s", buf.String())
+	}
+}
+
+func TestWriteJUnit(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJUnit(&buf, testResults()); err != nil {
+		t.Fatalf("WriteJUnit() = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "<?xml") {
+		t.Errorf("output should start with an XML header")
+	}
+
+	var doc junitSuites
+	if err := xml.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("output is not XML: %v", err)
+	}
+	if doc.Tests != 3 || doc.Fail != 1 || doc.Errors != 1 {
+		t.Errorf("totals = %d tests, %d failures, %d errors; want 3, 1, 1", doc.Tests, doc.Fail, doc.Errors)
+	}
+	cases := doc.Suites[0].Cases
+	if cases[0].Failure != nil || cases[0].Error != nil {
+		t.Errorf("valid file should pass: %+v", cases[0])
+	}
+	if f := cases[1].Failure; f == nil || f.Message != "2 validation errors" || f.Body != "svc/billing.yml:2:1: service.id: is required\nsvc/billing.yml: manifest is empty" {
+		t.Errorf("invalid file failure = %+v", f)
+	}
+	if e := cases[2].Error; e == nil || !strings.Contains(e.Message, "permission denied") {
+		t.Errorf("unreadable file error = %+v", e)
+	}
+}
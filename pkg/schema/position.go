@@ -0,0 +1,132 @@
+package schema
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Locate fills in Line and Column of each error from the manifest source by
+// resolving its Field path ("spec.owner", "links[0].url",
+// `annotations["example.com/key"]`) against the YAML. An error about a
+// missing field points at its closest existing parent; one about the whole
+// document at its start. Errors that already have a line are left alone, as
+// are all of them when content is not valid YAML.
+func Locate(content string, errs []api.ManifestValidationError) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil || len(doc.Content) == 0 {
+		return
+	}
+	root := doc.Content[0]
+	for i := range errs {
+		if errs[i].Line != 0 {
+			continue
+		}
+		n := Position(root, errs[i].Field)
+		errs[i].Line, errs[i].Column = n.Line, n.Column
+	}
+}
+
+// Position returns the node a field path refers to below root: the key of a
+// mapping entry, the item of a sequence, or, for a path that does not exist,
+// the deepest node on the way to it
+func Position(root *yaml.Node, field string) *yaml.Node {
+	at, node := root, root
+	for _, seg := range splitPath(field) {
+		for node.Kind == yaml.AliasNode && node.Alias != nil {
+			node = node.Alias
+		}
+		var key, value *yaml.Node
+		switch {
+		case node.Kind == yaml.MappingNode && !seg.index:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				if node.Content[i].Value == seg.key {
+					key, value = node.Content[i], node.Content[i+1]
+					break
+				}
+			}
+		case node.Kind == yaml.SequenceNode && seg.index:
+			if seg.n < len(node.Content) {
+				key, value = node.Content[seg.n], node.Content[seg.n]
+			}
+		}
+		if key == nil {
+			return at
+		}
+		at, node = key, value
+	}
+	return at
+}
+
+type pathSegment struct {
+	key   string
+	index bool
+	n     int
+}
+
+// splitPath parses a field path in the validator's format: dotted keys,
+// [N] indices and ["quoted"] keys
+func splitPath(field string) []pathSegment {
+	var segs []pathSegment
+	for field != "" {
+		switch {
+		case strings.HasPrefix(field, "."):
+			field = field[1:]
+		case strings.HasPrefix(field, `["`):
+			end := closingQuote(field[1:])
+			if end < 0 {
+				return append(segs, pathSegment{key: field})
+			}
+			key, err := strconv.Unquote(field[1 : end+2])
+			if err != nil {
+				key = field[2 : end+1]
+			}
+			segs = append(segs, pathSegment{key: key})
+			field = strings.TrimPrefix(field[end+2:], "]")
+		case strings.HasPrefix(field, "["):
+			end := strings.IndexByte(field, ']')
+			n, err := strconv.Atoi(field[1:max(end, 1)])
+			if end < 0 || err != nil {
+				return append(segs, pathSegment{key: field})
+			}
+			segs = append(segs, pathSegment{index: true, n: n})
+			field = field[end+1:]
+		default:
+			end := strings.IndexAny(field, ".[")
+			if end < 0 {
+				end = len(field)
+			}
+			segs = append(segs, pathSegment{key: field[:end]})
+			field = field[end:]
+		}
+	}
+	return segs
+}
+
+// closingQuote returns the index of the quote closing the string literal s
+// starts with, or -1
+func closingQuote(s string) int {
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return -1
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+):`)
+
+// syntaxErrorLine extracts the line number from a yaml.v3 syntax error
+func syntaxErrorLine(err error) int {
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		return n
+	}
+	return 0
+}
@@ -0,0 +1,77 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+const positionDoc = `apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: orders
+  annotations:
+    github.com/project-slug: acme/orders
+    "say \"hi\"": x
+  tags:
+    - go
+    - grpc
+  links: &links
+    - url: /runbook
+spec:
+  type: service
+  owner: {}
+  extra: *links
+`
+
+func TestLocate(t *testing.T) {
+	tests := []struct {
+		field     string
+		line, col int
+	}{
+		{"kind", 2, 1},
+		{"metadata.name", 4, 3},
+		{"metadata.tags[1]", 10, 7},
+		{"metadata.links[0].url", 12, 7},
+		{`metadata.annotations["github.com/project-slug"]`, 6, 5},
+		{`metadata.annotations["say \"hi\""]`, 7, 5},
+		// Missing fields point at their closest existing parent
+		{"spec.lifecycle", 13, 1},
+		{"spec.owner.name", 15, 3},
+		{"metadata.tags[5]", 8, 3},
+		// Aliases resolve to their anchor's content
+		{"spec.extra[0].url", 12, 7},
+		// The document itself
+		{"", 1, 1},
+	}
+	for _, tt := range tests {
+		errs := []api.ManifestValidationError{{Field: tt.field}}
+		Locate(positionDoc, errs)
+		if errs[0].Line != tt.line || errs[0].Column != tt.col {
+			t.Errorf("Locate(%q) = %d:%d, want %d:%d", tt.field, errs[0].Line, errs[0].Column, tt.line, tt.col)
+		}
+	}
+}
+
+func TestLocate_KeepsKnownLines(t *testing.T) {
+	errs := []api.ManifestValidationError{{Field: "kind", Line: 7}}
+	Locate(positionDoc, errs)
+	if errs[0].Line != 7 || errs[0].Column != 0 {
+		t.Errorf("Locate() overwrote a known line: %+v", errs[0])
+	}
+
+	errs = []api.ManifestValidationError{{Field: "kind"}}
+	Locate("kind: [", errs)
+	if errs[0].Line != 0 {
+		t.Errorf("Locate() on invalid YAML = %+v, want no position", errs[0])
+	}
+}
+
+func TestSplitPath(t *testing.T) {
+	got := splitPath(`a.b[2]["c.d"].e`)
+	want := []pathSegment{{key: "a"}, {key: "b"}, {index: true, n: 2}, {key: "c.d"}, {key: "e"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitPath() = %+v, want %+v", got, want)
+	}
+}
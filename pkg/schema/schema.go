@@ -0,0 +1,184 @@
+// Package schema validates Shoehorn and Backstage manifests offline against
+// JSON Schemas embedded in the binary, reporting errors in the same shape as
+// the server's /manifests/validate endpoint.
+package schema
+
+import (
+	"cmp"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+// Kind is a manifest format
+type Kind string
+
+// Manifest formats with embedded schemas
+const (
+	KindShoehorn  Kind = "shoehorn"
+	KindBackstage Kind = "backstage"
+)
+
+//go:embed schemas/*/*.json
+var schemaFS embed.FS
+
+var (
+	compileOnce sync.Once
+	compiled    map[Kind]map[string]*Schema // kind → version → schema
+	compileErr  error
+)
+
+// load compiles every embedded schema once
+func load() (map[Kind]map[string]*Schema, error) {
+	compileOnce.Do(func() {
+		compiled = map[Kind]map[string]*Schema{}
+		for _, kind := range []Kind{KindShoehorn, KindBackstage} {
+			entries, err := schemaFS.ReadDir("schemas/" + string(kind))
+			if err != nil {
+				compileErr = err
+				return
+			}
+			compiled[kind] = map[string]*Schema{}
+			for _, e := range entries {
+				name := "schemas/" + string(kind) + "/" + e.Name()
+				data, err := schemaFS.ReadFile(name)
+				if err != nil {
+					compileErr = err
+					return
+				}
+				var root map[string]any
+				if err := json.Unmarshal(data, &root); err != nil {
+					compileErr = fmt.Errorf("%s: %w", name, err)
+					return
+				}
+				s, err := Compile(root)
+				if err != nil {
+					compileErr = fmt.Errorf("%s: %w", name, err)
+					return
+				}
+				compiled[kind][strings.TrimSuffix(e.Name(), path.Ext(e.Name()))] = s
+			}
+		}
+	})
+	return compiled, compileErr
+}
+
+// Versions returns the embedded schema versions for a format, oldest first
+// (e.g. "v1" for Shoehorn, "v1alpha1" for Backstage)
+func Versions(kind Kind) []string {
+	all, err := load()
+	if err != nil {
+		return nil
+	}
+	versions := make([]string, 0, len(all[kind]))
+	for v := range all[kind] {
+		versions = append(versions, v)
+	}
+	slices.SortFunc(versions, compareVersions)
+	return versions
+}
+
+// compareVersions orders "v2" after "v1" and "v10" after "v9"
+func compareVersions(a, b string) int {
+	na, errA := strconv.Atoi(strings.TrimPrefix(a, "v"))
+	nb, errB := strconv.Atoi(strings.TrimPrefix(b, "v"))
+	if errA == nil && errB == nil {
+		return cmp.Compare(na, nb)
+	}
+	return strings.Compare(a, b)
+}
+
+// Detect returns the format of a decoded manifest: Backstage when it has a
+// backstage.io apiVersion, Shoehorn otherwise
+func Detect(doc any) Kind {
+	if m, ok := doc.(map[string]any); ok {
+		if v, ok := m["apiVersion"].(string); ok && strings.Contains(v, "backstage.io/") {
+			return KindBackstage
+		}
+	}
+	return KindShoehorn
+}
+
+// ValidateManifest validates manifest content against the embedded schema for
+// its format. version selects the Shoehorn schema version ("1" and "v1" are
+// the same); empty picks the version the manifest declares in schemaVersion,
+// or the latest. Backstage manifests always use the latest Backstage schema,
+// whatever version is given. An unknown version is an error; an invalid
+// manifest is not. Errors carry their line and column in content.
+func ValidateManifest(content, version string) (*api.ValidateManifestResponse, error) {
+	var doc any
+	if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+		return invalid(api.ManifestValidationError{
+			Message: "invalid YAML: " + strings.TrimPrefix(err.Error(), "yaml: "),
+			Line:    syntaxErrorLine(err),
+		}), nil
+	}
+	if doc == nil {
+		return invalid(api.ManifestValidationError{Message: "manifest is empty"}), nil
+	}
+
+	kind := Detect(doc)
+	s, err := lookup(kind, version, doc)
+	if err != nil {
+		return nil, err
+	}
+	errs := s.Validate(doc)
+	if errs == nil {
+		errs = []api.ManifestValidationError{}
+	}
+	Locate(content, errs)
+	return &api.ValidateManifestResponse{Valid: len(errs) == 0, Errors: errs}, nil
+}
+
+// CheckVersion returns the error ValidateManifest would give for every
+// Shoehorn manifest with this version, so that a bad version can be reported
+// once up front. Empty is always accepted.
+func CheckVersion(version string) error {
+	_, err := lookup(KindShoehorn, version, nil)
+	return err
+}
+
+// lookup picks the schema for a document. version applies to Shoehorn
+// manifests only.
+func lookup(kind Kind, version string, doc any) (*Schema, error) {
+	all, err := load()
+	if err != nil {
+		return nil, fmt.Errorf("load embedded schemas: %w", err)
+	}
+	versions := Versions(kind)
+
+	if version == "" || kind != KindShoehorn {
+		if kind == KindShoehorn {
+			if m, ok := doc.(map[string]any); ok {
+				if n, ok := m["schemaVersion"].(int); ok {
+					if s, ok := all[kind]["v"+strconv.Itoa(n)]; ok {
+						return s, nil
+					}
+				}
+			}
+		}
+		// Unknown or missing: validate against the latest, which reports
+		// schemaVersion itself if it is wrong
+		return all[kind][versions[len(versions)-1]], nil
+	}
+
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if s, ok := all[kind][version]; ok {
+		return s, nil
+	}
+	return nil, fmt.Errorf("no embedded %s schema version %q (available: %s)", kind, version, strings.Join(versions, ", "))
+}
+
+func invalid(e api.ManifestValidationError) *api.ValidateManifestResponse {
+	return &api.ValidateManifestResponse{Valid: false, Errors: []api.ManifestValidationError{e}}
+}
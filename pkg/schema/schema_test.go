@@ -0,0 +1,185 @@
+package schema
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+const validShoehorn = `schemaVersion: 1
+service:
+  id: orders
+  name: Orders
+  type: service
+  tier: 1
+owner:
+  - type: team
+    id: payments
+lifecycle: production
+tags: [go, grpc]
+links:
+  - name: Runbook
+    url: https://runbooks.example.com/orders
+`
+
+const validBackstage = `apiVersion: backstage.io/v1alpha1
+kind: Component
+metadata:
+  name: orders
+  tags: [go]
+  links:
+    - url: https://runbooks.example.com/orders
+      title: Runbook
+spec:
+  type: service
+  lifecycle: production
+  owner: team-payments
+`
+
+func TestEmbeddedSchemasCompile(t *testing.T) {
+	if _, err := load(); err != nil {
+		t.Fatalf("load() = %v", err)
+	}
+	if got := Versions(KindShoehorn); !reflect.DeepEqual(got, []string{"v1"}) {
+		t.Errorf("Versions(shoehorn) = %v, want [v1]", got)
+	}
+	if got := Versions(KindBackstage); !reflect.DeepEqual(got, []string{"v1alpha1"}) {
+		t.Errorf("Versions(backstage) = %v, want [v1alpha1]", got)
+	}
+}
+
+func TestValidateManifest(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []api.ManifestValidationError
+	}{
+		{"valid shoehorn", validShoehorn, nil},
+		{"valid backstage", validBackstage, nil},
+		{
+			"shoehorn missing id and unknown field",
+			"schemaVersion: 1\nservice:\n  name: Orders\nowner_team: payments\n",
+			[]api.ManifestValidationError{
+				{Field: "owner_team", Message: "unknown field", Line: 4, Column: 1},
+				{Field: "service.id", Message: "is required", Line: 2, Column: 1},
+			},
+		},
+		{
+			"shoehorn bad owner and link",
+			"schemaVersion: 1\nservice: {id: orders}\nowner: [{type: squad, id: payments}]\nlinks: [{name: Docs, url: /docs}]\n",
+			[]api.ManifestValidationError{
+				{Field: "links[0].url", Message: "must be an absolute URL", Line: 4, Column: 22},
+				{Field: "owner[0].type", Message: `must be one of: "team", "user", "group"`, Line: 3, Column: 10},
+			},
+		},
+		{
+			"shoehorn wrong types",
+			"schemaVersion: 1\nservice: {id: orders}\ntags: go\ndescription: 42\n",
+			[]api.ManifestValidationError{
+				{Field: "description", Message: "expected string, got integer", Line: 4, Column: 1},
+				{Field: "tags", Message: "expected array, got string", Line: 3, Column: 1},
+			},
+		},
+		{
+			"backstage component without owner",
+			strings.Replace(validBackstage, "  owner: team-payments\n", "", 1),
+			[]api.ManifestValidationError{{Field: "spec.owner", Message: "is required", Line: 9, Column: 1}},
+		},
+		{
+			"backstage API needs a definition",
+			strings.Replace(validBackstage, "kind: Component", "kind: API", 1),
+			[]api.ManifestValidationError{{Field: "spec.definition", Message: "is required", Line: 9, Column: 1}},
+		},
+		{
+			"backstage unknown kind",
+			strings.Replace(validBackstage, "kind: Component", "kind: Service", 1),
+			[]api.ManifestValidationError{{Field: "kind", Message: `must be one of: "Component", "API", "Resource", "System", "Domain", "Group", "User", "Location", "Template"`, Line: 2, Column: 1}},
+		},
+		{
+			"invalid yaml",
+			"service: [",
+			[]api.ManifestValidationError{{Message: "invalid YAML: line 1: did not find expected node content", Line: 1}},
+		},
+		{"empty", "", []api.ManifestValidationError{{Message: "manifest is empty"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ValidateManifest(tt.content, "")
+			if err != nil {
+				t.Fatalf("ValidateManifest() = %v", err)
+			}
+			if got.Valid != (len(tt.want) == 0) {
+				t.Errorf("Valid = %v, want %v", got.Valid, len(tt.want) == 0)
+			}
+			if len(tt.want) == 0 && len(got.Errors) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got.Errors, tt.want) {
+				t.Errorf("Errors =\n  %+v\nwant\n  %+v", got.Errors, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateManifest_SchemaVersion(t *testing.T) {
+	for _, v := range []string{"", "1", "v1"} {
+		if _, err := ValidateManifest(validShoehorn, v); err != nil {
+			t.Errorf("ValidateManifest(version %q) = %v", v, err)
+		}
+	}
+	// The version is for Shoehorn manifests; Backstage ones ignore it
+	for _, v := range []string{"1", "v1alpha1"} {
+		if _, err := ValidateManifest(validBackstage, v); err != nil {
+			t.Errorf("ValidateManifest(backstage, version %q) = %v", v, err)
+		}
+	}
+
+	_, err := ValidateManifest(validShoehorn, "7")
+	if err == nil || !strings.Contains(err.Error(), "available: v1") {
+		t.Errorf("ValidateManifest(version 7) = %v, want error listing available versions", err)
+	}
+
+	// A manifest declaring a version with no embedded schema is checked
+	// against the latest, which flags the version itself
+	got, err := ValidateManifest(strings.Replace(validShoehorn, "schemaVersion: 1", "schemaVersion: 3", 1), "")
+	if err != nil {
+		t.Fatalf("ValidateManifest(schemaVersion 3) = %v", err)
+	}
+	want := []api.ManifestValidationError{{Field: "schemaVersion", Message: "must be 1", Line: 1, Column: 1}}
+	if !reflect.DeepEqual(got.Errors, want) {
+		t.Errorf("Errors = %+v, want %+v", got.Errors, want)
+	}
+}
+
+func TestCheckVersion(t *testing.T) {
+	for _, v := range []string{"", "1", "v1"} {
+		if err := CheckVersion(v); err != nil {
+			t.Errorf("CheckVersion(%q) = %v", v, err)
+		}
+	}
+	for _, v := range []string{"7", "v1alpha1"} {
+		if err := CheckVersion(v); err == nil || !strings.Contains(err.Error(), "available: v1") {
+			t.Errorf("CheckVersion(%q) = %v, want error listing available versions", v, err)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		doc  any
+		want Kind
+	}{
+		{map[string]any{"apiVersion": "backstage.io/v1alpha1"}, KindBackstage},
+		{map[string]any{"apiVersion": "scaffolder.backstage.io/v1beta3"}, KindBackstage},
+		{map[string]any{"schemaVersion": 1}, KindShoehorn},
+		{map[string]any{"apiVersion": "v1"}, KindShoehorn},
+		{"not a map", KindShoehorn},
+	}
+	for _, tt := range tests {
+		if got := Detect(tt.doc); got != tt.want {
+			t.Errorf("Detect(%v) = %s, want %s", tt.doc, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,466 @@
+package schema
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+// Schema is a compiled JSON Schema. It supports the subset of draft 2020-12
+// used by the embedded manifest schemas: type, enum, const, the string,
+// number, array and object constraints, format "uri", local $ref, allOf,
+// anyOf, oneOf, not and if/then/else.
+type Schema struct {
+	root     map[string]any
+	patterns map[string]*regexp.Regexp
+}
+
+// Compile checks that every pattern and $ref in a decoded JSON Schema
+// resolves, and that keywords holding subschemas or required names have the
+// right shape, so that validation itself cannot fail.
+func Compile(root map[string]any) (*Schema, error) {
+	s := &Schema{root: root, patterns: map[string]*regexp.Regexp{}}
+	if err := s.compile(root); err != nil {
+		return nil, err
+	}
+	if err := checkShape(root, "#"); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// checkShape walks a schema through its subschemas and rejects keyword values
+// the validator cannot apply. A subschema is an object, or a boolean: true
+// matches anything and false nothing.
+func checkShape(sch any, at string) error {
+	m, ok := sch.(map[string]any)
+	if !ok {
+		if _, ok := sch.(bool); ok {
+			return nil
+		}
+		return fmt.Errorf("%s: schema must be an object or a boolean", at)
+	}
+
+	if r, ok := m["required"]; ok {
+		list, ok := r.([]any)
+		if !ok || slices.ContainsFunc(list, func(e any) bool { _, ok := e.(string); return !ok }) {
+			return fmt.Errorf("%s/required: must be an array of strings", at)
+		}
+	}
+	for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+		v, ok := m[kw]
+		if !ok {
+			continue
+		}
+		list, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s/%s: must be an array of schemas", at, kw)
+		}
+		for i, sub := range list {
+			if err := checkShape(sub, at+"/"+kw+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, kw := range []string{"not", "if", "then", "else", "items", "additionalProperties"} {
+		v, ok := m[kw]
+		if !ok {
+			continue
+		}
+		if _, tuple := v.([]any); tuple && kw == "items" {
+			continue // draft 4 tuple form, not applied
+		}
+		if err := checkShape(v, at+"/"+kw); err != nil {
+			return err
+		}
+	}
+	for _, kw := range []string{"properties", "$defs", "definitions"} {
+		v, ok := m[kw]
+		if !ok {
+			continue
+		}
+		subs, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s/%s: must be an object", at, kw)
+		}
+		for _, k := range slices.Sorted(maps.Keys(subs)) {
+			if err := checkShape(subs[k], at+"/"+kw+"/"+k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Schema) compile(node any) error {
+	switch n := node.(type) {
+	case map[string]any:
+		if p, ok := n["pattern"].(string); ok {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return fmt.Errorf("pattern %q: %w", p, err)
+			}
+			s.patterns[p] = re
+		}
+		if ref, ok := n["$ref"].(string); ok {
+			if _, err := s.resolve(ref); err != nil {
+				return err
+			}
+		}
+		for _, v := range n {
+			if err := s.compile(v); err != nil {
+				return err
+			}
+		}
+	case []any:
+		for _, v := range n {
+			if err := s.compile(v); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolve looks up a local reference such as "#/$defs/id"
+func (s *Schema) resolve(ref string) (map[string]any, error) {
+	if !strings.HasPrefix(ref, "#/") {
+		return nil, fmt.Errorf("$ref %q: only local references are supported", ref)
+	}
+	var node any = s.root
+	for _, part := range strings.Split(ref[2:], "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+		m, ok := node.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("$ref %q does not resolve", ref)
+		}
+		node = m[part]
+	}
+	m, ok := node.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("$ref %q does not resolve to a schema", ref)
+	}
+	return m, nil
+}
+
+// Validate checks a decoded YAML or JSON document and returns one error per
+// violation, in document order where possible. Field is a path such as
+// "spec.owner" or "links[0].url"; it is empty for the document itself.
+func (s *Schema) Validate(doc any) []api.ManifestValidationError {
+	var errs []api.ManifestValidationError
+	s.validate(s.root, normalize(doc), "", &errs)
+	return errs
+}
+
+// matches reports whether x is valid against sub without recording errors
+func (s *Schema) matches(sub any, x any) bool {
+	var errs []api.ManifestValidationError
+	s.apply(sub, x, "", &errs)
+	return len(errs) == 0
+}
+
+// apply validates x against a subschema: an object is validated, true
+// matches anything and false nothing
+func (s *Schema) apply(sub any, x any, path string, errs *[]api.ManifestValidationError) {
+	switch sub := sub.(type) {
+	case map[string]any:
+		s.validate(sub, x, path, errs)
+	case bool:
+		if !sub {
+			*errs = append(*errs, api.ManifestValidationError{Field: path, Message: "is not allowed"})
+		}
+	}
+}
+
+func (s *Schema) validate(sch map[string]any, x any, path string, errs *[]api.ManifestValidationError) {
+	fail := func(field, format string, args ...any) {
+		*errs = append(*errs, api.ManifestValidationError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if ref, ok := sch["$ref"].(string); ok {
+		sub, _ := s.resolve(ref) // checked by Compile
+		s.validate(sub, x, path, errs)
+	}
+
+	if t, ok := sch["type"]; ok && !matchesType(t, x) {
+		fail(path, "expected %s, got %s", typeList(t), typeOf(x))
+		return
+	}
+	if enum, ok := sch["enum"].([]any); ok && !slices.ContainsFunc(enum, func(v any) bool { return equal(v, x) }) {
+		fail(path, "must be one of: %s", joinValues(enum))
+	}
+	if c, ok := sch["const"]; ok && !equal(c, x) {
+		fail(path, "must be %s", formatValue(c))
+	}
+
+	switch v := x.(type) {
+	case string:
+		s.validateString(sch, v, path, fail)
+	case float64:
+		if min, ok := number(sch["minimum"]); ok && v < min {
+			fail(path, "must be at least %s", formatValue(sch["minimum"]))
+		}
+		if max, ok := number(sch["maximum"]); ok && v > max {
+			fail(path, "must be at most %s", formatValue(sch["maximum"]))
+		}
+	case []any:
+		s.validateArray(sch, v, path, fail, errs)
+	case map[string]any:
+		s.validateObject(sch, v, path, fail, errs)
+	}
+
+	if all, ok := sch["allOf"].([]any); ok {
+		for _, sub := range all {
+			s.apply(sub, x, path, errs)
+		}
+	}
+	if anyOf, ok := sch["anyOf"].([]any); ok {
+		if !slices.ContainsFunc(anyOf, func(sub any) bool { return s.matches(sub, x) }) {
+			fail(path, "does not match any of the allowed forms")
+		}
+	}
+	if oneOf, ok := sch["oneOf"].([]any); ok {
+		n := 0
+		for _, sub := range oneOf {
+			if s.matches(sub, x) {
+				n++
+			}
+		}
+		if n != 1 {
+			fail(path, "must match exactly one of the allowed forms (matches %d)", n)
+		}
+	}
+	if not, ok := sch["not"]; ok && s.matches(not, x) {
+		fail(path, "matches a form that is not allowed")
+	}
+	if cond, ok := sch["if"]; ok {
+		branch := "else"
+		if s.matches(cond, x) {
+			branch = "then"
+		}
+		if sub, ok := sch[branch]; ok {
+			s.apply(sub, x, path, errs)
+		}
+	}
+}
+
+func (s *Schema) validateString(sch map[string]any, v, path string, fail func(string, string, ...any)) {
+	n := utf8.RuneCountInString(v)
+	if min, ok := number(sch["minLength"]); ok && float64(n) < min {
+		if min == 1 {
+			fail(path, "must not be empty")
+		} else {
+			fail(path, "must be at least %s characters", formatValue(sch["minLength"]))
+		}
+	}
+	if max, ok := number(sch["maxLength"]); ok && float64(n) > max {
+		fail(path, "must be at most %s characters", formatValue(sch["maxLength"]))
+	}
+	if p, ok := sch["pattern"].(string); ok && !s.patterns[p].MatchString(v) {
+		fail(path, "%q does not match pattern %s", v, p)
+	}
+	if sch["format"] == "uri" {
+		if u, err := url.Parse(v); err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+			fail(path, "must be an absolute URL")
+		}
+	}
+}
+
+func (s *Schema) validateArray(sch map[string]any, v []any, path string, fail func(string, string, ...any), errs *[]api.ManifestValidationError) {
+	if min, ok := number(sch["minItems"]); ok && float64(len(v)) < min {
+		if min == 1 {
+			fail(path, "must not be empty")
+		} else {
+			fail(path, "must have at least %s items", formatValue(sch["minItems"]))
+		}
+	}
+	if max, ok := number(sch["maxItems"]); ok && float64(len(v)) > max {
+		fail(path, "must have at most %s items", formatValue(sch["maxItems"]))
+	}
+	if sch["uniqueItems"] == true {
+		for i := range v {
+			if slices.ContainsFunc(v[:i], func(w any) bool { return equal(w, v[i]) }) {
+				fail(IndexPath(path, i), "duplicates an earlier item")
+			}
+		}
+	}
+	if items, ok := sch["items"]; ok {
+		if _, tuple := items.([]any); !tuple {
+			for i, item := range v {
+				s.apply(items, item, IndexPath(path, i), errs)
+			}
+		}
+	}
+}
+
+func (s *Schema) validateObject(sch map[string]any, v map[string]any, path string, fail func(string, string, ...any), errs *[]api.ManifestValidationError) {
+	if required, ok := sch["required"].([]any); ok {
+		for _, r := range required {
+			name, ok := r.(string)
+			if !ok {
+				continue // rejected by Compile
+			}
+			if _, ok := v[name]; !ok {
+				fail(FieldPath(path, name), "is required")
+			}
+		}
+	}
+
+	props, _ := sch["properties"].(map[string]any)
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	for _, k := range keys {
+		if sub, ok := props[k]; ok {
+			s.apply(sub, v[k], FieldPath(path, k), errs)
+			continue
+		}
+		switch extra := sch["additionalProperties"].(type) {
+		case bool:
+			if !extra {
+				fail(FieldPath(path, k), "unknown field")
+			}
+		case map[string]any:
+			s.validate(extra, v[k], FieldPath(path, k), errs)
+		}
+	}
+}
+
+// FieldPath appends an object key to a field path in the format validation
+// errors use ("metadata.links", `metadata.annotations["backstage.io/x"]`),
+// quoting keys that would be ambiguous
+func FieldPath(path, key string) string {
+	if strings.ContainsAny(key, ".[]\" ") || key == "" {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// IndexPath appends an array index to a field path ("metadata.links[0]")
+func IndexPath(path string, i int) string {
+	return path + "[" + strconv.Itoa(i) + "]"
+}
+
+// normalize converts decoded YAML into JSON data model values: map keys
+// become strings, numbers become float64 and timestamps strings.
+func normalize(x any) any {
+	switch v := x.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[k] = normalize(e)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, e := range v {
+			out[fmt.Sprint(k)] = normalize(e)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, e := range v {
+			out[i] = normalize(e)
+		}
+		return out
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339)
+	}
+	return x
+}
+
+func typeOf(x any) string {
+	switch v := x.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", x)
+}
+
+func matchesType(t, x any) bool {
+	actual := typeOf(x)
+	ok := func(want string) bool {
+		return want == actual || (want == "number" && actual == "integer")
+	}
+	switch t := t.(type) {
+	case string:
+		return ok(t)
+	case []any:
+		return slices.ContainsFunc(t, func(w any) bool { s, _ := w.(string); return ok(s) })
+	}
+	return true
+}
+
+func typeList(t any) string {
+	if list, ok := t.([]any); ok {
+		names := make([]string, len(list))
+		for i, n := range list {
+			names[i] = fmt.Sprint(n)
+		}
+		return strings.Join(names, " or ")
+	}
+	return fmt.Sprint(t)
+}
+
+func number(v any) (float64, bool) {
+	f, ok := v.(float64)
+	return f, ok
+}
+
+func equal(a, b any) bool {
+	return reflect.DeepEqual(a, b)
+}
+
+func formatValue(v any) string {
+	if s, ok := v.(string); ok {
+		return strconv.Quote(s)
+	}
+	if f, ok := v.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(v)
+}
+
+func joinValues(values []any) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = formatValue(v)
+	}
+	return strings.Join(out, ", ")
+}
@@ -0,0 +1,221 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"gopkg.in/yaml.v3"
+)
+
+func mustCompile(t *testing.T, schema string) *Schema {
+	t.Helper()
+	var root map[string]any
+	if err := json.Unmarshal([]byte(schema), &root); err != nil {
+		t.Fatal(err)
+	}
+	s, err := Compile(root)
+	if err != nil {
+		t.Fatalf("Compile() = %v", err)
+	}
+	return s
+}
+
+func validateYAML(t *testing.T, s *Schema, doc string) []api.ManifestValidationError {
+	t.Helper()
+	var v any
+	if err := yaml.Unmarshal([]byte(doc), &v); err != nil {
+		t.Fatal(err)
+	}
+	return s.Validate(v)
+}
+
+func TestValidate_Keywords(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		doc    string
+		want   []api.ManifestValidationError
+	}{
+		{
+			"integer accepts whole floats",
+			`{"type":"integer"}`, `3.0`, nil,
+		},
+		{
+			"number accepts integers",
+			`{"type":"number","minimum":1,"maximum":10}`, `12`,
+			[]api.ManifestValidationError{{Message: "must be at most 10"}},
+		},
+		{
+			"multiple types",
+			`{"type":["string","null"]}`, `true`,
+			[]api.ManifestValidationError{{Message: "expected string or null, got boolean"}},
+		},
+		{
+			"length counts runes",
+			`{"type":"string","maxLength":3}`, `"héé"`, nil,
+		},
+		{
+			"unique items",
+			`{"type":"array","uniqueItems":true,"minItems":2}`, `[a, b, a]`,
+			[]api.ManifestValidationError{{Field: "[2]", Message: "duplicates an earlier item"}},
+		},
+		{
+			"additionalProperties schema",
+			`{"type":"object","additionalProperties":{"type":"string"}}`, `{team: payments, size: 3}`,
+			[]api.ManifestValidationError{{Field: "size", Message: "expected string, got integer"}},
+		},
+		{
+			"keys needing quotes",
+			`{"type":"object","properties":{"annotations":{"additionalProperties":{"type":"string"}}}}`,
+			`{annotations: {"github.com/project-slug": 1}}`,
+			[]api.ManifestValidationError{{Field: `annotations["github.com/project-slug"]`, Message: "expected string, got integer"}},
+		},
+		{
+			"local ref",
+			`{"properties":{"id":{"$ref":"#/$defs/id"}},"$defs":{"id":{"pattern":"^[a-z]+$"}}}`, `{id: Orders}`,
+			[]api.ManifestValidationError{{Field: "id", Message: `"Orders" does not match pattern ^[a-z]+$`}},
+		},
+		{
+			"if then else",
+			`{"if":{"properties":{"kind":{"const":"A"}}},"then":{"required":["a"]},"else":{"required":["b"]}}`, `{kind: B}`,
+			[]api.ManifestValidationError{{Field: "b", Message: "is required"}},
+		},
+		{
+			"anyOf",
+			`{"anyOf":[{"type":"string"},{"type":"integer"}]}`, `[1]`,
+			[]api.ManifestValidationError{{Message: "does not match any of the allowed forms"}},
+		},
+		{
+			"oneOf matching two",
+			`{"oneOf":[{"type":"integer"},{"minimum":0}]}`, `5`,
+			[]api.ManifestValidationError{{Message: "must match exactly one of the allowed forms (matches 2)"}},
+		},
+		{
+			"not",
+			`{"not":{"const":"latest"}}`, `latest`,
+			[]api.ManifestValidationError{{Message: "matches a form that is not allowed"}},
+		},
+		{
+			"boolean subschemas",
+			`{"anyOf":[true,{"type":"string"}],"properties":{"legacy":false,"tags":{"items":true}}}`, `{tags: [1, a]}`, nil,
+		},
+		{
+			"false subschema",
+			`{"properties":{"legacy":false}}`, `{legacy: 1}`,
+			[]api.ManifestValidationError{{Field: "legacy", Message: "is not allowed"}},
+		},
+		{
+			"allOf false",
+			`{"allOf":[false]}`, `1`,
+			[]api.ManifestValidationError{{Message: "is not allowed"}},
+		},
+		{
+			"oneOf with true",
+			`{"oneOf":[true,{"type":"string"}]}`, `x`,
+			[]api.ManifestValidationError{{Message: "must match exactly one of the allowed forms (matches 2)"}},
+		},
+		{
+			"not true",
+			`{"not":true}`, `1`,
+			[]api.ManifestValidationError{{Message: "matches a form that is not allowed"}},
+		},
+		{
+			"yaml timestamps are strings",
+			`{"type":"string"}`, `2026-03-01`, nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validateYAML(t, mustCompile(t, tt.schema), tt.doc)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Validate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompile_Errors(t *testing.T) {
+	tests := []struct {
+		schema  string
+		wantErr string
+	}{
+		{`{"pattern":"("}`, "pattern"},
+		{`{"$ref":"#/$defs/missing"}`, "does not resolve"},
+		{`{"$ref":"https://example.com/schema.json"}`, "only local references"},
+		{`{"required":["id",1]}`, "#/required: must be an array of strings"},
+		{`{"properties":{"a":{"required":"id"}}}`, "#/properties/a/required: must be an array of strings"},
+		{`{"anyOf":{"type":"string"}}`, "#/anyOf: must be an array of schemas"},
+		{`{"allOf":["string"]}`, "#/allOf/0: schema must be an object or a boolean"},
+		{`{"items":{"not":1}}`, "#/items/not: schema must be an object or a boolean"},
+		{`{"properties":[]}`, "#/properties: must be an object"},
+	}
+	for _, tt := range tests {
+		var root map[string]any
+		json.Unmarshal([]byte(tt.schema), &root)
+		if _, err := Compile(root); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("Compile(%s) = %v, want error containing %q", tt.schema, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidate_MoldInputs(t *testing.T) {
+	// Mold schemas come from the server and may use boolean subschemas
+	var mold api.MoldDetail
+	err := json.Unmarshal([]byte(`{"schema": {
+		"type": "object",
+		"required": ["name"],
+		"properties": {
+			"name": {"type": "string", "pattern": "^[a-z-]+$"},
+			"replicas": {"anyOf": [true, {"type": "integer"}]},
+			"metadata": true,
+			"legacy": false,
+			"tags": {"type": "array", "items": {"allOf": [true, {"type": "string"}]}}
+		},
+		"additionalProperties": false
+	}}`), &mold)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := Compile(mold.Schema)
+	if err != nil {
+		t.Fatalf("Compile() = %v", err)
+	}
+
+	valid := map[string]any{"name": "orders", "replicas": int64(3), "metadata": map[string]any{"team": "payments"}, "tags": []any{"go"}}
+	if got := s.Validate(valid); got != nil {
+		t.Errorf("Validate(valid) = %+v, want none", got)
+	}
+
+	got := s.Validate(map[string]any{"name": "orders", "legacy": true, "tags": []any{float64(1)}})
+	want := []api.ManifestValidationError{
+		{Field: "legacy", Message: "is not allowed"},
+		{Field: "tags[0]", Message: "expected string, got integer"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Validate(invalid) = %+v, want %+v", got, want)
+	}
+}
+
+func TestFieldPath(t *testing.T) {
+	tests := []struct {
+		path, key, want string
+	}{
+		{"", "metadata", "metadata"},
+		{"metadata", "name", "metadata.name"},
+		{"metadata.annotations", "backstage.io/x", `metadata.annotations["backstage.io/x"]`},
+		{"spec", "with space", `spec["with space"]`},
+		{"spec", "", `spec[""]`},
+		{"", "a[0]", `["a[0]"]`},
+	}
+	for _, tt := range tests {
+		if got := FieldPath(tt.path, tt.key); got != tt.want {
+			t.Errorf("FieldPath(%q, %q) = %s, want %s", tt.path, tt.key, got, tt.want)
+		}
+	}
+	if got := IndexPath(FieldPath("metadata", "links"), 2); got != "metadata.links[2]" {
+		t.Errorf("IndexPath() = %s", got)
+	}
+}
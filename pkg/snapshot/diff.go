@@ -0,0 +1,206 @@
+package snapshot
+
+import (
+	"slices"
+	"strings"
+)
+
+// Diff is the difference between the entities of two snapshots
+type Diff struct {
+	From    string        `json:"from"` // label of the old side (file or profile)
+	To      string        `json:"to"`
+	Added   []*Entity     `json:"added"`
+	Removed []*Entity     `json:"removed"`
+	Changed []*EntityDiff `json:"changed"`
+	Summary DiffSummary   `json:"summary"`
+}
+
+// DiffSummary counts the changes in a Diff
+type DiffSummary struct {
+	Added          int `json:"added"`
+	Removed        int `json:"removed"`
+	Changed        int `json:"changed"`
+	OwnerChanges   int `json:"owner_changes"`
+	TagChanges     int `json:"tag_changes"`
+	GradesImproved int `json:"grades_improved"`
+	GradesDropped  int `json:"grades_dropped"`
+}
+
+// EntityDiff describes how one entity differs between two snapshots
+type EntityDiff struct {
+	ID          string         `json:"id"`
+	Owner       *Change        `json:"owner,omitempty"`
+	TagsAdded   []string       `json:"tags_added,omitempty"`
+	TagsRemoved []string       `json:"tags_removed,omitempty"`
+	Grade       *GradeChange   `json:"grade,omitempty"`
+	Fields      []*FieldChange `json:"fields,omitempty"` // name, type, lifecycle, tier, description
+}
+
+// Change is an old and a new value
+type Change struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// FieldChange is a changed scalar field of an entity
+type FieldChange struct {
+	Field string `json:"field"`
+	Change
+}
+
+// GradeChange is a scorecard grade movement. An empty grade means the entity
+// had no scorecard on that side.
+type GradeChange struct {
+	Change
+	FromScore int `json:"from_score"`
+	ToScore   int `json:"to_score"`
+}
+
+// Improved reports whether the grade got better (A is best)
+func (g *GradeChange) Improved() bool {
+	return gradeRank(g.To) > gradeRank(g.From)
+}
+
+// gradeRank orders grades so that higher is better (A+ > A > A- > B+) and
+// no grade, or one that is not a letter with an optional + or -, is lowest
+func gradeRank(grade string) int {
+	if len(grade) == 0 || len(grade) > 2 || grade[0] < 'A' || grade[0] > 'Z' {
+		return 0
+	}
+	modifier := 1
+	if len(grade) == 2 {
+		switch grade[1] {
+		case '+':
+			modifier = 2
+		case '-':
+			modifier = 0
+		default:
+			return 0
+		}
+	}
+	return int('Z'-grade[0])*3 + modifier + 1
+}
+
+// Empty reports whether the two snapshots have the same entities
+func (d *Diff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// Compare returns what changed from the entities of a to those of b.
+// Entities are matched by ID; only catalog-managed fields, ownership, tags and
+// the scorecard grade are compared, so timestamps and health do not show up
+// as drift.
+func Compare(a, b *Snapshot) *Diff {
+	d := &Diff{Added: []*Entity{}, Removed: []*Entity{}, Changed: []*EntityDiff{}}
+
+	old := make(map[string]*Entity, len(a.Entities))
+	for _, e := range a.Entities {
+		old[e.ID] = e
+	}
+	seen := make(map[string]bool, len(b.Entities))
+	for _, e := range b.Entities {
+		seen[e.ID] = true
+		prev, ok := old[e.ID]
+		if !ok {
+			d.Added = append(d.Added, e)
+			continue
+		}
+		if ed := compareEntity(prev, e); ed != nil {
+			d.Changed = append(d.Changed, ed)
+		}
+	}
+	for _, e := range a.Entities {
+		if !seen[e.ID] {
+			d.Removed = append(d.Removed, e)
+		}
+	}
+
+	byID := func(x, y *Entity) int { return strings.Compare(x.ID, y.ID) }
+	slices.SortFunc(d.Added, byID)
+	slices.SortFunc(d.Removed, byID)
+	slices.SortFunc(d.Changed, func(x, y *EntityDiff) int { return strings.Compare(x.ID, y.ID) })
+
+	d.Summary = DiffSummary{Added: len(d.Added), Removed: len(d.Removed), Changed: len(d.Changed)}
+	for _, c := range d.Changed {
+		if c.Owner != nil {
+			d.Summary.OwnerChanges++
+		}
+		if len(c.TagsAdded) > 0 || len(c.TagsRemoved) > 0 {
+			d.Summary.TagChanges++
+		}
+		if c.Grade != nil {
+			if c.Grade.Improved() {
+				d.Summary.GradesImproved++
+			} else {
+				d.Summary.GradesDropped++
+			}
+		}
+	}
+	return d
+}
+
+// compareEntity returns the differences between a and b, or nil if none
+func compareEntity(a, b *Entity) *EntityDiff {
+	d := &EntityDiff{ID: b.ID}
+	changed := false
+
+	if a.Owner != b.Owner {
+		d.Owner = &Change{From: a.Owner, To: b.Owner}
+		changed = true
+	}
+
+	d.TagsAdded, d.TagsRemoved = setDiff(a.Tags, b.Tags)
+	if len(d.TagsAdded) > 0 || len(d.TagsRemoved) > 0 {
+		changed = true
+	}
+
+	fromGrade, fromScore := grade(a)
+	toGrade, toScore := grade(b)
+	if fromGrade != toGrade {
+		d.Grade = &GradeChange{Change: Change{From: fromGrade, To: toGrade}, FromScore: fromScore, ToScore: toScore}
+		changed = true
+	}
+
+	for _, f := range []struct{ name, from, to string }{
+		{"name", a.Name, b.Name},
+		{"type", a.Type, b.Type},
+		{"lifecycle", a.Lifecycle, b.Lifecycle},
+		{"tier", a.Tier, b.Tier},
+		{"description", a.Description, b.Description},
+	} {
+		if f.from != f.to {
+			d.Fields = append(d.Fields, &FieldChange{Field: f.name, Change: Change{From: f.from, To: f.to}})
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	return d
+}
+
+// grade returns the scorecard grade and score of e, or "" without a scorecard
+func grade(e *Entity) (string, int) {
+	if e.Scorecard == nil {
+		return "", 0
+	}
+	return e.Scorecard.Grade, e.Scorecard.Score
+}
+
+// setDiff returns the sorted elements only in b (added) and only in a (removed)
+func setDiff(a, b []string) (added, removed []string) {
+	for _, t := range b {
+		if !slices.Contains(a, t) && !slices.Contains(added, t) {
+			added = append(added, t)
+		}
+	}
+	for _, t := range a {
+		if !slices.Contains(b, t) && !slices.Contains(removed, t) {
+			removed = append(removed, t)
+		}
+	}
+	slices.Sort(added)
+	slices.Sort(removed)
+	return added, removed
+}
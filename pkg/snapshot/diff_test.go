@@ -0,0 +1,126 @@
+package snapshot
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+func entity(id, owner string, tags []string, grade string, score int) *Entity {
+	e := &Entity{EntityDetail: api.EntityDetail{Entity: api.Entity{ID: id, Name: id, Owner: owner, Tags: tags}}}
+	if grade != "" {
+		e.Scorecard = &api.Scorecard{Grade: grade, Score: score}
+	}
+	return e
+}
+
+func TestCompare(t *testing.T) {
+	a := &Snapshot{Entities: []*Entity{
+		entity("orders", "payments", []string{"go", "legacy"}, "B", 80),
+		entity("billing", "payments", nil, "C", 65),
+		entity("search", "discovery", []string{"java"}, "", 0),
+		entity("gone", "platform", nil, "", 0),
+		entity("same", "platform", []string{"go"}, "A", 95),
+	}}
+	b := &Snapshot{Entities: []*Entity{
+		entity("orders", "checkout", []string{"go", "grpc"}, "A", 92),
+		entity("billing", "payments", nil, "D", 50),
+		entity("search", "discovery", []string{"java"}, "B", 78),
+		entity("new", "platform", nil, "", 0),
+		entity("same", "platform", []string{"go"}, "A", 95),
+	}}
+	b.Entities[2].Lifecycle = "production"
+
+	d := Compare(a, b)
+
+	if len(d.Added) != 1 || d.Added[0].ID != "new" {
+		t.Errorf("Added = %v, want [new]", ids(d.Added))
+	}
+	if len(d.Removed) != 1 || d.Removed[0].ID != "gone" {
+		t.Errorf("Removed = %v, want [gone]", ids(d.Removed))
+	}
+	if len(d.Changed) != 3 {
+		t.Fatalf("Changed = %d entities, want 3 (billing, orders, search)", len(d.Changed))
+	}
+
+	billing, orders, search := d.Changed[0], d.Changed[1], d.Changed[2]
+	if orders.ID != "orders" || orders.Owner == nil || orders.Owner.To != "checkout" {
+		t.Errorf("orders owner = %+v, want payments → checkout", orders.Owner)
+	}
+	if !reflect.DeepEqual(orders.TagsAdded, []string{"grpc"}) || !reflect.DeepEqual(orders.TagsRemoved, []string{"legacy"}) {
+		t.Errorf("orders tags = +%v -%v, want +[grpc] -[legacy]", orders.TagsAdded, orders.TagsRemoved)
+	}
+	if orders.Grade == nil || !orders.Grade.Improved() || orders.Grade.ToScore != 92 {
+		t.Errorf("orders grade = %+v, want B → A (92)", orders.Grade)
+	}
+	if billing.Grade == nil || billing.Grade.Improved() || billing.Owner != nil {
+		t.Errorf("billing = %+v, want only a dropped grade", billing)
+	}
+	if search.Grade == nil || search.Grade.From != "" || !search.Grade.Improved() {
+		t.Errorf("search grade = %+v, want none → B as an improvement", search.Grade)
+	}
+	if len(search.Fields) != 1 || search.Fields[0].Field != "lifecycle" || search.Fields[0].To != "production" {
+		t.Errorf("search fields = %+v, want lifecycle → production", search.Fields)
+	}
+
+	want := DiffSummary{Added: 1, Removed: 1, Changed: 3, OwnerChanges: 1, TagChanges: 1, GradesImproved: 2, GradesDropped: 1}
+	if d.Summary != want {
+		t.Errorf("Summary = %+v, want %+v", d.Summary, want)
+	}
+}
+
+func TestCompare_Identical(t *testing.T) {
+	s := testSnapshot()
+	if d := Compare(s, testSnapshot()); !d.Empty() {
+		t.Errorf("Compare(s, s) = %+v, want empty", d)
+	}
+}
+
+func TestCompare_TagOrderIgnored(t *testing.T) {
+	a := &Snapshot{Entities: []*Entity{entity("x", "", []string{"a", "b"}, "", 0)}}
+	b := &Snapshot{Entities: []*Entity{entity("x", "", []string{"b", "a"}, "", 0)}}
+	if d := Compare(a, b); !d.Empty() {
+		t.Errorf("reordered tags reported as drift: %+v", d.Changed[0])
+	}
+}
+
+func ids(entities []*Entity) []string {
+	out := make([]string, len(entities))
+	for i, e := range entities {
+		out[i] = e.ID
+	}
+	return out
+}
+
+func TestGradeChange_Improved(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{"B", "A", true},
+		{"A", "B", false},
+		{"A", "A+", true},
+		{"A+", "A", false},
+		{"A-", "A", true},
+		{"B+", "A-", true},
+		{"A-", "B+", false},
+		{"B", "B+", true},
+		{"C+", "C-", false},
+		{"F", "E-", true},
+		{"", "F", true},
+		{"", "D-", true},
+		{"A+", "", false},
+		{"B", "?", false},
+		{"??", "C", true},
+		{"A*", "C", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from+"→"+tt.to, func(t *testing.T) {
+			g := &GradeChange{Change: Change{From: tt.from, To: tt.to}}
+			if got := g.Improved(); got != tt.want {
+				t.Errorf("Improved() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
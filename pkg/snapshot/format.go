@@ -0,0 +1,309 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Format is a snapshot file encoding
+type Format string
+
+// Supported snapshot formats
+const (
+	// FormatJSON is a single indented JSON document
+	FormatJSON Format = "json"
+	// FormatNDJSON is one JSON record per line: a header, then one line per
+	// entity, team, user and group, so large snapshots can be streamed and grepped.
+	FormatNDJSON Format = "ndjson"
+	// FormatTarball is a gzipped tar holding snapshot.json plus one
+	// manifests/<id>.yml per entity, ready for `shoehorn apply`.
+	FormatTarball Format = "tar.gz"
+)
+
+// tarball entry names
+const (
+	tarSnapshotName = "snapshot.json"
+	tarManifestDir  = "manifests"
+)
+
+// ParseFormat parses a --format value
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "json":
+		return FormatJSON, nil
+	case "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	case "tar.gz", "tgz", "tarball":
+		return FormatTarball, nil
+	}
+	return "", fmt.Errorf("unknown snapshot format %q (expected json, ndjson, or tar.gz)", s)
+}
+
+// FormatFromPath infers the format from a file extension, defaulting to JSON
+func FormatFromPath(name string) Format {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".ndjson"), strings.HasSuffix(lower, ".jsonl"):
+		return FormatNDJSON
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return FormatTarball
+	}
+	return FormatJSON
+}
+
+// ndjsonRecord is one line of an NDJSON snapshot
+type ndjsonRecord struct {
+	Kind string          `json:"kind"` // snapshot (header), entity, team, user, group
+	Data json.RawMessage `json:"data"`
+}
+
+// ndjsonHeader is the data of the first NDJSON record
+type ndjsonHeader struct {
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Profile   string    `json:"profile,omitempty"`
+	Server    string    `json:"server,omitempty"`
+}
+
+// Write encodes s to w in format f
+func Write(w io.Writer, s *Snapshot, f Format) error {
+	switch f {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(s)
+	case FormatNDJSON:
+		return writeNDJSON(w, s)
+	case FormatTarball:
+		return writeTarball(w, s)
+	}
+	return fmt.Errorf("unknown snapshot format %q", f)
+}
+
+func writeNDJSON(w io.Writer, s *Snapshot) error {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	write := func(kind string, v any) error {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", kind, err)
+		}
+		return enc.Encode(ndjsonRecord{Kind: kind, Data: data})
+	}
+
+	header := ndjsonHeader{Version: s.Version, CreatedAt: s.CreatedAt, Profile: s.Profile, Server: s.Server}
+	if err := write("snapshot", header); err != nil {
+		return err
+	}
+	for _, e := range s.Entities {
+		if err := write("entity", e); err != nil {
+			return err
+		}
+	}
+	for _, t := range s.Teams {
+		if err := write("team", t); err != nil {
+			return err
+		}
+	}
+	for _, u := range s.Users {
+		if err := write("user", u); err != nil {
+			return err
+		}
+	}
+	for _, g := range s.Groups {
+		if err := write("group", g); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+func writeTarball(w io.Writer, s *Snapshot) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	add := func(name string, data []byte) error {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(data)),
+			ModTime: s.CreatedAt,
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			return fmt.Errorf("write %s: %w", name, err)
+		}
+		return nil
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, s, FormatJSON); err != nil {
+		return err
+	}
+	if err := add(tarSnapshotName, buf.Bytes()); err != nil {
+		return err
+	}
+	for _, e := range s.Entities {
+		data, err := e.Manifest().Marshal()
+		if err != nil {
+			return fmt.Errorf("entity %s: %w", e.ID, err)
+		}
+		if err := add(path.Join(tarManifestDir, ManifestFileName(e.ID)), data); err != nil {
+			return err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// ManifestFileName returns a safe file name for an entity's manifest
+func ManifestFileName(id string) string {
+	r := strings.NewReplacer("/", "_", "\\", "_", ":", "_")
+	name := r.Replace(id)
+	if name == "" || strings.HasPrefix(name, ".") {
+		name = "_" + name
+	}
+	return name + ".yml"
+}
+
+// Read decodes a snapshot in any supported format, detected from its content
+func Read(r io.Reader) (*Snapshot, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+
+	var s *Snapshot
+	switch {
+	case len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b:
+		s, err = readTarball(data)
+	case isNDJSON(data):
+		s, err = readNDJSON(data)
+	default:
+		s = &Snapshot{}
+		if err = json.Unmarshal(data, s); err != nil {
+			err = fmt.Errorf("decode snapshot: %w", err)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.Version == 0 {
+		return nil, errors.New("not a Shoehorn catalog snapshot (missing version)")
+	}
+	if s.Version > Version {
+		return nil, fmt.Errorf("snapshot version %d is newer than this CLI supports (%d); upgrade shoehorn", s.Version, Version)
+	}
+	return s, nil
+}
+
+// isNDJSON reports whether data starts with an NDJSON snapshot header line
+func isNDJSON(data []byte) bool {
+	line, _, _ := bytes.Cut(bytes.TrimSpace(data), []byte("\n"))
+	var rec ndjsonRecord
+	return json.Unmarshal(line, &rec) == nil && rec.Kind == "snapshot"
+}
+
+func readNDJSON(data []byte) (*Snapshot, error) {
+	s := &Snapshot{}
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	sc.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for n := 1; sc.Scan(); n++ {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var rec ndjsonRecord
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+
+		var err error
+		switch rec.Kind {
+		case "snapshot":
+			var h ndjsonHeader
+			err = json.Unmarshal(rec.Data, &h)
+			s.Version, s.CreatedAt, s.Profile, s.Server = h.Version, h.CreatedAt, h.Profile, h.Server
+		case "entity":
+			s.Entities, err = appendRecord(s.Entities, rec.Data)
+		case "team":
+			s.Teams, err = appendRecord(s.Teams, rec.Data)
+		case "user":
+			s.Users, err = appendRecord(s.Users, rec.Data)
+		case "group":
+			s.Groups, err = appendRecord(s.Groups, rec.Data)
+		default:
+			// Records from newer CLIs are skipped rather than rejected
+		}
+		if err != nil {
+			return nil, fmt.Errorf("line %d (%s): %w", n, rec.Kind, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read snapshot: %w", err)
+	}
+	return s, nil
+}
+
+// appendRecord decodes data into a new *T and appends it to list
+func appendRecord[T any](list []*T, data json.RawMessage) ([]*T, error) {
+	v := new(T)
+	if err := json.Unmarshal(data, v); err != nil {
+		return list, err
+	}
+	return append(list, v), nil
+}
+
+func readTarball(data []byte) (*Snapshot, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("read snapshot tarball: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("snapshot tarball has no %s", tarSnapshotName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read snapshot tarball: %w", err)
+		}
+		if path.Clean(hdr.Name) != tarSnapshotName {
+			continue
+		}
+		s := &Snapshot{}
+		if err := json.NewDecoder(tr).Decode(s); err != nil {
+			return nil, fmt.Errorf("decode %s: %w", tarSnapshotName, err)
+		}
+		return s, nil
+	}
+}
+
+// ReadFile reads a snapshot from path, or from stdin when path is "-"
+func ReadFile(name string) (*Snapshot, error) {
+	if name == "-" {
+		return Read(os.Stdin)
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Read(f)
+}
@@ -0,0 +1,174 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+)
+
+func testSnapshot() *Snapshot {
+	return &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC),
+		Profile:   "prod",
+		Server:    "https://shoehorn.example.com",
+		Entities: []*Entity{
+			{
+				EntityDetail: api.EntityDetail{
+					Entity:    api.Entity{ID: "orders", Name: "Orders", Type: "service", Owner: "payments", Tags: []string{"go"}},
+					Lifecycle: "production",
+				},
+				Status:    &api.EntityStatus{Health: "healthy"},
+				Scorecard: &api.Scorecard{Score: 80, Grade: "B"},
+			},
+			{EntityDetail: api.EntityDetail{Entity: api.Entity{ID: "team/web", Name: "Web"}}},
+		},
+		Teams:  []*api.TeamDetail{{Team: api.Team{ID: "t1", Slug: "payments"}, Members: []api.TeamMember{{ID: "u1"}}}},
+		Users:  []*api.User{{ID: "u1", Email: "ada@example.com"}},
+		Groups: []*api.Group{{Name: "admins"}},
+	}
+}
+
+func TestWriteRead_RoundTrip(t *testing.T) {
+	for _, f := range []Format{FormatJSON, FormatNDJSON, FormatTarball} {
+		t.Run(string(f), func(t *testing.T) {
+			want := testSnapshot()
+			var buf bytes.Buffer
+			if err := Write(&buf, want, f); err != nil {
+				t.Fatalf("Write() = %v", err)
+			}
+			got, err := Read(&buf)
+			if err != nil {
+				t.Fatalf("Read() = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip mismatch:\n got %+v\nwant %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestWrite_NDJSONOneRecordPerLine(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testSnapshot(), FormatNDJSON); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	// header + 2 entities + 1 team + 1 user + 1 group
+	if len(lines) != 6 {
+		t.Fatalf("got %d lines, want 6:\n%s", len(lines), buf.String())
+	}
+	if !strings.HasPrefix(lines[0], `{"kind":"snapshot"`) {
+		t.Errorf("first line = %s, want snapshot header", lines[0])
+	}
+}
+
+func TestWrite_TarballHasManifests(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, testSnapshot(), FormatTarball); err != nil {
+		t.Fatalf("Write() = %v", err)
+	}
+	gz, err := gzip.NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr := tar.NewReader(gz)
+	files := map[string][]byte{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(tr)
+		files[hdr.Name] = data
+	}
+
+	for _, name := range []string{"snapshot.json", "manifests/orders.yml", "manifests/team_web.yml"} {
+		if _, ok := files[name]; !ok {
+			t.Errorf("tarball is missing %s (has %d files)", name, len(files))
+		}
+	}
+	m, err := manifest.Parse(files["manifests/orders.yml"])
+	if err != nil {
+		t.Fatalf("Parse(orders.yml) = %v", err)
+	}
+	if m.Service.ID != "orders" || m.OwnerID() != "payments" {
+		t.Errorf("manifest = %+v, want orders owned by payments", m)
+	}
+}
+
+func TestRead_RejectsVersions(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{"missing version", `{"entities":[]}`, "missing version"},
+		{"newer version", `{"version":99,"entities":[]}`, "newer than this CLI"},
+		{"newer ndjson", `{"kind":"snapshot","data":{"version":99}}`, "newer than this CLI"},
+		{"not json", `hello`, "decode snapshot"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Read(strings.NewReader(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Read() = %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRead_NDJSONSkipsUnknownKinds(t *testing.T) {
+	input := `{"kind":"snapshot","data":{"version":1}}
+{"kind":"plugin","data":{"anything":true}}
+{"kind":"group","data":{"name":"admins"}}
+`
+	s, err := Read(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Read() = %v", err)
+	}
+	if len(s.Groups) != 1 || s.Groups[0].Name != "admins" {
+		t.Errorf("groups = %+v, want [admins]", s.Groups)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]Format{
+		"":                    FormatJSON,
+		"catalog.json":        FormatJSON,
+		"catalog.ndjson":      FormatNDJSON,
+		"catalog.JSONL":       FormatNDJSON,
+		"catalog.tar.gz":      FormatTarball,
+		"backups/catalog.tgz": FormatTarball,
+	}
+	for name, want := range tests {
+		if got := FormatFromPath(name); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestManifestFileName(t *testing.T) {
+	tests := map[string]string{
+		"orders":        "orders.yml",
+		"team/web":      "team_web.yml",
+		"../etc/passwd": "_.._etc_passwd.yml",
+		"c:\\x":         "c__x.yml",
+	}
+	for id, want := range tests {
+		if got := ManifestFileName(id); got != want {
+			t.Errorf("ManifestFileName(%q) = %q, want %q", id, got, want)
+		}
+	}
+}
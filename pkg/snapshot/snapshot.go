@@ -0,0 +1,220 @@
+// Package snapshot captures the catalog of a Shoehorn profile — entities with
+// their resources, status and scorecards, teams, users and groups — in one
+// versioned document for audits, disaster-recovery drills and test seeding.
+package snapshot
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+	"github.com/shoehorn-dev/cli/pkg/manifest"
+)
+
+// Version is the snapshot format version written by this CLI
+const Version = 1
+
+// DefaultConcurrency is the number of entities fetched in parallel by Export
+const DefaultConcurrency = 8
+
+// Snapshot is a point-in-time copy of a catalog
+type Snapshot struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	Profile   string            `json:"profile,omitempty"`
+	Server    string            `json:"server,omitempty"`
+	Entities  []*Entity         `json:"entities"`
+	Teams     []*api.TeamDetail `json:"teams"`
+	Users     []*api.User       `json:"users"`
+	Groups    []*api.Group      `json:"groups"`
+}
+
+// Entity is an entity with the sub-resources that are fetched separately
+type Entity struct {
+	api.EntityDetail
+	Resources []*api.Resource   `json:"resources,omitempty"`
+	Status    *api.EntityStatus `json:"status,omitempty"`
+	Scorecard *api.Scorecard    `json:"scorecard,omitempty"`
+}
+
+// Manifest returns the Shoehorn manifest that recreates the entity
+func (e *Entity) Manifest() *manifest.Manifest {
+	return manifest.FromEntity(&e.EntityDetail)
+}
+
+// ExportOptions controls how Export walks the catalog
+type ExportOptions struct {
+	Concurrency  int                   // parallel entity fetches; 0 uses DefaultConcurrency
+	Progress     func(done, total int) // called after each entity; may be nil
+	EntitiesOnly bool                  // skip teams, users and groups
+}
+
+// Export walks every page of the catalog through c. Sub-resources that the
+// server does not have for an entity (404) are left empty; any other error
+// aborts the export rather than produce a silently incomplete snapshot. The
+// cache of c is bypassed, so that a snapshot never holds stale data; offline,
+// Export fails.
+func Export(ctx context.Context, c *api.Client, opts ExportOptions) (*Snapshot, error) {
+	c.BypassCache()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	all := api.PageOptions{}
+	summaries, _, err := c.ListEntities(ctx, api.ListEntitiesOpts{PageOptions: all})
+	if err != nil {
+		return nil, fmt.Errorf("list entities: %w", err)
+	}
+	var (
+		teams  []*api.Team
+		users  []*api.User
+		groups []*api.Group
+	)
+	if !opts.EntitiesOnly {
+		if teams, _, err = c.ListTeams(ctx, all); err != nil {
+			return nil, fmt.Errorf("list teams: %w", err)
+		}
+		if users, _, err = c.ListUsers(ctx, all); err != nil {
+			return nil, fmt.Errorf("list users: %w", err)
+		}
+		if groups, _, err = c.ListGroups(ctx, all); err != nil {
+			return nil, fmt.Errorf("list groups: %w", err)
+		}
+	}
+
+	snap := &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Entities:  make([]*Entity, len(summaries)),
+		Teams:     make([]*api.TeamDetail, len(teams)),
+		Users:     users,
+		Groups:    groups,
+	}
+
+	workers := opts.Concurrency
+	if workers <= 0 {
+		workers = DefaultConcurrency
+	}
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	jobs := make(chan func() error)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				if err := job(); err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	total := len(summaries) + len(teams)
+	progress := func() {
+		if opts.Progress == nil {
+			return
+		}
+		mu.Lock()
+		done++
+		n := done
+		mu.Unlock()
+		opts.Progress(n, total)
+	}
+
+	// submit hands a job to the pool; false means the export was cancelled
+	submit := func(job func() error) bool {
+		select {
+		case jobs <- job:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	for i, s := range summaries {
+		ok := submit(func() error {
+			e, err := fetchEntity(ctx, c, s.ID)
+			if err != nil {
+				return err
+			}
+			snap.Entities[i] = e
+			progress()
+			return nil
+		})
+		if !ok {
+			break
+		}
+	}
+	for i, t := range teams {
+		ok := submit(func() error {
+			ref := cmp.Or(t.Slug, t.ID)
+			detail, err := c.GetTeam(ctx, ref)
+			if err != nil {
+				return fmt.Errorf("team %s: %w", ref, err)
+			}
+			snap.Teams[i] = detail
+			progress()
+			return nil
+		})
+		if !ok {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	snap.Sort()
+	return snap, nil
+}
+
+// fetchEntity loads an entity and its sub-resources
+func fetchEntity(ctx context.Context, c *api.Client, id string) (*Entity, error) {
+	detail, err := c.GetEntity(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("entity %s: %w", id, err)
+	}
+	e := &Entity{EntityDetail: *detail}
+
+	if e.Resources, err = c.GetEntityResources(ctx, id); err != nil && !api.IsNotFound(err) {
+		return nil, fmt.Errorf("entity %s resources: %w", id, err)
+	}
+	if e.Status, err = c.GetEntityStatus(ctx, id); err != nil && !api.IsNotFound(err) {
+		return nil, fmt.Errorf("entity %s status: %w", id, err)
+	}
+	if e.Scorecard, err = c.GetEntityScorecard(ctx, id); err != nil && !api.IsNotFound(err) {
+		return nil, fmt.Errorf("entity %s scorecard: %w", id, err)
+	}
+	return e, nil
+}
+
+// Sort orders every collection by ID (or name) so that snapshots of the same
+// catalog are byte-for-byte identical and diff cleanly.
+func (s *Snapshot) Sort() {
+	slices.SortFunc(s.Entities, func(a, b *Entity) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(s.Teams, func(a, b *api.TeamDetail) int { return cmp.Compare(a.Slug, b.Slug) })
+	slices.SortFunc(s.Users, func(a, b *api.User) int { return cmp.Compare(a.ID, b.ID) })
+	slices.SortFunc(s.Groups, func(a, b *api.Group) int { return cmp.Compare(a.Name, b.Name) })
+}
@@ -0,0 +1,150 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+// newCatalogServer serves a small catalog: entities svc-0..svc-(n-1), where
+// odd-numbered entities have no scorecard (404), plus one team, user and group.
+// failEntity, when set, makes that entity's detail request fail with a 500.
+func newCatalogServer(t *testing.T, n int, failEntity string, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		p := r.URL.Path
+		switch {
+		case p == "/api/v1/entities":
+			var items []string
+			for i := n - 1; i >= 0; i-- {
+				items = append(items, fmt.Sprintf(`{"service":{"id":"svc-%d","name":"Service %d","type":"service"}}`, i, i))
+			}
+			fmt.Fprintf(w, `{"entities":[%s],"page":{"total":%d}}`, strings.Join(items, ","), n)
+		case strings.HasSuffix(p, "/resources"):
+			fmt.Fprint(w, `{"resources":[{"id":"db","name":"orders-db","type":"postgres"}]}`)
+		case strings.HasSuffix(p, "/status"):
+			fmt.Fprint(w, `{"health":"healthy","uptime":99.9}`)
+		case strings.HasSuffix(p, "/scorecard"):
+			var i int
+			fmt.Sscanf(strings.TrimPrefix(p, "/api/v1/entities/svc-"), "%d", &i)
+			if i%2 == 1 {
+				http.Error(w, `{"error":"no scorecard"}`, http.StatusNotFound)
+				return
+			}
+			fmt.Fprint(w, `{"score":80,"grade":"B","max_score":100}`)
+		case strings.HasPrefix(p, "/api/v1/entities/"):
+			id := strings.TrimPrefix(p, "/api/v1/entities/")
+			if id == failEntity {
+				http.Error(w, `{"error":"boom"}`, http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprintf(w, `{"entity":{"service":{"id":%q,"name":"Service","type":"service","tier":"1"},
+				"owner":[{"type":"team","id":"payments"}],"tags":["go"],"lifecycle":"production",
+				"links":[{"name":"Runbook","url":"https://runbooks.example.com"}]}}`, id)
+		case p == "/api/v1/teams":
+			fmt.Fprint(w, `{"teams":[{"id":"t1","name":"Payments","slug":"payments"}],"total":1}`)
+		case p == "/api/v1/teams/payments":
+			fmt.Fprint(w, `{"team":{"id":"t1","name":"Payments","slug":"payments"},"members":[{"id":"u1","email":"ada@example.com","role":"lead"}]}`)
+		case p == "/api/v1/users":
+			fmt.Fprint(w, `{"items":[{"id":"u1","email":"ada@example.com","username":"ada"}],"total":1}`)
+		case p == "/api/v1/groups":
+			fmt.Fprint(w, `{"items":[{"name":"admins"}]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newTestClient(url string) *api.Client {
+	c := api.NewClient(url)
+	c.SetRetryPolicy(api.RetryPolicy{})
+	return c
+}
+
+func TestExport(t *testing.T) {
+	var requests atomic.Int32
+	server := newCatalogServer(t, 5, "", &requests)
+
+	var progressCalls atomic.Int32
+	snap, err := Export(context.Background(), newTestClient(server.URL), ExportOptions{
+		Concurrency: 3,
+		Progress:    func(done, total int) { progressCalls.Add(1) },
+	})
+	if err != nil {
+		t.Fatalf("Export() = %v", err)
+	}
+
+	if snap.Version != Version {
+		t.Errorf("Version = %d, want %d", snap.Version, Version)
+	}
+	if len(snap.Entities) != 5 || len(snap.Teams) != 1 || len(snap.Users) != 1 || len(snap.Groups) != 1 {
+		t.Fatalf("got %d entities, %d teams, %d users, %d groups; want 5, 1, 1, 1",
+			len(snap.Entities), len(snap.Teams), len(snap.Users), len(snap.Groups))
+	}
+	if progressCalls.Load() != 6 {
+		t.Errorf("progress called %d times, want 6 (5 entities + 1 team)", progressCalls.Load())
+	}
+	for i, e := range snap.Entities {
+		if want := fmt.Sprintf("svc-%d", i); e.ID != want {
+			t.Errorf("Entities[%d] = %q, want %q (sorted)", i, e.ID, want)
+		}
+		if len(e.Resources) != 1 || e.Status == nil {
+			t.Errorf("%s: resources=%d status=%v, want both fetched", e.ID, len(e.Resources), e.Status)
+		}
+		if wantScorecard := i%2 == 0; (e.Scorecard != nil) != wantScorecard {
+			t.Errorf("%s: scorecard = %v, want present=%v", e.ID, e.Scorecard, wantScorecard)
+		}
+	}
+	if got := snap.Teams[0]; got.Slug != "payments" || len(got.Members) != 1 {
+		t.Errorf("team = %+v, want payments with 1 member", got)
+	}
+}
+
+func TestExport_FailsOnEntityError(t *testing.T) {
+	var requests atomic.Int32
+	server := newCatalogServer(t, 50, "svc-3", &requests)
+
+	_, err := Export(context.Background(), newTestClient(server.URL), ExportOptions{Concurrency: 2})
+	if err == nil {
+		t.Fatal("Export() = nil, want error for svc-3")
+	}
+	if !strings.Contains(err.Error(), "svc-3") {
+		t.Errorf("error = %q, want it to name svc-3", err)
+	}
+}
+
+func TestEntity_Manifest(t *testing.T) {
+	e := &Entity{EntityDetail: api.EntityDetail{
+		Entity: api.Entity{
+			ID:          "orders",
+			Name:        "Orders",
+			Type:        "service",
+			Owner:       "payments",
+			Description: "Order API",
+			Tags:        []string{"go"},
+		},
+		Links:     []api.EntityLink{{Title: "Runbook", URL: "https://runbooks.example.com"}},
+		Lifecycle: "production",
+		Tier:      "1",
+	}}
+
+	m := e.Manifest()
+	if m.Service.ID != "orders" || m.Service.Tier != "1" || m.Lifecycle != "production" {
+		t.Errorf("manifest = %+v, want service orders, tier 1, production", m)
+	}
+	if m.OwnerID() != "payments" || m.Owner[0].Type != "team" {
+		t.Errorf("owner = %+v, want team payments", m.Owner)
+	}
+	if len(m.Links) != 1 || m.Links[0].Name != "Runbook" {
+		t.Errorf("links = %+v, want Runbook", m.Links)
+	}
+}
@@ -0,0 +1,312 @@
+package tui
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrFormCancelled is returned by RunForm when the user quits the form
+var ErrFormCancelled = errors.New("cancelled")
+
+// FieldKind selects the widget a form field is edited with
+type FieldKind int
+
+// Form field widgets
+const (
+	FieldText    FieldKind = iota // free text
+	FieldNumber                   // text that must parse as a number
+	FieldInteger                  // text that must parse as a whole number
+	FieldBool                     // yes/no toggle
+	FieldSelect                   // one of Options
+	FieldJSON                     // text that must be valid JSON (arrays and objects)
+)
+
+// FormField is one question in a form
+type FormField struct {
+	Name        string
+	Description string
+	Kind        FieldKind
+	Required    bool
+	Default     string   // pre-filled answer; "true" or "false" for FieldBool
+	Options     []string // choices for FieldSelect
+}
+
+// validate checks an answer for the field
+func (f FormField) validate(answer string) error {
+	if answer == "" {
+		if f.Required {
+			return errors.New("required")
+		}
+		return nil
+	}
+	switch f.Kind {
+	case FieldNumber:
+		if _, err := strconv.ParseFloat(answer, 64); err != nil {
+			return errors.New("must be a number")
+		}
+	case FieldInteger:
+		if _, err := strconv.ParseInt(answer, 10, 64); err != nil {
+			return errors.New("must be a whole number")
+		}
+	case FieldJSON:
+		if !json.Valid([]byte(answer)) {
+			return errors.New("must be valid JSON")
+		}
+	}
+	return nil
+}
+
+// options returns the choices for a select field, with an empty choice first
+// when the field may be left unanswered
+func (f FormField) options() []string {
+	if f.Required {
+		return f.Options
+	}
+	return append([]string{""}, f.Options...)
+}
+
+// formModel is a bubbletea model asking the fields of a form one at a time
+type formModel struct {
+	title    string
+	fields   []FormField
+	answers  []string
+	index    int
+	input    textinput.Model
+	choice   int // selected option (FieldSelect) or 0 = yes, 1 = no (FieldBool)
+	err      string
+	done     bool
+	canceled bool
+}
+
+func newFormModel(title string, fields []FormField) formModel {
+	m := formModel{title: title, fields: fields, answers: make([]string, len(fields))}
+	for i, f := range fields {
+		m.answers[i] = f.Default
+	}
+	m.input = textinput.New()
+	m.input.Prompt = "> "
+	m.input.PromptStyle = WarnStyle
+	m.load()
+	return m
+}
+
+// load sets the widget up for the current field from its answer
+func (m *formModel) load() {
+	m.err = ""
+	f := m.fields[m.index]
+	answer := m.answers[m.index]
+	switch f.Kind {
+	case FieldBool:
+		m.choice = 1
+		if b, err := strconv.ParseBool(answer); err == nil && b {
+			m.choice = 0
+		}
+	case FieldSelect:
+		m.choice = max(slices.Index(f.options(), answer), 0)
+	default:
+		m.input.SetValue(answer)
+		m.input.CursorEnd()
+		m.input.Focus()
+	}
+}
+
+// current returns the answer the widget holds for the current field
+func (m formModel) current() string {
+	f := m.fields[m.index]
+	switch f.Kind {
+	case FieldBool:
+		return strconv.FormatBool(m.choice == 0)
+	case FieldSelect:
+		if opts := f.options(); len(opts) > 0 {
+			return opts[m.choice]
+		}
+		return ""
+	}
+	return strings.TrimSpace(m.input.Value())
+}
+
+func (m formModel) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (m formModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return m, cmd
+	}
+
+	f := m.fields[m.index]
+	switch key.String() {
+	case "ctrl+c", "esc":
+		m.canceled = true
+		return m, tea.Quit
+	case "enter", "tab":
+		answer := m.current()
+		if err := f.validate(answer); err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		m.answers[m.index] = answer
+		if m.index == len(m.fields)-1 {
+			m.done = true
+			return m, tea.Quit
+		}
+		m.index++
+		m.load()
+		return m, nil
+	case "shift+tab":
+		if m.index > 0 {
+			m.answers[m.index] = m.current()
+			m.index--
+			m.load()
+		}
+		return m, nil
+	}
+
+	switch f.Kind {
+	case FieldBool:
+		switch key.String() {
+		case "left", "right", "up", "down", "h", "l", " ":
+			m.choice = 1 - m.choice
+		case "y":
+			m.choice = 0
+		case "n":
+			m.choice = 1
+		}
+		return m, nil
+	case FieldSelect:
+		n := len(f.options())
+		switch key.String() {
+		case "up", "k":
+			m.choice = (m.choice - 1 + n) % n
+		case "down", "j":
+			m.choice = (m.choice + 1) % n
+		}
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	m.err = ""
+	return m, cmd
+}
+
+func (m formModel) View() string {
+	if m.done || m.canceled {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString("\n  " + TitleStyle.Render(m.title) + "\n\n")
+	width := 0
+	for _, f := range m.fields {
+		width = max(width, len(f.Name))
+	}
+
+	for i, f := range m.fields {
+		name := fmt.Sprintf("%-*s", width, f.Name)
+		switch {
+		case i < m.index:
+			b.WriteString(fmt.Sprintf("  %s %s  %s\n", SuccessStyle.Render("✓"), name, displayAnswer(m.answers[i])))
+		case i > m.index:
+			b.WriteString(fmt.Sprintf("  %s %s\n", MutedStyle.Render("○"), MutedStyle.Render(name)))
+		default:
+			label := f.Name
+			if f.Required {
+				label += ErrorStyle.Render(" *")
+			}
+			b.WriteString(fmt.Sprintf("  %s %s\n", WarnStyle.Render("▸"), HeaderStyle.Render(label)))
+			if f.Description != "" {
+				b.WriteString("    " + MutedStyle.Render(f.Description) + "\n")
+			}
+			b.WriteString(m.widgetView(f))
+			if m.err != "" {
+				b.WriteString("    " + ErrorStyle.Render(m.err) + "\n")
+			}
+		}
+	}
+
+	help := "enter next · shift+tab back · esc cancel"
+	switch f := m.fields[m.index]; f.Kind {
+	case FieldBool:
+		help = "←/→ or y/n choose · " + help
+	case FieldSelect:
+		help = "↑/↓ choose · " + help
+	}
+	b.WriteString("\n  " + MutedStyle.Render(help) + "\n")
+	return b.String()
+}
+
+func (m formModel) widgetView(f FormField) string {
+	switch f.Kind {
+	case FieldBool:
+		yes, no := MutedStyle.Render("Yes"), MutedStyle.Render("No")
+		if m.choice == 0 {
+			yes = SelectedStyle.Render(" Yes ")
+		} else {
+			no = SelectedStyle.Render(" No ")
+		}
+		return "    " + yes + "  " + no + "\n"
+	case FieldSelect:
+		var b strings.Builder
+		for i, opt := range f.options() {
+			if opt == "" {
+				opt = "(none)"
+			}
+			if i == m.choice {
+				b.WriteString("    " + WarnStyle.Render("> "+opt) + "\n")
+			} else {
+				b.WriteString("      " + opt + "\n")
+			}
+		}
+		return b.String()
+	}
+	return "    " + m.input.View() + "\n"
+}
+
+func displayAnswer(answer string) string {
+	if answer == "" {
+		return MutedStyle.Render("—")
+	}
+	if i := strings.IndexByte(answer, '\n'); i >= 0 {
+		answer = answer[:i] + "…"
+	}
+	return answer
+}
+
+// RunForm asks each field in turn, with defaults pre-filled and answers
+// checked against the field's kind, and returns the non-empty answers by
+// field name. It returns ErrFormCancelled if the user quits with Esc or
+// Ctrl+C. Callers must check that a terminal is available first.
+func RunForm(title string, fields []FormField) (map[string]string, error) {
+	if len(fields) == 0 {
+		return map[string]string{}, nil
+	}
+	final, err := tea.NewProgram(newFormModel(title, fields)).Run()
+	if err != nil {
+		return nil, fmt.Errorf("form: %w", err)
+	}
+	fm, ok := final.(formModel)
+	if !ok {
+		return nil, fmt.Errorf("form: unexpected final model type %T", final)
+	}
+	if fm.canceled {
+		return nil, ErrFormCancelled
+	}
+	answers := map[string]string{}
+	for i, f := range fields {
+		if fm.answers[i] != "" {
+			answers[f.Name] = fm.answers[i]
+		}
+	}
+	return answers, nil
+}
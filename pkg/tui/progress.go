@@ -0,0 +1,195 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Step is one step of an operation shown by RunProgress
+type Step struct {
+	Name   string
+	Status string
+	Detail string // shown after the status, e.g. an error
+}
+
+// Progress is an update to an operation shown by RunProgress: its overall
+// status and steps, and log lines written since the previous update
+type Progress struct {
+	Status string
+	Steps  []Step
+	Logs   []string
+}
+
+// progressMsg carries an update from the watch function
+type progressMsg Progress
+
+// watchDoneMsg carries the watch function's result
+type watchDoneMsg struct{ err error }
+
+// progressModel is a bubbletea model listing the steps of an operation, with
+// its log lines printed above the list as they arrive
+type progressModel struct {
+	spinner  spinner.Model
+	title    string
+	status   string
+	steps    []Step
+	done     bool
+	err      error
+	canceled bool
+}
+
+func (m progressModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
+
+func (m progressModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case progressMsg:
+		if msg.Status != "" {
+			m.status = msg.Status
+		}
+		if msg.Steps != nil {
+			m.steps = msg.Steps
+		}
+		if len(msg.Logs) > 0 {
+			return m, tea.Println(strings.Join(msg.Logs, "\n"))
+		}
+	case watchDoneMsg:
+		m.done = true
+		m.err = msg.err
+		return m, tea.Quit
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			m.done = true
+			m.canceled = true
+			return m, tea.Quit
+		}
+	case spinner.TickMsg:
+		var cmd tea.Cmd
+		m.spinner, cmd = m.spinner.Update(msg)
+		return m, cmd
+	}
+	return m, nil
+}
+
+func (m progressModel) View() string {
+	var b strings.Builder
+	running := m.spinner.View()
+	icon := running
+	if m.done {
+		running = ""
+		icon = stepIcon(m.status, "")
+	}
+	b.WriteString(fmt.Sprintf("  %s %s", icon, TitleStyle.Render(m.title)))
+	if m.status != "" {
+		b.WriteString("  " + StatusColor(m.status).Render(m.status))
+	}
+	b.WriteString("\n")
+	for _, s := range m.steps {
+		b.WriteString(fmt.Sprintf("    %s %s", stepIcon(s.Status, running), s.Name))
+		if s.Detail != "" {
+			b.WriteString("  " + MutedStyle.Render(s.Detail))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// stepIcon returns the marker for a step status; running is shown while a
+// step is in progress
+func stepIcon(status, running string) string {
+	switch status {
+	case "completed", "succeeded", "success":
+		return SuccessStyle.Render("✓")
+	case "failed", "error":
+		return ErrorStyle.Render("✗")
+	case "rolled_back":
+		return WarnStyle.Render("↺")
+	case "cancelled", "skipped":
+		return MutedStyle.Render("–")
+	case "executing", "running":
+		if running != "" {
+			return running
+		}
+		return WarnStyle.Render("•")
+	}
+	return MutedStyle.Render("○")
+}
+
+// RunProgress runs watch in the background, which reports the operation's
+// progress through update until it returns. The steps are rendered as a live
+// list with log lines scrolling above it; without a TTY, or in plain mode,
+// status changes and log lines are printed line by line instead.
+//
+// Ctrl+C stops watching and returns context.Canceled; the watch function's
+// context is cancelled, the operation itself is not.
+func RunProgress(ctx context.Context, title string, watch func(ctx context.Context, update func(Progress)) error) error {
+	if plainMode || !isTTY() {
+		return runPlainProgress(ctx, os.Stdout, title, watch)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("220"))
+	p := tea.NewProgram(progressModel{spinner: s, title: title})
+
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		err := watch(ctx, func(u Progress) { p.Send(progressMsg(u)) })
+		p.Send(watchDoneMsg{err: err})
+	}()
+
+	final, err := p.Run()
+	cancel()
+	<-finished
+	if err != nil {
+		return fmt.Errorf("progress: %w", err)
+	}
+	fm, ok := final.(progressModel)
+	if !ok {
+		return fmt.Errorf("progress: unexpected final model type %T", final)
+	}
+	if fm.canceled {
+		return context.Canceled
+	}
+	return fm.err
+}
+
+// runPlainProgress prints the title, then each status or step change and
+// every log line as it arrives
+func runPlainProgress(ctx context.Context, w io.Writer, title string, watch func(ctx context.Context, update func(Progress)) error) error {
+	fmt.Fprintln(w, title)
+	var status string
+	seen := map[string]string{}
+	return watch(ctx, func(u Progress) {
+		for _, line := range u.Logs {
+			fmt.Fprintln(w, line)
+		}
+		for _, s := range u.Steps {
+			if seen[s.Name] == s.Status {
+				continue
+			}
+			seen[s.Name] = s.Status
+			line := fmt.Sprintf("  step %s: %s", s.Name, s.Status)
+			if s.Detail != "" {
+				line += " (" + s.Detail + ")"
+			}
+			fmt.Fprintln(w, line)
+		}
+		if u.Status != "" && u.Status != status {
+			status = u.Status
+			fmt.Fprintf(w, "status: %s\n", status)
+		}
+	})
+}
@@ -41,6 +41,16 @@ func DetectMode(interactive bool, noInteractive bool, outputFormat string) Outpu
 	return ModePlain
 }
 
+// CanPrompt reports whether the user can be asked for input: stdin and stdout
+// are terminals, output is not JSON or YAML, and neither --no-interactive nor
+// a CI environment rules prompting out.
+func CanPrompt(noInteractive bool, outputFormat string) bool {
+	if noInteractive || outputFormat == "json" || outputFormat == "yaml" || isCIEnvironment() {
+		return false
+	}
+	return isTerminal() && term.IsTerminal(int(os.Stdin.Fd()))
+}
+
 // IsInteractive returns true if the mode supports interactive features
 func IsInteractive(mode OutputMode) bool {
 	return mode == ModeInteractive
@@ -1,9 +1,14 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net"
+	"net/http"
 	"os"
-	"strings"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
 )
 
 // Exit codes for the CLI
@@ -15,52 +20,106 @@ const (
 	ExitValidation   = 4 // Validation error
 	ExitTimeout      = 5 // Operation timeout
 	ExitCancelled    = 6 // User cancelled operation
+	ExitRunFailed    = 7 // Forge run failed
+	ExitRolledBack   = 8 // Forge run failed and was rolled back
 )
 
-// Exit terminates the program with the given exit code
-func Exit(code int) {
-	os.Exit(code)
+// exitCodeError attaches an explicit exit code to an error
+type exitCodeError struct {
+	code int
+	err  error
 }
 
-// ExitWithError prints the error and exits with appropriate code
-func ExitWithError(err error) {
+func (e *exitCodeError) Error() string { return e.err.Error() }
+func (e *exitCodeError) Unwrap() error { return e.err }
+
+// WithExitCode wraps err so that ExitWithError exits with code, for failures
+// that are not API errors (e.g. local validation or a declined confirmation).
+func WithExitCode(code int, err error) error {
 	if err == nil {
-		Exit(ExitSuccess)
-		return
+		return nil
 	}
+	return &exitCodeError{code: code, err: err}
+}
 
-	// Print error to stderr
-	RenderError(err)
+// reportedError marks an error the command has already displayed
+type reportedError struct {
+	err error
+}
 
-	// Determine exit code based on error message
-	errMsg := strings.ToLower(err.Error())
+func (e *reportedError) Error() string { return e.err.Error() }
+func (e *reportedError) Unwrap() error { return e.err }
 
-	switch {
-	case strings.Contains(errMsg, "not authenticated"),
-		strings.Contains(errMsg, "authentication required"),
-		strings.Contains(errMsg, "401"):
-		Exit(ExitAuthRequired)
+// Reported marks err as already shown to the user (e.g. in an error box), so
+// ExitWithError sets the exit code without printing it again.
+func Reported(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &reportedError{err: err}
+}
 
-	case strings.Contains(errMsg, "not found"),
-		strings.Contains(errMsg, "404"):
-		Exit(ExitNotFound)
+// ExitCode returns the exit code for err
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitSuccess
+	}
 
-	case strings.Contains(errMsg, "validation"),
-		strings.Contains(errMsg, "invalid"),
-		strings.Contains(errMsg, "400"):
-		Exit(ExitValidation)
+	var codeErr *exitCodeError
+	if errors.As(err, &codeErr) {
+		return codeErr.code
+	}
 
-	case strings.Contains(errMsg, "timeout"),
-		strings.Contains(errMsg, "deadline exceeded"):
-		Exit(ExitTimeout)
+	var apiErr *api.APIError
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return ExitAuthRequired
+		case http.StatusNotFound:
+			return ExitNotFound
+		case http.StatusBadRequest, http.StatusUnprocessableEntity:
+			return ExitValidation
+		case http.StatusRequestTimeout, http.StatusGatewayTimeout:
+			return ExitTimeout
+		default:
+			return ExitError
+		}
+	}
 
-	case strings.Contains(errMsg, "cancelled"),
-		strings.Contains(errMsg, "canceled"):
-		Exit(ExitCancelled)
+	if errors.Is(err, api.ErrNotAuthenticated) {
+		return ExitAuthRequired
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return ExitTimeout
+	}
+
+	if errors.Is(err, context.Canceled) {
+		return ExitCancelled
+	}
+
+	return ExitError
+}
+
+// Exit terminates the program with the given exit code
+func Exit(code int) {
+	os.Exit(code)
+}
+
+// ExitWithError prints the error and exits with the code from ExitCode
+func ExitWithError(err error) {
+	if err == nil {
+		Exit(ExitSuccess)
+		return
+	}
 
-	default:
-		Exit(ExitError)
+	// Print error to stderr unless the command already displayed it
+	var reported *reportedError
+	if !errors.As(err, &reported) {
+		RenderError(err)
 	}
+	Exit(ExitCode(err))
 }
 
 // ExitWithMessage prints a message and exits with the given code
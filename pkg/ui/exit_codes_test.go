@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/shoehorn-dev/cli/pkg/api"
+)
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"nil", nil, ExitSuccess},
+		{"unauthorized", &api.APIError{StatusCode: 401}, ExitAuthRequired},
+		{"forbidden", &api.APIError{StatusCode: 403}, ExitAuthRequired},
+		{"not found", fmt.Errorf("get entity: %w", &api.APIError{StatusCode: 404}), ExitNotFound},
+		{"bad request", &api.APIError{StatusCode: 400}, ExitValidation},
+		{"unprocessable", &api.APIError{StatusCode: 422}, ExitValidation},
+		{"gateway timeout", &api.APIError{StatusCode: 504}, ExitTimeout},
+		{"server error", &api.APIError{StatusCode: 500}, ExitError},
+		{"not authenticated", fmt.Errorf("load: %w", api.ErrNotAuthenticated), ExitAuthRequired},
+		{"deadline", fmt.Errorf("do request: %w", context.DeadlineExceeded), ExitTimeout},
+		{"canceled", context.Canceled, ExitCancelled},
+		{"explicit code", WithExitCode(ExitValidation, errors.New("2 files invalid")), ExitValidation},
+		{"reported", Reported(&api.APIError{StatusCode: 404}), ExitNotFound},
+		// Text alone no longer decides the exit code
+		{"message mentions 404", errors.New("profile '404' not found"), ExitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExitCode(tt.err); got != tt.want {
+				t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}